@@ -3,8 +3,261 @@ package v1alpha1
 import (
 	"github.com/go-errors/errors"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"path"
+	"strings"
 )
 
+// Annotations that request a one-off action on an XJoinIndex or XJoinDataSource.
+// The operator removes each annotation once the action has been handled.
+const (
+	RefreshAnnotation           = "xjoin.cloud.redhat.com/refresh"
+	PromoteRefreshingAnnotation = "xjoin.cloud.redhat.com/promote-refreshing"
+	RollbackAnnotation          = "xjoin.cloud.redhat.com/rollback"
+	ApproveRefreshingAnnotation = "xjoin.cloud.redhat.com/approve-refreshing"
+)
+
+// Database types of an XJoinDataSource, each is read by the matching Debezium source connector
+const (
+	DatabaseTypePostgres  = "postgres"
+	DatabaseTypeMySQL     = "mysql"
+	DatabaseTypeSQLServer = "sqlserver"
+)
+
+// Refresh strategies of an XJoinDataSource
+const (
+	RefreshStrategyRecreate            = "Recreate"
+	RefreshStrategyIncrementalSnapshot = "IncrementalSnapshot"
+)
+
+type RefreshPolicy struct {
+	// RequireApproval waits for the approve-refreshing annotation before replacing the active version
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// Strategy is how an XJoinDataSource is refreshed, it is ignored by XJoinIndex.
+	// Recreate (the default) creates a new XJoinDataSourcePipeline. IncrementalSnapshot re-reads the table through
+	// the active pipeline's Debezium connector when a refresh is requested or the active version fails validation.
+	// +kubebuilder:validation:Enum=Recreate;IncrementalSnapshot
+	// +optional
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// DataSourceSource is where the records of an XJoinDataSource are read from.
+// A database table read by a Debezium connector is used when no source is set.
+type DataSourceSource struct {
+	// +optional
+	KafkaTopic *KafkaTopicSource `json:"kafkaTopic,omitempty"`
+}
+
+// KafkaTopicSource is an existing Kafka topic whose values are Avro records registered in the schema registry.
+// The operator does not create or delete the topic or its subject.
+type KafkaTopicSource struct {
+	// +kubebuilder:validation:MinLength=1
+	Topic string `json:"topic"`
+
+	// +optional
+	Subject string `json:"subject,omitempty"` //value schema subject of the topic, <topic>-value when empty
+}
+
+// ValueSubject is the subject of the topic's value schema
+func (in *KafkaTopicSource) ValueSubject() string {
+	if in.Subject != "" {
+		return in.Subject
+	}
+	return in.Topic + "-value"
+}
+
+// RowFilter selects the rows of a datasource's table that are sent to Kafka, e.g. to leave out soft-deleted rows
+type RowFilter struct {
+	// Where is a SQL condition on the table's columns, e.g. "deleted_at IS NULL". It filters the connector's
+	// snapshots, the incremental snapshots and the queries of the validation pods.
+	// +kubebuilder:validation:MinLength=1
+	Where string `json:"where"`
+
+	// Condition is the Groovy condition of a Debezium filter SMT that drops the streamed changes of filtered rows,
+	// e.g. "value.deleted_at == null". Only snapshots are filtered when it is empty.
+	// +optional
+	Condition string `json:"condition,omitempty"`
+
+	// Columns are the columns used by Condition that are not fields of the avroSchema, e.g. "deleted_at". They are
+	// added to the connector's column.include.list so the condition can read them. Every column is captured when
+	// Condition is set without Columns.
+	// +optional
+	Columns []string `json:"columns,omitempty"`
+}
+
+// WorkloadSpec configures the pods of a workload created by the operator, e.g. the xjoin-core deployment.
+// Fields that are not set use the defaults from the xjoin-generic ConfigMap.
+type WorkloadSpec struct {
+	// +optional
+	Image string `json:"image,omitempty"` //image repository without the tag, e.g. quay.io/cloudservices/xjoin-core
+
+	// +optional
+	Tag string `json:"tag,omitempty"` //image tag or digest, e.g. latest or sha256:<digest>
+
+	// +optional
+	ImagePullPolicy v1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"` //ignored by the validator which runs as a single pod
+
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"` //added to the environment variables set by the operator
+}
+
+// Merge returns a copy of the workload with the fields that are set in override replaced.
+// Env vars are merged by name, resource requests and limits are merged by resource name.
+func (w WorkloadSpec) Merge(override *WorkloadSpec) WorkloadSpec {
+	if override == nil {
+		return w
+	}
+
+	if override.Image != "" {
+		w.Image = override.Image
+	}
+	if override.Tag != "" {
+		w.Tag = override.Tag
+	}
+	if override.ImagePullPolicy != "" {
+		w.ImagePullPolicy = override.ImagePullPolicy
+	}
+	if override.Replicas != nil {
+		w.Replicas = override.Replicas
+	}
+	if override.Resources != nil {
+		w.Resources = mergeResources(w.Resources, override.Resources)
+	}
+	if override.NodeSelector != nil {
+		w.NodeSelector = override.NodeSelector
+	}
+	if override.Tolerations != nil {
+		w.Tolerations = override.Tolerations
+	}
+	if override.Env != nil {
+		var env []v1.EnvVar
+		for _, envVar := range w.Env {
+			overridden := false
+			for _, overrideEnvVar := range override.Env {
+				overridden = overridden || envVar.Name == overrideEnvVar.Name
+			}
+			if !overridden {
+				env = append(env, envVar)
+			}
+		}
+		w.Env = append(env, override.Env...)
+	}
+
+	return w
+}
+
+// mergeResources replaces the requests and limits of resources that are set in override. A request that is not
+// overridden is lowered to the overridden limit of its resource, so the requirements stay valid.
+func mergeResources(resources *v1.ResourceRequirements, override *v1.ResourceRequirements) *v1.ResourceRequirements {
+	if resources == nil {
+		return override.DeepCopy()
+	}
+
+	merged := resources.DeepCopy()
+	if merged.Requests == nil && len(override.Requests) > 0 {
+		merged.Requests = v1.ResourceList{}
+	}
+	for name, quantity := range override.Requests {
+		merged.Requests[name] = quantity.DeepCopy()
+	}
+	if merged.Limits == nil && len(override.Limits) > 0 {
+		merged.Limits = v1.ResourceList{}
+	}
+	for name, limit := range override.Limits {
+		merged.Limits[name] = limit.DeepCopy()
+		if _, ok := override.Requests[name]; ok {
+			continue
+		}
+		if request, ok := merged.Requests[name]; ok && request.Cmp(limit) > 0 {
+			merged.Requests[name] = limit.DeepCopy()
+		}
+	}
+	return merged
+}
+
+// ImageName returns the full image reference, Tag can either be a tag or a digest
+func (w WorkloadSpec) ImageName() string {
+	if w.Tag == "" {
+		return w.Image
+	} else if strings.Contains(w.Tag, ":") {
+		return w.Image + "@" + w.Tag
+	}
+	return w.Image + ":" + w.Tag
+}
+
+// XJoinIndexWorkloads configures the workloads created for each version of an XJoinIndex
+type XJoinIndexWorkloads struct {
+	// +optional
+	Core *WorkloadSpec `json:"core,omitempty"`
+
+	// +optional
+	Subgraph *WorkloadSpec `json:"subgraph,omitempty"` //the image is ignored for customSubgraphImages
+
+	// +optional
+	Validator *WorkloadSpec `json:"validator,omitempty"`
+}
+
+// XJoinIndexValidation overrides the validation thresholds of the xjoin-generic ConfigMap for an XJoinIndex
+type XJoinIndexValidation struct {
+	// +optional
+	AttemptsThreshold *int `json:"attemptsThreshold,omitempty"` //consecutive failed validations before the active version is invalid
+
+	// +optional
+	PercentageThreshold *int `json:"percentageThreshold,omitempty"` //percentage of mismatched documents a validation tolerates
+
+	// +optional
+	RefreshAttemptsThreshold *int `json:"refreshAttemptsThreshold,omitempty"` //consecutive failed validations before a refresh is restarted (0 = never)
+
+	// +optional
+	InitPercentageThreshold *int `json:"initPercentageThreshold,omitempty"` //percentage of mismatched documents tolerated before a version is active
+}
+
+// RefreshSummary compares the active and refreshing versions while a refresh is awaiting approval
+type RefreshSummary struct {
+	ActiveVersion     string `json:"activeVersion"`
+	RefreshingVersion string `json:"refreshingVersion"`
+
+	// +optional
+	ActiveDocumentCount *int `json:"activeDocumentCount,omitempty"`
+
+	// +optional
+	RefreshingDocumentCount *int `json:"refreshingDocumentCount,omitempty"`
+
+	// +optional
+	ActiveValidation string `json:"activeValidation,omitempty"`
+
+	// +optional
+	RefreshingValidation string `json:"refreshingValidation,omitempty"`
+
+	// +optional
+	SchemaChanges []string `json:"schemaChanges,omitempty"`
+}
+
+// XJoinAction records the handling of an action requested via an annotation
+type XJoinAction struct {
+	Annotation string `json:"annotation"`
+
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	Message string      `json:"message"`
+	Time    metav1.Time `json:"time"`
+}
+
 type StringOrSecretParameter struct {
 	// +optional
 	Value string `json:"value,omitempty"`
@@ -35,6 +288,26 @@ func (s StringOrSecretParameter) ConvertToEnvVar(name string) (envVar v1.EnvVar,
 	return
 }
 
+// SecretKeySelector is the secret key a StringOrSecretParameter is read from, nil when it is a value
+func (s *StringOrSecretParameter) SecretKeySelector() *v1.SecretKeySelector {
+	if s == nil || s.ValueFrom == nil {
+		return nil
+	}
+	return s.ValueFrom.SecretKeyRef
+}
+
+// FilePath is the path of the file a StringOrSecretParameter refers to. A value is the path itself, the key of a
+// secret is expected in a directory named after the secret under mountPath.
+func (s *StringOrSecretParameter) FilePath(mountPath string) string {
+	if s == nil {
+		return ""
+	}
+	if secretKey := s.SecretKeySelector(); secretKey != nil {
+		return path.Join(mountPath, secretKey.Name, secretKey.Key)
+	}
+	return s.Value
+}
+
 type SecretKeyRef struct {
 	// +optional
 	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
@@ -10,6 +10,30 @@ type PipelineState string
 
 const validConditionType = "Valid"
 
+// ComponentsHealthyConditionType is set on the xjoin.v2 pipelines after checking each component for deviations
+const ComponentsHealthyConditionType = "ComponentsHealthy"
+
+// ReplicationLagHighConditionType is set on an XJoinDataSourcePipeline after checking its replication slot
+// and copied to the XJoinDataSource from its active version
+const ReplicationLagHighConditionType = "ReplicationLagHigh"
+
+// ValidationResultConditionType is set on an XJoinIndexValidator after each validation Job finishes
+const ValidationResultConditionType = "ValidationResult"
+
+// AvroSchemaParsedConditionType is set on an XJoinIndexPipeline after parsing its avroSchema and copied to its XJoinIndex
+const AvroSchemaParsedConditionType = "AvroSchemaParsed"
+
+// SchemaEvolvedConditionType is set on an XJoinIndexPipeline after its components are evolved to an updated avroSchema
+const SchemaEvolvedConditionType = "SchemaEvolved"
+
+// Condition types set on XJoinIndex and XJoinDataSource after each reconcile
+const (
+	ReadyConditionType      = "Ready"
+	RefreshingConditionType = "Refreshing"
+	DegradedConditionType   = "Degraded"
+	PausedConditionType     = "Paused"
+)
+
 const (
 	STATE_NEW          PipelineState = "NEW"
 	STATE_INITIAL_SYNC PipelineState = "INITIAL_SYNC"
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,8 +14,44 @@ type XJoinDataSourceSpec struct {
 	DatabaseName     *StringOrSecretParameter `json:"databaseName,omitempty"`
 	DatabaseTable    *StringOrSecretParameter `json:"databaseTable,omitempty"`
 
+	// AvroSchemaFrom generates the Avro schema of each new version from the databaseTable's columns when it is
+	// introspect. The avroSchema is ignored.
+	// +kubebuilder:validation:Enum=introspect
+	// +optional
+	AvroSchemaFrom string `json:"avroSchemaFrom,omitempty"`
+
+	// DatabaseType selects the Debezium source connector, postgres when empty
+	// +kubebuilder:validation:Enum=postgres;mysql;sqlserver
+	// +optional
+	DatabaseType string `json:"databaseType,omitempty"`
+
+	// +optional
+	DatabaseSSLMode *StringOrSecretParameter `json:"databaseSSLMode,omitempty"` //e.g. verify-full, the modes depend on the databaseType
+
+	// +optional
+	DatabaseSSLRootCert *StringOrSecretParameter `json:"databaseSSLRootCert,omitempty"` //path to the CA bundle, or a secret with its contents
+
+	// +optional
+	DatabaseSSLCert *StringOrSecretParameter `json:"databaseSSLCert,omitempty"` //path to the client certificate, or a secret with its contents
+
+	// +optional
+	DatabaseSSLKey *StringOrSecretParameter `json:"databaseSSLKey,omitempty"` //path to the client certificate's key, or a secret with its contents
+
+	// +optional
+	DatabaseSignalTable string `json:"databaseSignalTable,omitempty"` //Debezium signaling table used for incremental snapshots
+
+	// +optional
+	RowFilter *RowFilter `json:"rowFilter,omitempty"`
+
 	// +optional
 	Pause bool `json:"pause,omitempty"`
+
+	// +optional
+	RefreshPolicy *RefreshPolicy `json:"refreshPolicy,omitempty"`
+
+	// Source reads the datasource from an existing Kafka topic instead of a database
+	// +optional
+	Source *DataSourceSource `json:"source,omitempty"`
 }
 
 type XJoinDataSourceStatus struct {
@@ -23,6 +60,77 @@ type XJoinDataSourceStatus struct {
 	RefreshingVersion        string `json:"refreshingVersion"`
 	RefreshingVersionIsValid bool   `json:"refreshingVersionIsValid"`
 	SpecHash                 string `json:"specHash"`
+
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +optional
+	LastRefreshReason string `json:"lastRefreshReason,omitempty"`
+
+	// +optional
+	LastAction *XJoinAction `json:"lastAction,omitempty"`
+
+	// +optional
+	ActiveVersionValidationSkipped bool `json:"activeVersionValidationSkipped,omitempty"`
+
+	// +optional
+	ActiveVersionValidationSkippedTime *metav1.Time `json:"activeVersionValidationSkippedTime,omitempty"`
+
+	// +optional
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// +optional
+	PreviousVersionExpiration *metav1.Time `json:"previousVersionExpiration,omitempty"`
+
+	// +optional
+	ApprovedVersion string `json:"approvedVersion,omitempty"`
+
+	// +optional
+	RefreshSummary *RefreshSummary `json:"refreshSummary,omitempty"`
+
+	// +optional
+	IncrementalSnapshot *IncrementalSnapshotStatus `json:"incrementalSnapshot,omitempty"`
+
+	// +optional
+	IntrospectedAvroSchema string `json:"introspectedAvroSchema,omitempty"` //schema generated for the latest version
+}
+
+// AvroSchemaFromIntrospect generates a datasource's Avro schema from its table's columns
+const AvroSchemaFromIntrospect = "introspect"
+
+// Phases of an incremental snapshot
+const (
+	IncrementalSnapshotRequested = "Requested"
+	IncrementalSnapshotRunning   = "Running"
+	IncrementalSnapshotCompleted = "Completed"
+	IncrementalSnapshotFailed    = "Failed"
+)
+
+// IncrementalSnapshotStatus is the progress of the latest incremental snapshot of the active version's table
+type IncrementalSnapshotStatus struct {
+	Version   string      `json:"version"`
+	SignalId  string      `json:"signalId,omitempty"`
+	Reason    string      `json:"reason,omitempty"`
+	Phase     string      `json:"phase"`
+	StartTime metav1.Time `json:"startTime"`
+
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	ChunksCompleted int `json:"chunksCompleted,omitempty"`
+
+	// +optional
+	ChunksEstimated int `json:"chunksEstimated,omitempty"` //rows in the table when the snapshot started divided by the chunk size
 }
 
 // +kubebuilder:object:root=true
@@ -37,8 +145,11 @@ type XJoinDataSource struct {
 	Status XJoinDataSourceStatus `json:"status,omitempty"`
 }
 
+// GetSpec returns the part of the spec that requires a refresh when it is changed
 func (in *XJoinDataSource) GetSpec() interface{} {
-	return in.Spec
+	spec := in.Spec
+	spec.RefreshPolicy = nil
+	return spec
 }
 
 func (in *XJoinDataSource) GetSpecHash() string {
@@ -77,6 +188,91 @@ func (in *XJoinDataSource) SetRefreshingVersionIsValid(valid bool) {
 	in.Status.RefreshingVersionIsValid = valid
 }
 
+func (in *XJoinDataSource) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+func (in *XJoinDataSource) SetCondition(condition metav1.Condition) {
+	meta.SetStatusCondition(&in.Status.Conditions, condition)
+}
+
+func (in *XJoinDataSource) SetPhase(phase string) {
+	in.Status.Phase = phase
+}
+
+func (in *XJoinDataSource) SetObservedGeneration(generation int64) {
+	in.Status.ObservedGeneration = generation
+}
+
+func (in *XJoinDataSource) SetRefreshReason(reason string) {
+	in.Status.LastRefreshReason = reason
+}
+
+func (in *XJoinDataSource) GetLastAction() *XJoinAction {
+	return in.Status.LastAction
+}
+
+func (in *XJoinDataSource) SetLastAction(action XJoinAction) {
+	in.Status.LastAction = &action
+}
+
+func (in *XJoinDataSource) SetActiveVersionValidationSkipped(skipped bool) {
+	in.Status.ActiveVersionValidationSkipped = skipped
+	in.Status.ActiveVersionValidationSkippedTime = nil
+	if skipped {
+		now := metav1.Now()
+		in.Status.ActiveVersionValidationSkippedTime = &now
+	}
+}
+
+func (in *XJoinDataSource) GetPreviousVersion() string {
+	return in.Status.PreviousVersion
+}
+
+func (in *XJoinDataSource) GetPreviousVersionExpiration() *metav1.Time {
+	return in.Status.PreviousVersionExpiration
+}
+
+func (in *XJoinDataSource) SetPreviousVersion(version string, expiration *metav1.Time) {
+	in.Status.PreviousVersion = version
+	in.Status.PreviousVersionExpiration = expiration
+}
+
+func (in *XJoinDataSource) RefreshRequiresApproval() bool {
+	return in.Spec.RefreshPolicy != nil && in.Spec.RefreshPolicy.RequireApproval
+}
+
+// KafkaTopicSource is the existing Kafka topic the datasource is read from, nil for database datasources
+func (in *XJoinDataSource) KafkaTopicSource() *KafkaTopicSource {
+	if in.Spec.Source == nil {
+		return nil
+	}
+	return in.Spec.Source.KafkaTopic
+}
+
+func (in *XJoinDataSource) RefreshWithIncrementalSnapshot() bool {
+	return in.Spec.RefreshPolicy != nil && in.Spec.RefreshPolicy.Strategy == RefreshStrategyIncrementalSnapshot
+}
+
+// IncrementalSnapshotInProgress is true while the active version's table is re-read by an incremental snapshot
+func (in *XJoinDataSource) IncrementalSnapshotInProgress() bool {
+	snapshot := in.Status.IncrementalSnapshot
+	return snapshot != nil && snapshot.Version == in.Status.ActiveVersion &&
+		(snapshot.Phase == IncrementalSnapshotRequested || snapshot.Phase == IncrementalSnapshotRunning)
+}
+
+func (in *XJoinDataSource) GetApprovedVersion() string {
+	return in.Status.ApprovedVersion
+}
+
+func (in *XJoinDataSource) SetApprovedVersion(version string) {
+	in.Status.ApprovedVersion = version
+}
+
+func (in *XJoinDataSource) SetRefreshSummary(summary *RefreshSummary) {
+	in.Status.RefreshSummary = summary
+}
+
 // +kubebuilder:object:root=true
 
 type XJoinDataSourceList struct {
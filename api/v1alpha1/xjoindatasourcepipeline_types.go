@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -14,6 +15,10 @@ type XJoinDataSourcePipelineSpec struct {
 	// +kubebuilder:validation:Required
 	AvroSchema string `json:"avroSchema,omitempty"`
 
+	// +kubebuilder:validation:Enum=postgres;mysql;sqlserver
+	// +optional
+	DatabaseType string `json:"databaseType,omitempty"`
+
 	// +optional
 	DatabaseHostname *StringOrSecretParameter `json:"databaseHostname,omitempty"`
 
@@ -32,11 +37,37 @@ type XJoinDataSourcePipelineSpec struct {
 	// +optional
 	DatabaseTable *StringOrSecretParameter `json:"databaseTable,omitempty"`
 
+	// +optional
+	DatabaseSSLMode *StringOrSecretParameter `json:"databaseSSLMode,omitempty"`
+
+	// +optional
+	DatabaseSSLRootCert *StringOrSecretParameter `json:"databaseSSLRootCert,omitempty"`
+
+	// +optional
+	DatabaseSSLCert *StringOrSecretParameter `json:"databaseSSLCert,omitempty"`
+
+	// +optional
+	DatabaseSSLKey *StringOrSecretParameter `json:"databaseSSLKey,omitempty"`
+
+	// +optional
+	DatabaseSignalTable string `json:"databaseSignalTable,omitempty"` //Debezium signaling table used for incremental snapshots
+
+	// +optional
+	RowFilter *RowFilter `json:"rowFilter,omitempty"`
+
 	// +optional
 	Pause bool `json:"pause,omitempty"`
+
+	// +optional
+	Source *DataSourceSource `json:"source,omitempty"`
 }
 
 type XJoinDataSourcePipelineStatus struct {
+	ValidationResponse validation.ValidationResponse `json:"validationResponse,omitempty"`
+	Conditions         []metav1.Condition            `json:"conditions,omitempty"`
+
+	// +optional
+	LastValidationTime *metav1.Time `json:"lastValidationTime,omitempty"` //when the validator last recorded a result
 }
 
 // +kubebuilder:object:root=true
@@ -59,6 +90,14 @@ type XJoinDataSourcePipelineList struct {
 	Items           []XJoinDataSourcePipeline `json:"items"`
 }
 
+// KafkaTopicSource is the existing Kafka topic the pipeline reads, nil for database datasources
+func (in *XJoinDataSourcePipeline) KafkaTopicSource() *KafkaTopicSource {
+	if in.Spec.Source == nil {
+		return nil
+	}
+	return in.Spec.Source.KafkaTopic
+}
+
 func init() {
 	SchemeBuilder.Register(&XJoinDataSourcePipeline{}, &XJoinDataSourcePipelineList{})
 }
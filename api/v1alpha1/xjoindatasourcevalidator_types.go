@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type XJoinDataSourceValidatorSpec struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Version string `json:"version,omitempty"`
+
+	// +kubebuilder:validation:Required
+	AvroSchema string `json:"avroSchema,omitempty"`
+
+	// +kubebuilder:validation:Required
+	TopicName string `json:"topicName,omitempty"`
+
+	// +kubebuilder:validation:Enum=postgres;mysql;sqlserver
+	// +optional
+	DatabaseType string `json:"databaseType,omitempty"`
+
+	// +optional
+	DatabaseHostname *StringOrSecretParameter `json:"databaseHostname,omitempty"`
+
+	// +optional
+	DatabasePort *StringOrSecretParameter `json:"databasePort,omitempty"`
+
+	// +optional
+	DatabaseUsername *StringOrSecretParameter `json:"databaseUsername,omitempty"`
+
+	// +optional
+	DatabasePassword *StringOrSecretParameter `json:"databasePassword,omitempty"`
+
+	// +optional
+	DatabaseName *StringOrSecretParameter `json:"databaseName,omitempty"`
+
+	// +optional
+	DatabaseTable *StringOrSecretParameter `json:"databaseTable,omitempty"`
+
+	// +optional
+	DatabaseSSLMode *StringOrSecretParameter `json:"databaseSSLMode,omitempty"`
+
+	// +optional
+	DatabaseSSLRootCert *StringOrSecretParameter `json:"databaseSSLRootCert,omitempty"`
+
+	// +optional
+	DatabaseSSLCert *StringOrSecretParameter `json:"databaseSSLCert,omitempty"`
+
+	// +optional
+	DatabaseSSLKey *StringOrSecretParameter `json:"databaseSSLKey,omitempty"`
+
+	// +optional
+	RowFilter *RowFilter `json:"rowFilter,omitempty"`
+
+	// +optional
+	Pause bool `json:"pause,omitempty"`
+}
+
+type XJoinDataSourceValidatorStatus struct {
+	ValidationResponse validation.ValidationResponse `json:"validationResponse,omitempty"`
+	ValidationPodPhase string                        `json:"validationPodPhase,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=xjoindatasourcevalidator,categories=all
+
+type XJoinDataSourceValidator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   XJoinDataSourceValidatorSpec   `json:"spec,omitempty"`
+	Status XJoinDataSourceValidatorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type XJoinDataSourceValidatorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []XJoinDataSourceValidator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&XJoinDataSourceValidator{}, &XJoinDataSourceValidatorList{})
+}
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -11,6 +12,15 @@ type XJoinIndexSpec struct {
 
 	// +optional
 	Pause bool `json:"pause,omitempty"`
+
+	// +optional
+	RefreshPolicy *RefreshPolicy `json:"refreshPolicy,omitempty"`
+
+	// +optional
+	Workloads *XJoinIndexWorkloads `json:"workloads,omitempty"`
+
+	// +optional
+	Validation *XJoinIndexValidation `json:"validation,omitempty"`
 }
 
 type XJoinIndexStatus struct {
@@ -20,8 +30,70 @@ type XJoinIndexStatus struct {
 	RefreshingVersionIsValid bool   `json:"refreshingVersionIsValid"`
 	SpecHash                 string `json:"specHash"`
 
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	//+optional
-	DataSources map[string]string `json:"dataSources"` //map of datasource name to datasource resource version
+	DataSourceVersions map[string]XJoinIndexDataSource `json:"dataSourceVersions,omitempty"` //map of datasource name to the datasource version used by the index
+
+	// DataSources is the map of datasource name to datasource resource version written by previous releases. It is
+	// converted to DataSourceVersions when the index is reconciled.
+	// +optional
+	DataSources map[string]string `json:"dataSources,omitempty"`
+
+	// +optional
+	LastRefreshReason string `json:"lastRefreshReason,omitempty"`
+
+	// +optional
+	LastAction *XJoinAction `json:"lastAction,omitempty"`
+
+	// +optional
+	ActiveVersionValidationSkipped bool `json:"activeVersionValidationSkipped,omitempty"`
+
+	// +optional
+	ActiveVersionValidationSkippedTime *metav1.Time `json:"activeVersionValidationSkippedTime,omitempty"`
+
+	// +optional
+	PreviousVersion string `json:"previousVersion,omitempty"`
+
+	// +optional
+	PreviousVersionExpiration *metav1.Time `json:"previousVersionExpiration,omitempty"`
+
+	// +optional
+	ApprovedVersion string `json:"approvedVersion,omitempty"`
+
+	// +optional
+	RefreshSummary *RefreshSummary `json:"refreshSummary,omitempty"`
+
+	// +optional
+	ElasticsearchAlias string `json:"elasticsearchAlias,omitempty"` //stable alias pointing to the active version's index
+
+	// +optional
+	ActiveVersionValidationFailures int `json:"activeVersionValidationFailures,omitempty"`
+
+	// +optional
+	RefreshingVersionValidationFailures int `json:"refreshingVersionValidationFailures,omitempty"`
+
+	// +optional
+	ActiveVersionValidationReport string `json:"activeVersionValidationReport,omitempty"`
+
+	// +optional
+	RefreshingVersionValidationReport string `json:"refreshingVersionValidationReport,omitempty"`
+}
+
+// XJoinIndexDataSource records the state of a datasource when the index last started a refresh
+type XJoinIndexDataSource struct {
+	// +optional
+	ActiveVersion string `json:"activeVersion,omitempty"`
+
+	// +optional
+	AvroSchemaHash string `json:"avroSchemaHash,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -44,8 +116,8 @@ type CustomSubgraphImage struct {
 	Image string `json:"image"`
 }
 
-func (in *XJoinIndex) GetDataSources() map[string]string {
-	return in.Status.DataSources
+func (in *XJoinIndex) GetDataSources() map[string]XJoinIndexDataSource {
+	return in.Status.DataSourceVersions
 }
 
 func (in *XJoinIndex) GetDataSourceNames() []string {
@@ -56,8 +128,13 @@ func (in *XJoinIndex) GetDataSourceNames() []string {
 	return keys
 }
 
+// GetSpec returns the part of the spec that requires a refresh when it is changed
 func (in *XJoinIndex) GetSpec() interface{} {
-	return in.Spec
+	spec := in.Spec
+	spec.RefreshPolicy = nil
+	spec.Workloads = nil  //rolled out to the existing versions
+	spec.Validation = nil //rolled out to the existing versions
+	return spec
 }
 
 func (in *XJoinIndex) GetSpecHash() string {
@@ -96,6 +173,72 @@ func (in *XJoinIndex) SetRefreshingVersionIsValid(valid bool) {
 	in.Status.RefreshingVersionIsValid = valid
 }
 
+func (in *XJoinIndex) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+func (in *XJoinIndex) SetCondition(condition metav1.Condition) {
+	meta.SetStatusCondition(&in.Status.Conditions, condition)
+}
+
+func (in *XJoinIndex) SetPhase(phase string) {
+	in.Status.Phase = phase
+}
+
+func (in *XJoinIndex) SetObservedGeneration(generation int64) {
+	in.Status.ObservedGeneration = generation
+}
+
+func (in *XJoinIndex) SetRefreshReason(reason string) {
+	in.Status.LastRefreshReason = reason
+}
+
+func (in *XJoinIndex) GetLastAction() *XJoinAction {
+	return in.Status.LastAction
+}
+
+func (in *XJoinIndex) SetLastAction(action XJoinAction) {
+	in.Status.LastAction = &action
+}
+
+func (in *XJoinIndex) SetActiveVersionValidationSkipped(skipped bool) {
+	in.Status.ActiveVersionValidationSkipped = skipped
+	in.Status.ActiveVersionValidationSkippedTime = nil
+	if skipped {
+		now := metav1.Now()
+		in.Status.ActiveVersionValidationSkippedTime = &now
+	}
+}
+
+func (in *XJoinIndex) GetPreviousVersion() string {
+	return in.Status.PreviousVersion
+}
+
+func (in *XJoinIndex) GetPreviousVersionExpiration() *metav1.Time {
+	return in.Status.PreviousVersionExpiration
+}
+
+func (in *XJoinIndex) SetPreviousVersion(version string, expiration *metav1.Time) {
+	in.Status.PreviousVersion = version
+	in.Status.PreviousVersionExpiration = expiration
+}
+
+func (in *XJoinIndex) RefreshRequiresApproval() bool {
+	return in.Spec.RefreshPolicy != nil && in.Spec.RefreshPolicy.RequireApproval
+}
+
+func (in *XJoinIndex) GetApprovedVersion() string {
+	return in.Status.ApprovedVersion
+}
+
+func (in *XJoinIndex) SetApprovedVersion(version string) {
+	in.Status.ApprovedVersion = version
+}
+
+func (in *XJoinIndex) SetRefreshSummary(summary *RefreshSummary) {
+	in.Status.RefreshSummary = summary
+}
+
 // +kubebuilder:object:root=true
 
 type XJoinIndexList struct {
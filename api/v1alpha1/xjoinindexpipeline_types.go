@@ -20,10 +20,32 @@ type XJoinIndexPipelineSpec struct {
 
 	// +optional
 	Pause bool `json:"pause,omitempty"`
+
+	// +optional
+	Workloads *XJoinIndexWorkloads `json:"workloads,omitempty"`
+
+	// +optional
+	Validation *XJoinIndexValidation `json:"validation,omitempty"`
 }
 
 type XJoinIndexPipelineStatus struct {
 	ValidationResponse validation.ValidationResponse `json:"validationResponse,omitempty"`
+	Conditions         []metav1.Condition            `json:"conditions,omitempty"`
+
+	// +optional
+	ValidationFailedCount int `json:"validationFailedCount,omitempty"` //consecutive validations above the mismatch threshold
+
+	// +optional
+	ValidationReport string `json:"validationReport,omitempty"` //name of the ConfigMap with the validation history
+
+	// +optional
+	LastValidationRepair *metav1.Time `json:"lastValidationRepair,omitempty"`
+
+	// +optional
+	LastValidationTime *metav1.Time `json:"lastValidationTime,omitempty"` //when the validator last recorded a result
+
+	// +optional
+	AvroSchemaHash string `json:"avroSchemaHash,omitempty"` //hash of the avroSchema the components were last updated to
 }
 
 // +kubebuilder:object:root=true
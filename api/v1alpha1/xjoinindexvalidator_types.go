@@ -20,11 +20,20 @@ type XJoinIndexValidatorSpec struct {
 
 	// +optional
 	Pause bool `json:"pause,omitempty"`
+
+	// +optional
+	Workload *WorkloadSpec `json:"workload,omitempty"`
+
+	// +optional
+	Validation *XJoinIndexValidation `json:"validation,omitempty"`
 }
 
 type XJoinIndexValidatorStatus struct {
 	ValidationResponse validation.ValidationResponse `json:"validationResponse,omitempty"`
 	ValidationPodPhase string                        `json:"validationPodPhase,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -42,6 +42,126 @@ func (in *CustomSubgraphImage) DeepCopy() *CustomSubgraphImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSourceSource) DeepCopyInto(out *DataSourceSource) {
+	*out = *in
+	if in.KafkaTopic != nil {
+		in, out := &in.KafkaTopic, &out.KafkaTopic
+		*out = new(KafkaTopicSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSourceSource.
+func (in *DataSourceSource) DeepCopy() *DataSourceSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSourceSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncrementalSnapshotStatus) DeepCopyInto(out *IncrementalSnapshotStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncrementalSnapshotStatus.
+func (in *IncrementalSnapshotStatus) DeepCopy() *IncrementalSnapshotStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IncrementalSnapshotStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaTopicSource) DeepCopyInto(out *KafkaTopicSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaTopicSource.
+func (in *KafkaTopicSource) DeepCopy() *KafkaTopicSource {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaTopicSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RefreshPolicy) DeepCopyInto(out *RefreshPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshPolicy.
+func (in *RefreshPolicy) DeepCopy() *RefreshPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RefreshPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RefreshSummary) DeepCopyInto(out *RefreshSummary) {
+	*out = *in
+	if in.ActiveDocumentCount != nil {
+		in, out := &in.ActiveDocumentCount, &out.ActiveDocumentCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.RefreshingDocumentCount != nil {
+		in, out := &in.RefreshingDocumentCount, &out.RefreshingDocumentCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.SchemaChanges != nil {
+		in, out := &in.SchemaChanges, &out.SchemaChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RefreshSummary.
+func (in *RefreshSummary) DeepCopy() *RefreshSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RefreshSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RowFilter) DeepCopyInto(out *RowFilter) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RowFilter.
+func (in *RowFilter) DeepCopy() *RowFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(RowFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
 	*out = *in
@@ -82,13 +202,75 @@ func (in *StringOrSecretParameter) DeepCopy() *StringOrSecretParameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSpec) DeepCopyInto(out *WorkloadSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpec.
+func (in *WorkloadSpec) DeepCopy() *WorkloadSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinAction) DeepCopyInto(out *XJoinAction) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinAction.
+func (in *XJoinAction) DeepCopy() *XJoinAction {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinDataSource) DeepCopyInto(out *XJoinDataSource) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSource.
@@ -147,7 +329,7 @@ func (in *XJoinDataSourcePipeline) DeepCopyInto(out *XJoinDataSourcePipeline) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourcePipeline.
@@ -233,6 +415,36 @@ func (in *XJoinDataSourcePipelineSpec) DeepCopyInto(out *XJoinDataSourcePipeline
 		*out = new(StringOrSecretParameter)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DatabaseSSLMode != nil {
+		in, out := &in.DatabaseSSLMode, &out.DatabaseSSLMode
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLRootCert != nil {
+		in, out := &in.DatabaseSSLRootCert, &out.DatabaseSSLRootCert
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLCert != nil {
+		in, out := &in.DatabaseSSLCert, &out.DatabaseSSLCert
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLKey != nil {
+		in, out := &in.DatabaseSSLKey, &out.DatabaseSSLKey
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RowFilter != nil {
+		in, out := &in.RowFilter, &out.RowFilter
+		*out = new(RowFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(DataSourceSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourcePipelineSpec.
@@ -248,6 +460,18 @@ func (in *XJoinDataSourcePipelineSpec) DeepCopy() *XJoinDataSourcePipelineSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinDataSourcePipelineStatus) DeepCopyInto(out *XJoinDataSourcePipelineStatus) {
 	*out = *in
+	in.ValidationResponse.DeepCopyInto(&out.ValidationResponse)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastValidationTime != nil {
+		in, out := &in.LastValidationTime, &out.LastValidationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourcePipelineStatus.
@@ -293,6 +517,41 @@ func (in *XJoinDataSourceSpec) DeepCopyInto(out *XJoinDataSourceSpec) {
 		*out = new(StringOrSecretParameter)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DatabaseSSLMode != nil {
+		in, out := &in.DatabaseSSLMode, &out.DatabaseSSLMode
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLRootCert != nil {
+		in, out := &in.DatabaseSSLRootCert, &out.DatabaseSSLRootCert
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLCert != nil {
+		in, out := &in.DatabaseSSLCert, &out.DatabaseSSLCert
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLKey != nil {
+		in, out := &in.DatabaseSSLKey, &out.DatabaseSSLKey
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RowFilter != nil {
+		in, out := &in.RowFilter, &out.RowFilter
+		*out = new(RowFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RefreshPolicy != nil {
+		in, out := &in.RefreshPolicy, &out.RefreshPolicy
+		*out = new(RefreshPolicy)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(DataSourceSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourceSpec.
@@ -308,6 +567,36 @@ func (in *XJoinDataSourceSpec) DeepCopy() *XJoinDataSourceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinDataSourceStatus) DeepCopyInto(out *XJoinDataSourceStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastAction != nil {
+		in, out := &in.LastAction, &out.LastAction
+		*out = new(XJoinAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveVersionValidationSkippedTime != nil {
+		in, out := &in.ActiveVersionValidationSkippedTime, &out.ActiveVersionValidationSkippedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousVersionExpiration != nil {
+		in, out := &in.PreviousVersionExpiration, &out.PreviousVersionExpiration
+		*out = (*in).DeepCopy()
+	}
+	if in.RefreshSummary != nil {
+		in, out := &in.RefreshSummary, &out.RefreshSummary
+		*out = new(RefreshSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IncrementalSnapshot != nil {
+		in, out := &in.IncrementalSnapshot, &out.IncrementalSnapshot
+		*out = new(IncrementalSnapshotStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourceStatus.
@@ -320,6 +609,151 @@ func (in *XJoinDataSourceStatus) DeepCopy() *XJoinDataSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinDataSourceValidator) DeepCopyInto(out *XJoinDataSourceValidator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourceValidator.
+func (in *XJoinDataSourceValidator) DeepCopy() *XJoinDataSourceValidator {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinDataSourceValidator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *XJoinDataSourceValidator) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinDataSourceValidatorList) DeepCopyInto(out *XJoinDataSourceValidatorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]XJoinDataSourceValidator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourceValidatorList.
+func (in *XJoinDataSourceValidatorList) DeepCopy() *XJoinDataSourceValidatorList {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinDataSourceValidatorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *XJoinDataSourceValidatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinDataSourceValidatorSpec) DeepCopyInto(out *XJoinDataSourceValidatorSpec) {
+	*out = *in
+	if in.DatabaseHostname != nil {
+		in, out := &in.DatabaseHostname, &out.DatabaseHostname
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabasePort != nil {
+		in, out := &in.DatabasePort, &out.DatabasePort
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseUsername != nil {
+		in, out := &in.DatabaseUsername, &out.DatabaseUsername
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabasePassword != nil {
+		in, out := &in.DatabasePassword, &out.DatabasePassword
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseName != nil {
+		in, out := &in.DatabaseName, &out.DatabaseName
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseTable != nil {
+		in, out := &in.DatabaseTable, &out.DatabaseTable
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLMode != nil {
+		in, out := &in.DatabaseSSLMode, &out.DatabaseSSLMode
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLRootCert != nil {
+		in, out := &in.DatabaseSSLRootCert, &out.DatabaseSSLRootCert
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLCert != nil {
+		in, out := &in.DatabaseSSLCert, &out.DatabaseSSLCert
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseSSLKey != nil {
+		in, out := &in.DatabaseSSLKey, &out.DatabaseSSLKey
+		*out = new(StringOrSecretParameter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RowFilter != nil {
+		in, out := &in.RowFilter, &out.RowFilter
+		*out = new(RowFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourceValidatorSpec.
+func (in *XJoinDataSourceValidatorSpec) DeepCopy() *XJoinDataSourceValidatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinDataSourceValidatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinDataSourceValidatorStatus) DeepCopyInto(out *XJoinDataSourceValidatorStatus) {
+	*out = *in
+	in.ValidationResponse.DeepCopyInto(&out.ValidationResponse)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinDataSourceValidatorStatus.
+func (in *XJoinDataSourceValidatorStatus) DeepCopy() *XJoinDataSourceValidatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinDataSourceValidatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinIndex) DeepCopyInto(out *XJoinIndex) {
 	*out = *in
@@ -347,6 +781,21 @@ func (in *XJoinIndex) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinIndexDataSource) DeepCopyInto(out *XJoinIndexDataSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexDataSource.
+func (in *XJoinIndexDataSource) DeepCopy() *XJoinIndexDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinIndexDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinIndexList) DeepCopyInto(out *XJoinIndexList) {
 	*out = *in
@@ -446,6 +895,16 @@ func (in *XJoinIndexPipelineSpec) DeepCopyInto(out *XJoinIndexPipelineSpec) {
 		*out = make([]CustomSubgraphImage, len(*in))
 		copy(*out, *in)
 	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = new(XJoinIndexWorkloads)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(XJoinIndexValidation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexPipelineSpec.
@@ -462,6 +921,21 @@ func (in *XJoinIndexPipelineSpec) DeepCopy() *XJoinIndexPipelineSpec {
 func (in *XJoinIndexPipelineStatus) DeepCopyInto(out *XJoinIndexPipelineStatus) {
 	*out = *in
 	in.ValidationResponse.DeepCopyInto(&out.ValidationResponse)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastValidationRepair != nil {
+		in, out := &in.LastValidationRepair, &out.LastValidationRepair
+		*out = (*in).DeepCopy()
+	}
+	if in.LastValidationTime != nil {
+		in, out := &in.LastValidationTime, &out.LastValidationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexPipelineStatus.
@@ -482,6 +956,21 @@ func (in *XJoinIndexSpec) DeepCopyInto(out *XJoinIndexSpec) {
 		*out = make([]CustomSubgraphImage, len(*in))
 		copy(*out, *in)
 	}
+	if in.RefreshPolicy != nil {
+		in, out := &in.RefreshPolicy, &out.RefreshPolicy
+		*out = new(RefreshPolicy)
+		**out = **in
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = new(XJoinIndexWorkloads)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(XJoinIndexValidation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexSpec.
@@ -497,6 +986,20 @@ func (in *XJoinIndexSpec) DeepCopy() *XJoinIndexSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinIndexStatus) DeepCopyInto(out *XJoinIndexStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DataSourceVersions != nil {
+		in, out := &in.DataSourceVersions, &out.DataSourceVersions
+		*out = make(map[string]XJoinIndexDataSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.DataSources != nil {
 		in, out := &in.DataSources, &out.DataSources
 		*out = make(map[string]string, len(*in))
@@ -504,6 +1007,24 @@ func (in *XJoinIndexStatus) DeepCopyInto(out *XJoinIndexStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.LastAction != nil {
+		in, out := &in.LastAction, &out.LastAction
+		*out = new(XJoinAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveVersionValidationSkippedTime != nil {
+		in, out := &in.ActiveVersionValidationSkippedTime, &out.ActiveVersionValidationSkippedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PreviousVersionExpiration != nil {
+		in, out := &in.PreviousVersionExpiration, &out.PreviousVersionExpiration
+		*out = (*in).DeepCopy()
+	}
+	if in.RefreshSummary != nil {
+		in, out := &in.RefreshSummary, &out.RefreshSummary
+		*out = new(RefreshSummary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexStatus.
@@ -516,12 +1037,47 @@ func (in *XJoinIndexStatus) DeepCopy() *XJoinIndexStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinIndexValidation) DeepCopyInto(out *XJoinIndexValidation) {
+	*out = *in
+	if in.AttemptsThreshold != nil {
+		in, out := &in.AttemptsThreshold, &out.AttemptsThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.PercentageThreshold != nil {
+		in, out := &in.PercentageThreshold, &out.PercentageThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.RefreshAttemptsThreshold != nil {
+		in, out := &in.RefreshAttemptsThreshold, &out.RefreshAttemptsThreshold
+		*out = new(int)
+		**out = **in
+	}
+	if in.InitPercentageThreshold != nil {
+		in, out := &in.InitPercentageThreshold, &out.InitPercentageThreshold
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexValidation.
+func (in *XJoinIndexValidation) DeepCopy() *XJoinIndexValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinIndexValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinIndexValidator) DeepCopyInto(out *XJoinIndexValidator) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -578,6 +1134,16 @@ func (in *XJoinIndexValidatorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinIndexValidatorSpec) DeepCopyInto(out *XJoinIndexValidatorSpec) {
 	*out = *in
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(WorkloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(XJoinIndexValidation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexValidatorSpec.
@@ -594,6 +1160,13 @@ func (in *XJoinIndexValidatorSpec) DeepCopy() *XJoinIndexValidatorSpec {
 func (in *XJoinIndexValidatorStatus) DeepCopyInto(out *XJoinIndexValidatorStatus) {
 	*out = *in
 	in.ValidationResponse.DeepCopyInto(&out.ValidationResponse)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexValidatorStatus.
@@ -606,6 +1179,36 @@ func (in *XJoinIndexValidatorStatus) DeepCopy() *XJoinIndexValidatorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XJoinIndexWorkloads) DeepCopyInto(out *XJoinIndexWorkloads) {
+	*out = *in
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(WorkloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subgraph != nil {
+		in, out := &in.Subgraph, &out.Subgraph
+		*out = new(WorkloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validator != nil {
+		in, out := &in.Validator, &out.Validator
+		*out = new(WorkloadSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XJoinIndexWorkloads.
+func (in *XJoinIndexWorkloads) DeepCopy() *XJoinIndexWorkloads {
+	if in == nil {
+		return nil
+	}
+	out := new(XJoinIndexWorkloads)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *XJoinPipeline) DeepCopyInto(out *XJoinPipeline) {
 	*out = *in
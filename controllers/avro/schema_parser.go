@@ -1,7 +1,6 @@
 package avro
 
 import (
-	"container/list"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -10,10 +9,12 @@ import (
 
 	"github.com/go-errors/errors"
 	. "github.com/redhatinsights/xjoin-go-lib/pkg/avro"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	"github.com/redhatinsights/xjoin-operator/controllers/log"
 	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
 	"github.com/riferrei/srclient"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -31,6 +32,9 @@ type IndexAvroSchema struct {
 	ESProperties     string
 	JSONFields       []string
 	SourceTopics     string
+
+	// KafkaTopicDataSources are the names of the referenced datasources that are read from existing Kafka topics
+	KafkaTopicDataSources []string
 }
 
 type IndexAvroSchemaParser struct {
@@ -41,6 +45,8 @@ type IndexAvroSchemaParser struct {
 	SchemaNamespace string
 	Log             log.Log
 	SchemaRegistry  *schemaregistry.ConfluentClient
+
+	logicalTypes map[string]string //Avro logicalType of the expanded schema's fields by field path
 }
 
 // Parse AvroSchema string into various structures represented by IndexAvroSchema to be used in component creation
@@ -50,7 +56,22 @@ func (d *IndexAvroSchemaParser) Parse() (indexAvroSchema IndexAvroSchema, err er
 		return indexAvroSchema, errors.Wrap(err, 0)
 	}
 
-	indexAvroSchema.SourceTopics = d.ParseSourceTopics(indexAvroSchema.References)
+	var sourceTopics []string
+	for _, reference := range indexAvroSchema.References {
+		kafkaTopicSource, err := d.kafkaTopicSource(reference)
+		if err != nil {
+			return indexAvroSchema, errors.Wrap(err, 0)
+		}
+
+		if kafkaTopicSource != nil {
+			sourceTopics = append(sourceTopics, kafkaTopicSource.Topic)
+			indexAvroSchema.KafkaTopicDataSources = append(
+				indexAvroSchema.KafkaTopicDataSources, strings.Split(reference.Name, ".")[1])
+		} else {
+			sourceTopics = append(sourceTopics, strings.ToLower(d.AvroSubjectToKafkaTopic(reference.Subject)))
+		}
+	}
+	indexAvroSchema.SourceTopics = strings.Join(sourceTopics, ",")
 
 	indexAvroSchema.AvroSchema, err = d.expandReferences(d.AvroSchema, indexAvroSchema.References)
 	if err != nil {
@@ -215,8 +236,7 @@ func (d *IndexAvroSchemaParser) parseAvroSchemaReferences() (references []srclie
 			return references, errors.Wrap(err, 0)
 		}
 		statusMap := status.(map[string]interface{})
-		//version := statusMap["activeVersion"] //TODO temporary
-		version := statusMap["refreshingVersion"]
+		version := statusMap["activeVersion"]
 		if version == nil {
 			err = errors.New("activeVersion missing from datasource.status")
 			return references, errors.Wrap(err, 0)
@@ -248,7 +268,7 @@ func (d *IndexAvroSchemaParser) transformToES(avroSchema Schema) (properties str
 		return properties, jsonFields, errors.Wrap(errors.New("fields property is missing from avro schema"), 0)
 	}
 
-	esProperties, jsonFields, err := parseAvroFields(avroSchema.Fields, list.List{})
+	esProperties, jsonFields, err := parseAvroFields(avroSchema.Fields, "", d.logicalTypes)
 	if err != nil {
 		return properties, jsonFields, errors.Wrap(err, 0)
 	}
@@ -261,13 +281,15 @@ func (d *IndexAvroSchemaParser) transformToES(avroSchema Schema) (properties str
 	return string(propertiesBytes), jsonFields, nil
 }
 
-func parseAvroFields(avroFields []Field, parent list.List) (map[string]interface{}, []string, error) {
+// parseAvroFields maps the avroFields to Elasticsearch properties. The fields' paths are prefixed by the path of their
+// parent field.
+func parseAvroFields(
+	avroFields []Field, parent string, logicalTypes map[string]string) (map[string]interface{}, []string, error) {
+
 	esProperties := make(map[string]interface{})
 	var jsonFields []string
 
 	for _, avroField := range avroFields {
-		esProperty := make(map[string]interface{})
-
 		if avroField.XJoinIndex != nil && !*avroField.XJoinIndex {
 			continue
 		}
@@ -280,21 +302,19 @@ func parseAvroFields(avroFields []Field, parent list.List) (map[string]interface
 			avroFieldType = avroField.Type[0]
 		}
 
-		esProperty["type"] = avroTypeToElasticsearchType(avroFieldType)
-		esProperty, err := parseXJoinFlags(avroFieldType, esProperty)
+		logicalType := logicalTypes[parent+avroField.Name]
+		esProperty, err := avroTypeToElasticsearchType(avroFieldType, logicalType)
+		if err != nil {
+			return nil, nil, errors.Wrap(fmt.Errorf("unable to map field %s: %w", avroField.Name, err), 0)
+		}
+		esProperty, err = parseXJoinFlags(avroFieldType, esProperty)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, 0)
 		}
 
 		//find json fields which need to be transformed from a string
-		if avroFieldType.XJoinType == "json" && avroFieldType.Type == "string" {
-			jsonFieldName := ""
-			for parentField := parent.Front(); parentField != nil; parentField = parentField.Next() {
-				jsonFieldName = parentField.Value.(string) + "."
-
-			}
-			jsonFieldName = jsonFieldName + avroField.Name
-			jsonFields = append(jsonFields, jsonFieldName)
+		if elasticsearchTypeName(avroFieldType, logicalType) == "json" && avroFieldType.Type == "string" {
+			jsonFields = append(jsonFields, parent+avroField.Name)
 		}
 
 		//recurse through nested object types
@@ -309,10 +329,8 @@ func parseAvroFields(avroFields []Field, parent list.List) (map[string]interface
 			}
 
 			if nestedFields != nil {
-				newParent := parent
-				newParent.PushFront(avroField.Name)
 				nestedProperties, nestedJsonFields, err :=
-					parseAvroFields(nestedFields, newParent)
+					parseAvroFields(nestedFields, parent+avroField.Name+".", logicalTypes)
 				if err != nil {
 					return nil, nil, errors.Wrap(err, 0)
 				}
@@ -326,30 +344,81 @@ func parseAvroFields(avroFields []Field, parent list.List) (map[string]interface
 	return esProperties, jsonFields, nil
 }
 
-func avroTypeToElasticsearchType(avroType Type) (esType string) {
-	typeString := avroType.XJoinType
-	if avroType.XJoinType == "array" {
-		typeString = avroType.Items[0].Type //TODO: handle multiple items?
-	}
-
-	switch strings.ToLower(typeString) {
-	case "date_nanos":
-		esType = "date_nanos"
-	case "string":
-		esType = "keyword"
-	case "boolean":
-		esType = "boolean"
-	case "json":
-		esType = "object"
-	case "record":
-		esType = "object"
-	case "reference":
-		esType = "object"
-	default:
-		esType = "keyword" //TODO should this be default or error?
+// elasticsearchTypes maps each xjoin.type, or Avro type when xjoin.type is not set, to an Elasticsearch mapping
+var elasticsearchTypes = map[string]map[string]interface{}{
+	"string":     {"type": "keyword"},
+	"enum":       {"type": "keyword"},
+	"uuid":       {"type": "keyword"},
+	"boolean":    {"type": "boolean"},
+	"byte":       {"type": "byte"},
+	"short":      {"type": "short"},
+	"int":        {"type": "integer"},
+	"long":       {"type": "long"},
+	"float":      {"type": "float"},
+	"double":     {"type": "double"},
+	"decimal":    {"type": "double"},
+	"bytes":      {"type": "binary"},
+	"date_nanos": {"type": "date_nanos"},
+	"json":       {"type": "object"},
+	"record":     {"type": "object"},
+	"reference":  {"type": "object"},
+	"map":        {"type": "flattened"}, //map keys are arbitrary, they are not added to the mapping as fields
+
+	"timestamp-millis": {"type": "date", "format": "epoch_millis"},
+	//Elasticsearch has no format for epoch microseconds or days, the values are kept as numbers for range queries
+	"timestamp-micros": {"type": "long"},
+	"date":             {"type": "integer"},
+}
+
+// connectLogicalTypes are the logical types of the Kafka Connect schema names used by Debezium
+var connectLogicalTypes = map[string]string{
+	"io.debezium.time.Timestamp":            "timestamp-millis",
+	"io.debezium.time.MicroTimestamp":       "timestamp-micros",
+	"io.debezium.time.ZonedTimestamp":       "date_nanos",
+	"io.debezium.time.Date":                 "date",
+	"io.debezium.data.Uuid":                 "uuid",
+	"io.debezium.data.Json":                 "json",
+	"org.apache.kafka.connect.data.Decimal": "decimal",
+}
+
+// elasticsearchTypeName is the key of the avroType's mapping in elasticsearchTypes. The logical type of a field is
+// set through xjoin.type, fields without xjoin.type are mapped by their connect.name, Avro logicalType or Avro type.
+// Unknown logicalTypes are ignored, like Avro readers do.
+func elasticsearchTypeName(avroType Type, logicalType string) string {
+	if avroType.XJoinType != "" {
+		return avroType.XJoinType
+	}
+	if connectType := connectLogicalTypes[avroType.ConnectName]; connectType != "" {
+		return connectType
+	}
+	if _, ok := elasticsearchTypes[logicalType]; ok && strings.ToLower(avroType.Type) != "array" {
+		return logicalType
+	}
+	return avroType.Type
+}
+
+// avroTypeToElasticsearchType is the Elasticsearch mapping of a field with the avroType and Avro logicalType. Arrays
+// are mapped to the type of their items, the logicalType of an array field is the logicalType of its items.
+func avroTypeToElasticsearchType(avroType Type, logicalType string) (esProperty map[string]interface{}, err error) {
+	typeString := elasticsearchTypeName(avroType, logicalType)
+
+	if strings.ToLower(typeString) == "array" {
+		if len(avroType.Items) == 0 {
+			return nil, errors.Wrap(errors.New("items are missing from array type"), 0)
+		}
+		return avroTypeToElasticsearchType(avroType.Items[0], logicalType) //TODO: handle multiple items?
 	}
 
-	return
+	esType, ok := elasticsearchTypes[strings.ToLower(typeString)]
+	if !ok {
+		return nil, errors.Wrap(fmt.Errorf("type %s has no Elasticsearch mapping", typeString), 0)
+	}
+
+	esProperty = make(map[string]interface{})
+	for key, value := range esType {
+		esProperty[key] = value
+	}
+	return esProperty, nil
 }
 
 func parseXJoinFlags(avroFieldType Type, esProperty map[string]interface{}) (map[string]interface{}, error) {
@@ -380,6 +449,12 @@ func (d *IndexAvroSchemaParser) expandReferences(baseSchema string, references [
 		return fullSchema, errors.Wrap(err, 0)
 	}
 
+	d.logicalTypes = make(map[string]string)
+	err = addLogicalTypes(baseSchema, "", d.logicalTypes)
+	if err != nil {
+		return fullSchema, errors.Wrap(err, 0)
+	}
+
 	//TODO handle type array instead of assuming type[0]
 	for idx, field := range fullSchema.Fields {
 		if field.Type[0].XJoinType == "reference" {
@@ -397,6 +472,10 @@ func (d *IndexAvroSchemaParser) expandReferences(baseSchema string, references [
 			if err != nil {
 				return fullSchema, errors.Wrap(err, 0)
 			}
+			err = addLogicalTypes(refSchemaString, field.Name+".", d.logicalTypes)
+			if err != nil {
+				return fullSchema, errors.Wrap(err, 0)
+			}
 
 			refSchemaType.XJoinType = field.Type[0].XJoinType
 			refSchemaType.Name = ref.Name
@@ -407,6 +486,49 @@ func (d *IndexAvroSchemaParser) expandReferences(baseSchema string, references [
 	return
 }
 
+// addLogicalTypes adds the Avro logicalType of each field of schemaString to logicalTypes, keyed by the field's path
+// prefixed by parent. The avro Type doesn't keep logicalType, so it is read from the raw schema. Optional fields use the
+// same branch of their union as parseAvroFields, array fields use the logicalType of their items.
+func addLogicalTypes(schemaString string, parent string, logicalTypes map[string]string) error {
+	var schema map[string]interface{}
+	err := json.Unmarshal([]byte(schemaString), &schema)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	addFieldLogicalTypes(schema["fields"], parent, logicalTypes)
+	return nil
+}
+
+func addFieldLogicalTypes(fields interface{}, parent string, logicalTypes map[string]string) {
+	fieldList, _ := fields.([]interface{})
+	for _, field := range fieldList {
+		fieldMap, _ := field.(map[string]interface{})
+		name, _ := fieldMap["name"].(string)
+
+		fieldType := fieldMap["type"]
+		if union, ok := fieldType.([]interface{}); ok && len(union) > 1 {
+			fieldType = union[1]
+		} else if ok && len(union) == 1 {
+			fieldType = union[0]
+		}
+		typeMap, ok := fieldType.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		logicalTypeMap := typeMap
+		if items, ok := typeMap["items"].(map[string]interface{}); ok && typeMap["type"] == "array" {
+			logicalTypeMap = items
+		}
+		if logicalType, ok := logicalTypeMap["logicalType"].(string); ok {
+			logicalTypes[parent+name] = logicalType
+		}
+
+		addFieldLogicalTypes(typeMap["fields"], parent+name+".", logicalTypes)
+		addFieldLogicalTypes(typeMap["xjoin.fields"], parent+name+".", logicalTypes)
+	}
+}
+
 func findReferenceByType(references []srclient.Reference, refType string) (srclient.Reference, error) {
 	for _, ref := range references {
 		if ref.Name == refType {
@@ -422,13 +544,20 @@ func (d *IndexAvroSchemaParser) AvroSubjectToKafkaTopic(avroSubject string) (kaf
 	return strings.Split(avroSubject, "-")[0]
 }
 
-func (d *IndexAvroSchemaParser) ParseSourceTopics(references []srclient.Reference) (sourceTopics string) {
-	for idx, reference := range references {
-		if idx != 0 {
-			sourceTopics = sourceTopics + ","
-		}
-
-		sourceTopics = sourceTopics + strings.ToLower(d.AvroSubjectToKafkaTopic(reference.Subject))
+// kafkaTopicSource is the existing Kafka topic of the referenced datasource pipeline, nil when it reads a database
+func (d *IndexAvroSchemaParser) kafkaTopicSource(reference srclient.Reference) (*v1alpha1.KafkaTopicSource, error) {
+	//the reference's subject is <datasource pipeline name>-value
+	dataSourcePipelineName := strings.TrimSuffix(
+		strings.TrimPrefix(reference.Subject, strings.ToLower(common.DataSourcePipelineGVK.Kind)+"."), "-value")
+
+	dataSourcePipeline := &v1alpha1.XJoinDataSourcePipeline{}
+	err := d.Client.Get(d.Context, client.ObjectKey{Name: dataSourcePipelineName, Namespace: d.Namespace},
+		dataSourcePipeline)
+	if k8errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, 0)
 	}
-	return
+
+	return dataSourcePipeline.KafkaTopicSource(), nil
 }
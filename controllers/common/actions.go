@@ -0,0 +1,164 @@
+package common
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HandleAction performs the first action requested via an annotation on the instance. A refresh is only validated
+// here, the returned refreshReason is passed to Reconcile to start it. The returned annotation should be removed from
+// the instance after its status is updated.
+func (r *Reconciler) HandleAction() (annotation string, refreshReason string, err error) {
+	if r.instance.GetDeletionTimestamp() != nil {
+		return "", "", nil
+	}
+
+	annotations := r.instance.GetAnnotations()
+	for _, annotation = range []string{
+		v1alpha1.RollbackAnnotation, v1alpha1.PromoteRefreshingAnnotation, v1alpha1.ApproveRefreshingAnnotation,
+		v1alpha1.RefreshAnnotation} {
+
+		value, ok := annotations[annotation]
+		if !ok {
+			continue
+		} else if annotation == v1alpha1.RefreshAnnotation && r.refreshDeferred {
+			r.log.Info("Keeping the refresh annotation pending", "value", value)
+			continue
+		}
+
+		switch annotation {
+		case v1alpha1.RollbackAnnotation:
+			err = r.rollback(value)
+		case v1alpha1.PromoteRefreshingAnnotation:
+			err = r.promoteRefreshing(value)
+		case v1alpha1.ApproveRefreshingAnnotation:
+			r.approveRefreshing(value)
+		case v1alpha1.RefreshAnnotation:
+			refreshReason = r.refresh(value)
+		}
+		if err != nil {
+			return "", "", errors.Wrap(err, 0)
+		}
+
+		return annotation, refreshReason, nil
+	}
+
+	return "", "", nil
+}
+
+// DeferRefresh leaves the refresh annotation on the instance for a later reconcile instead of handling it,
+// e.g. while an incremental snapshot re-reads the datasource's table. The other annotations are still handled.
+func (r *Reconciler) DeferRefresh() {
+	r.refreshDeferred = true
+}
+
+// rollback discards the refreshing version and keeps the active version.
+// When there is no refreshing version it switches back to the previous version.
+func (r *Reconciler) rollback(value string) (err error) {
+	if value != "true" {
+		r.recordAction(v1alpha1.RollbackAnnotation, value, "ignored, the value must be true", false)
+		return
+	} else if r.instance.GetRefreshingVersion() != "" && r.instance.GetActiveVersion() == "" {
+		r.recordAction(v1alpha1.RollbackAnnotation, value, "ignored, there is no active version to keep", false)
+		return
+	} else if r.instance.GetRefreshingVersion() != "" {
+		message := fmt.Sprintf("discarded refreshing version %s, kept active version %s",
+			r.instance.GetRefreshingVersion(), r.instance.GetActiveVersion())
+		r.instance.SetRefreshingVersion("")
+		r.instance.SetRefreshingVersionIsValid(false)
+		r.recordAction(v1alpha1.RollbackAnnotation, value, message, true)
+		return
+	} else if r.instance.GetPreviousVersion() != "" {
+		message := fmt.Sprintf("switched from active version %s back to previous version %s",
+			r.instance.GetActiveVersion(), r.instance.GetPreviousVersion())
+		r.instance.SetActiveVersion(r.instance.GetPreviousVersion())
+		r.instance.SetActiveVersionIsValid(true)
+		r.instance.SetActiveVersionValidationSkipped(false)
+		r.instance.SetPreviousVersion("", nil)
+		r.recordAction(v1alpha1.RollbackAnnotation, value, message, true)
+		return
+	}
+
+	r.recordAction(v1alpha1.RollbackAnnotation, value, "ignored, there is no refreshing or previous version", false)
+	return
+}
+
+// promoteRefreshing replaces the active version with the refreshing version without waiting for it to be valid
+func (r *Reconciler) promoteRefreshing(value string) (err error) {
+	if value != "true" {
+		r.recordAction(v1alpha1.PromoteRefreshingAnnotation, value, "ignored, the value must be true", false)
+		return
+	} else if r.instance.GetRefreshingVersion() == "" {
+		r.recordAction(v1alpha1.PromoteRefreshingAnnotation, value, "ignored, there is no refreshing version", false)
+		return
+	}
+
+	message := fmt.Sprintf("promoted refreshing version %s without validation", r.instance.GetRefreshingVersion())
+	err = r.promoteRefreshingVersion()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	r.instance.SetActiveVersionValidationSkipped(true)
+	r.recordAction(v1alpha1.PromoteRefreshingAnnotation, value, message, true)
+	return
+}
+
+// approveRefreshing allows a refreshing version that is awaiting approval to replace the active version
+func (r *Reconciler) approveRefreshing(value string) {
+	if !r.awaitingApproval() {
+		r.recordAction(v1alpha1.ApproveRefreshingAnnotation, value,
+			"ignored, there is no refreshing version awaiting approval", false)
+		return
+	} else if value != r.instance.GetRefreshingVersion() {
+		r.recordAction(v1alpha1.ApproveRefreshingAnnotation, value,
+			"ignored, the value must be the refreshing version "+r.instance.GetRefreshingVersion(), false)
+		return
+	}
+
+	r.instance.SetApprovedVersion(value)
+	r.recordAction(v1alpha1.ApproveRefreshingAnnotation, value, "approved refreshing version "+value, true)
+}
+
+// refresh returns the reason to force a refresh unless the refresh with this value was already started
+func (r *Reconciler) refresh(value string) (refreshReason string) {
+	lastAction := r.instance.GetLastAction()
+	if lastAction != nil && lastAction.Annotation == v1alpha1.RefreshAnnotation && lastAction.Value == value {
+		return ""
+	} else if r.instance.GetActiveVersion() != "" &&
+		!r.instance.GetActiveVersionIsValid() &&
+		r.instance.GetRefreshingVersion() != "" {
+		r.recordAction(v1alpha1.RefreshAnnotation, value, "ignored, a refresh is already in progress", false)
+		return ""
+	}
+
+	r.recordAction(v1alpha1.RefreshAnnotation, value, "started a refresh", true)
+	return "refresh requested via the " + v1alpha1.RefreshAnnotation + " annotation"
+}
+
+func (r *Reconciler) recordAction(annotation string, value string, message string, handled bool) {
+	r.log.Info("Handled annotation", "annotation", annotation, "value", value, "message", message)
+	r.instance.SetLastAction(v1alpha1.XJoinAction{
+		Annotation: annotation,
+		Value:      value,
+		Message:    message,
+		Time:       metav1.Now(),
+	})
+
+	if handled {
+		r.recorder.Event(r.instance, corev1.EventTypeNormal, "ActionHandled", annotation+": "+message)
+	} else {
+		r.recorder.Event(r.instance, corev1.EventTypeWarning, "ActionIgnored", annotation+": "+message)
+	}
+}
+
+// ValidatedSinceSkipped is true when the validation of the active version was skipped by the promote-refreshing
+// annotation and a validation result was recorded since, so the skip no longer applies
+func ValidatedSinceSkipped(skipped bool, skippedTime *metav1.Time, lastValidationTime *metav1.Time) bool {
+	if !skipped || lastValidationTime == nil {
+		return false
+	}
+	return skippedTime == nil || lastValidationTime.After(skippedTime.Time)
+}
@@ -0,0 +1,122 @@
+package common
+
+import (
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxConditionMessageLength is the maximum length of a condition's message accepted by the API server
+const MaxConditionMessageLength = 32768
+
+// conditionMessage cuts message to MaxConditionMessageLength without splitting a character
+func conditionMessage(message string) string {
+	if len(message) <= MaxConditionMessageLength {
+		return message
+	}
+
+	suffix := "... (truncated)"
+	end := MaxConditionMessageLength - len(suffix)
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end] + suffix
+}
+
+// SetComponentsHealthyCondition records the problems returned by ComponentManager.CheckForDeviations
+func SetComponentsHealthyCondition(conditions *[]metav1.Condition, problems []error) {
+	if len(problems) == 0 {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    v1alpha1.ComponentsHealthyConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "NoDeviations",
+			Message: "All components match their expected state",
+		})
+		return
+	}
+
+	//list as many problems as fit in the message, the remaining problems are only counted
+	var messages []string
+	length := 0
+	for index, problem := range problems {
+		remaining := "; and " + strconv.Itoa(len(problems)-index) + " more deviations"
+		if length+len(problem.Error())+len("; ")+len(remaining) > MaxConditionMessageLength && index > 0 {
+			messages = append(messages, strings.TrimPrefix(remaining, "; "))
+			break
+		}
+		messages = append(messages, problem.Error())
+		length += len(problem.Error()) + len("; ")
+	}
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    v1alpha1.ComponentsHealthyConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "DeviationFound",
+		Message: conditionMessage(strings.Join(messages, "; ")),
+	})
+}
+
+// SetAvroSchemaParsedCondition records whether the avroSchema could be parsed and mapped to Elasticsearch
+func SetAvroSchemaParsedCondition(conditions *[]metav1.Condition, err error) {
+	if err == nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    v1alpha1.AvroSchemaParsedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Parsed",
+			Message: "The avroSchema was parsed",
+		})
+		return
+	}
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    v1alpha1.AvroSchemaParsedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ParseFailed",
+		Message: conditionMessage(err.Error()),
+	})
+}
+
+// SetSchemaEvolvedCondition records whether the components were evolved to an updated avroSchema
+func SetSchemaEvolvedCondition(conditions *[]metav1.Condition, err error) {
+	if err == nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    v1alpha1.SchemaEvolvedConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  "Evolved",
+			Message: "All components were evolved to the avroSchema",
+		})
+		return
+	}
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:    v1alpha1.SchemaEvolvedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "EvolutionFailed",
+		Message: conditionMessage(err.Error()),
+	})
+}
+
+// ComponentsAreHealthy is false only when the ComponentsHealthy condition is present and false
+func ComponentsAreHealthy(conditions []metav1.Condition) bool {
+	return !meta.IsStatusConditionFalse(conditions, v1alpha1.ComponentsHealthyConditionType)
+}
+
+// SetPausedCondition records whether reconciliation of an XJoinIndex or XJoinDataSource is paused
+func SetPausedCondition(instance XJoinObject, paused bool) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.PausedConditionType,
+		ObservedGeneration: instance.GetGeneration(),
+		Status:             metav1.ConditionFalse,
+		Reason:             "NotPaused",
+		Message:            "Reconciliation is active",
+	}
+	if paused {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "PausedBySpec"
+		condition.Message = "Reconciliation is paused by spec.pause"
+	}
+	instance.SetCondition(condition)
+}
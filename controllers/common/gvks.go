@@ -32,6 +32,12 @@ var DataSourcePipelineGVK = schema.GroupVersionKind{
 	Version: "v1alpha1",
 }
 
+var DataSourceValidatorGVK = schema.GroupVersionKind{
+	Group:   "xjoin.cloud.redhat.com",
+	Kind:    "XJoinDataSourceValidator",
+	Version: "v1alpha1",
+}
+
 var DeploymentGVK = schema.GroupVersionKind{
 	Group:   "apps",
 	Kind:    "Deployment",
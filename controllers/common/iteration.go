@@ -117,6 +117,21 @@ func (i *Iteration) AddFinalizer(finalizer string) error {
 	return nil
 }
 
+// RemoveAnnotation removes an annotation from the instance after the action it requested has been handled
+func (i *Iteration) RemoveAnnotation(annotation string) error {
+	annotations := i.Instance.GetAnnotations()
+	if _, ok := annotations[annotation]; !ok {
+		return nil
+	}
+
+	delete(annotations, annotation)
+	i.Instance.SetAnnotations(annotations)
+
+	ctx, cancel := utils.DefaultContext()
+	defer cancel()
+	return i.Client.Update(ctx, i.Instance)
+}
+
 func (i *Iteration) ReconcileChild(child Child) (err error) {
 	//build an array and map of expected child versions (active, refreshing)
 	//the map value will be set to true when an expected child is found
@@ -130,6 +145,10 @@ func (i *Iteration) ReconcileChild(child Child) (err error) {
 		expectedChildrenMap[child.GetParentInstance().GetRefreshingVersion()] = false
 		expectedChildrenArray = append(expectedChildrenArray, child.GetParentInstance().GetRefreshingVersion())
 	}
+	//the previous version is kept during its retention period but is not recreated
+	if child.GetParentInstance().GetPreviousVersion() != "" {
+		expectedChildrenArray = append(expectedChildrenArray, child.GetParentInstance().GetPreviousVersion())
+	}
 
 	//retrieve a list of children for this datasource.name
 	children := &unstructured.UnstructuredList{}
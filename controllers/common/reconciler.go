@@ -2,8 +2,11 @@ package common
 
 import (
 	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	logger "github.com/redhatinsights/xjoin-operator/controllers/log"
 	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"strconv"
 	"time"
 )
@@ -16,6 +19,7 @@ type ReconcilerMethods interface {
 	StartRefreshing(string) error
 	Refreshing() error
 	RefreshComplete() error
+	AwaitingApproval() (v1alpha1.RefreshSummary, error)
 	Scrub() []error
 }
 
@@ -23,13 +27,19 @@ type Reconciler struct {
 	methods  ReconcilerMethods
 	instance XJoinObject
 	log      logger.Log
+	recorder record.EventRecorder
+
+	refreshDeferred bool
 }
 
-func NewReconciler(methods ReconcilerMethods, instance XJoinObject, log logger.Log) *Reconciler {
+func NewReconciler(
+	methods ReconcilerMethods, instance XJoinObject, log logger.Log, recorder record.EventRecorder) *Reconciler {
+
 	return &Reconciler{
 		methods:  methods,
 		instance: instance,
 		log:      log,
+		recorder: recorder,
 	}
 }
 
@@ -51,15 +61,27 @@ func (r *Reconciler) DoRefresh() (err error) {
 }
 
 const (
-	START_REFRESH    string = "START_REFRESH"
-	REFRESHING       string = "REFRESHING"
-	NEW              string = "NEW"
-	REMOVED          string = "REMOVED"
-	INITIAL_SYNC     string = "INITIAL_SYNC"
-	VALID            string = "VALID"
-	REFRESH_COMPLETE string = "REFRESH_COMPLETE"
+	START_REFRESH     string = "START_REFRESH"
+	REFRESHING        string = "REFRESHING"
+	NEW               string = "NEW"
+	REMOVED           string = "REMOVED"
+	INITIAL_SYNC      string = "INITIAL_SYNC"
+	VALID             string = "VALID"
+	REFRESH_COMPLETE  string = "REFRESH_COMPLETE"
+	AWAITING_APPROVAL string = "AWAITING_APPROVAL"
+	INVALID           string = "INVALID"
 )
 
+// awaitingApproval is true when a valid refreshing version would replace the active version
+// but the refresh policy requires the swap to be approved first
+func (r *Reconciler) awaitingApproval() bool {
+	return r.instance.RefreshRequiresApproval() &&
+		r.instance.GetActiveVersion() != "" &&
+		r.instance.GetRefreshingVersion() != "" &&
+		r.instance.GetRefreshingVersionIsValid() &&
+		r.instance.GetApprovedVersion() != r.instance.GetRefreshingVersion()
+}
+
 func (r *Reconciler) getState(specHash string) string {
 	if r.instance.GetDeletionTimestamp() != nil {
 		return REMOVED
@@ -74,6 +96,8 @@ func (r *Reconciler) getState(specHash string) string {
 		!r.instance.GetRefreshingVersionIsValid() &&
 		r.instance.GetRefreshingVersion() != "" {
 		return INITIAL_SYNC
+	} else if r.awaitingApproval() {
+		return AWAITING_APPROVAL
 	} else if r.instance.GetRefreshingVersion() != "" &&
 		r.instance.GetRefreshingVersionIsValid() {
 		return REFRESH_COMPLETE
@@ -90,7 +114,10 @@ func (r *Reconciler) getState(specHash string) string {
 	}
 }
 
-func (r *Reconciler) Reconcile(forceRefresh bool) (err error) {
+// Reconcile moves the instance to its next state. A refresh is started when forceRefreshReason is not empty.
+func (r *Reconciler) Reconcile(forceRefreshReason string) (err error) {
+	r.expirePreviousVersion()
+
 	//Scrub orphaned resources
 	errs := r.methods.Scrub()
 	if len(errs) > 0 {
@@ -107,7 +134,7 @@ func (r *Reconciler) Reconcile(forceRefresh bool) (err error) {
 	}
 
 	state := r.getState(specHash)
-	if state != REFRESHING && forceRefresh {
+	if state != REFRESHING && forceRefreshReason != "" {
 		state = START_REFRESH
 	}
 
@@ -125,6 +152,15 @@ func (r *Reconciler) Reconcile(forceRefresh bool) (err error) {
 		// or force_refresh is true
 		r.log.Info("STATE: START REFRESH")
 
+		refreshReason := forceRefreshReason
+		if refreshReason == "" && r.instance.GetSpecHash() != specHash {
+			refreshReason = "spec changed"
+		} else if refreshReason == "" {
+			refreshReason = "active version " + r.instance.GetActiveVersion() + " is invalid"
+		}
+		r.log.Info("Starting refresh", "reason", refreshReason)
+		r.instance.SetRefreshReason(refreshReason)
+
 		refreshingVersion := r.Version()
 		r.instance.SetRefreshingVersion(refreshingVersion)
 
@@ -164,18 +200,137 @@ func (r *Reconciler) Reconcile(forceRefresh bool) (err error) {
 		if err != nil {
 			return errors.Wrap(err, 0)
 		}
+	case AWAITING_APPROVAL:
+		r.log.Info("STATE: AWAITING APPROVAL")
+
+		summary, err := r.methods.AwaitingApproval()
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		r.instance.SetRefreshSummary(&summary)
 	case REFRESH_COMPLETE:
 		r.log.Info("STATE: REFRESH COMPLETE")
-		err = r.methods.RefreshComplete()
+		err = r.promoteRefreshingVersion()
 		if err != nil {
 			return errors.Wrap(err, 0)
 		}
+		r.instance.SetActiveVersionValidationSkipped(false)
+	}
 
-		r.instance.SetActiveVersion(r.instance.GetRefreshingVersion())
-		r.instance.SetActiveVersionIsValid(true)
-		r.instance.SetRefreshingVersion("")
-		r.instance.SetRefreshingVersionIsValid(false)
+	if state != AWAITING_APPROVAL {
+		r.instance.SetRefreshSummary(nil)
 	}
 
+	r.setStatus()
 	return nil
 }
+
+// promoteRefreshingVersion removes the active version and replaces it with the refreshing version
+func (r *Reconciler) promoteRefreshingVersion() (err error) {
+	err = r.methods.RefreshComplete()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	r.instance.SetActiveVersion(r.instance.GetRefreshingVersion())
+	r.instance.SetActiveVersionIsValid(true)
+	r.instance.SetRefreshingVersion("")
+	r.instance.SetRefreshingVersionIsValid(false)
+	return
+}
+
+// expirePreviousVersion stops keeping the previous version once its retention period has passed.
+// Its child and components are then removed like any other orphaned version.
+func (r *Reconciler) expirePreviousVersion() {
+	previousVersion := r.instance.GetPreviousVersion()
+	expiration := r.instance.GetPreviousVersionExpiration()
+	if previousVersion == "" || (expiration != nil && time.Now().Before(expiration.Time)) {
+		return
+	}
+
+	r.log.Info("Retention period of previous version expired", "version", previousVersion)
+	r.instance.SetPreviousVersion("", nil)
+}
+
+// phase summarizes the instance's versions after a state transition using the reconciler's state names
+func (r *Reconciler) phase() string {
+	switch {
+	case r.instance.GetDeletionTimestamp() != nil:
+		return REMOVED
+	case r.instance.GetActiveVersion() == "" && r.instance.GetRefreshingVersion() == "":
+		return NEW
+	case r.instance.GetActiveVersion() == "":
+		return INITIAL_SYNC
+	case r.awaitingApproval():
+		return AWAITING_APPROVAL
+	case r.instance.GetRefreshingVersion() != "":
+		return REFRESHING
+	case r.instance.GetActiveVersionIsValid():
+		return VALID
+	default:
+		return INVALID
+	}
+}
+
+// setStatus sets the phase, observed generation and Ready, Refreshing, Degraded conditions
+func (r *Reconciler) setStatus() {
+	generation := r.instance.GetGeneration()
+	activeVersion := r.instance.GetActiveVersion()
+	refreshingVersion := r.instance.GetRefreshingVersion()
+
+	r.instance.SetPhase(r.phase())
+	r.instance.SetObservedGeneration(generation)
+
+	ready := metav1.Condition{
+		Type:               v1alpha1.ReadyConditionType,
+		ObservedGeneration: generation,
+	}
+	degraded := metav1.Condition{
+		Type:               v1alpha1.DegradedConditionType,
+		ObservedGeneration: generation,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ActiveVersionValid",
+		Message:            "The active version is valid",
+	}
+	switch {
+	case activeVersion == "":
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "InitialSync"
+		ready.Message = "Waiting for the initial sync of version " + refreshingVersion
+		degraded.Reason = "InitialSync"
+		degraded.Message = "There is no active version yet"
+	case r.instance.GetActiveVersionIsValid():
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = "ActiveVersionValid"
+		ready.Message = "Active version " + activeVersion + " is valid"
+	default:
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ActiveVersionInvalid"
+		ready.Message = "Active version " + activeVersion + " is invalid"
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "ActiveVersionInvalid"
+		degraded.Message = ready.Message
+	}
+
+	refreshing := metav1.Condition{
+		Type:               v1alpha1.RefreshingConditionType,
+		ObservedGeneration: generation,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NotRefreshing",
+		Message:            "No version is being refreshed",
+	}
+	if r.awaitingApproval() {
+		refreshing.Status = metav1.ConditionTrue
+		refreshing.Reason = "AwaitingApproval"
+		refreshing.Message = "Refreshing version " + refreshingVersion + " is valid and awaiting approval via the " +
+			v1alpha1.ApproveRefreshingAnnotation + " annotation"
+	} else if refreshingVersion != "" {
+		refreshing.Status = metav1.ConditionTrue
+		refreshing.Reason = "RefreshInProgress"
+		refreshing.Message = "Refreshing version " + refreshingVersion
+	}
+
+	r.instance.SetCondition(ready)
+	r.instance.SetCondition(refreshing)
+	r.instance.SetCondition(degraded)
+}
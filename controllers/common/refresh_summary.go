@@ -0,0 +1,104 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-errors/errors"
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
+	"sort"
+	"strings"
+)
+
+// ValidationSummary describes a validation response in a single line
+func ValidationSummary(response validation.ValidationResponse) string {
+	if response.Result == "" {
+		return "not validated yet"
+	} else if response.Message == "" {
+		return response.Result
+	}
+	return response.Result + ": " + response.Message
+}
+
+// AvroSchemaChanges lists the fields that are added, removed or have a different type in the refreshing schema
+func AvroSchemaChanges(activeSchema string, refreshingSchema string) (changes []string, err error) {
+	activeFields, err := avroSchemaFields(activeSchema)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	refreshingFields, err := avroSchemaFields(refreshingSchema)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	for path, refreshingType := range refreshingFields {
+		activeType, ok := activeFields[path]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("added field %s", path))
+		} else if activeType != refreshingType {
+			changes = append(changes, fmt.Sprintf("changed type of field %s from %s to %s", path, activeType, refreshingType))
+		}
+	}
+	for path := range activeFields {
+		if _, ok := refreshingFields[path]; !ok {
+			changes = append(changes, fmt.Sprintf("removed field %s", path))
+		}
+	}
+
+	sort.Strings(changes)
+	return
+}
+
+// avroSchemaFields maps the path of each field in an avro schema, including nested records, to its type
+func avroSchemaFields(schema string) (fields map[string]string, err error) {
+	var parsed interface{}
+	err = json.Unmarshal([]byte(schema), &parsed)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	fields = make(map[string]string)
+	collectAvroFields(parsed, "", fields)
+	return
+}
+
+func collectAvroFields(schema interface{}, path string, fields map[string]string) {
+	switch schemaType := schema.(type) {
+	case []interface{}:
+		//union
+		for _, unionType := range schemaType {
+			collectAvroFields(unionType, path, fields)
+		}
+	case map[string]interface{}:
+		recordFields, _ := schemaType["fields"].([]interface{})
+		for _, recordField := range recordFields {
+			field, ok := recordField.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := field["name"].(string)
+			fields[path+name] = avroTypeName(field["type"])
+			collectAvroFields(field["type"], path+name+".", fields)
+		}
+	}
+}
+
+func avroTypeName(avroType interface{}) string {
+	switch avroType := avroType.(type) {
+	case string:
+		return avroType
+	case []interface{}:
+		var names []string
+		for _, unionType := range avroType {
+			names = append(names, avroTypeName(unionType))
+		}
+		return strings.Join(names, "|")
+	case map[string]interface{}:
+		name := avroTypeName(avroType["type"])
+		if xjoinType, ok := avroType["xjoin.type"].(string); ok {
+			name = name + "(" + xjoinType + ")"
+		}
+		return name
+	default:
+		return fmt.Sprint(avroType)
+	}
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -18,4 +19,19 @@ type XJoinObject interface {
 	SetRefreshingVersionIsValid(valid bool)
 	GetSpecHash() string
 	GetSpec() interface{}
+	GetConditions() []metav1.Condition
+	SetCondition(condition metav1.Condition)
+	SetPhase(phase string)
+	SetObservedGeneration(generation int64)
+	SetRefreshReason(reason string)
+	GetLastAction() *v1alpha1.XJoinAction
+	SetLastAction(action v1alpha1.XJoinAction)
+	SetActiveVersionValidationSkipped(skipped bool)
+	GetPreviousVersion() string
+	GetPreviousVersionExpiration() *metav1.Time
+	SetPreviousVersion(version string, expiration *metav1.Time)
+	RefreshRequiresApproval() bool
+	GetApprovedVersion() string
+	SetApprovedVersion(version string)
+	SetRefreshSummary(summary *v1alpha1.RefreshSummary)
 }
@@ -102,6 +102,37 @@ func (as *AvroSchema) CheckDeviation() (problem, err error) {
 	return
 }
 
+// EvolveSchema registers the schema as a new version of the subject when it differs from the latest version
+func (as *AvroSchema) EvolveSchema() (err error) {
+	problem, err := as.CheckDeviation()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	if problem == nil {
+		return
+	}
+
+	err = as.Create()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+// IsCompatible checks the schema against the latest version of the subject with the registry's compatibility rules
+func (as *AvroSchema) IsCompatible() (compatible bool, err error) {
+	schema, err := as.SetSchemaNameNamespace()
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+
+	compatible, err = as.registry.IsSchemaCompatible(as.Name(), schema)
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	return
+}
+
 func (as *AvroSchema) Exists() (exists bool, err error) {
 	exists, err = as.registry.CheckIfSchemaVersionExists(as.Name(), as.id)
 	if err != nil {
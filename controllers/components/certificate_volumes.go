@@ -0,0 +1,53 @@
+package components
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// CertificatesMountPath is the directory the database certificates stored in secrets are mounted in by the
+// validation pods
+const CertificatesMountPath = "/etc/xjoin/db-ssl"
+
+// certificateFileMode allows libpq to read a key owned by root
+var certificateFileMode = int32(0440)
+
+// CertificateVolumes are the volumes that mount the database certificates stored in secrets into a validation pod
+type CertificateVolumes struct {
+	Volumes      []v1.Volume
+	VolumeMounts []v1.VolumeMount
+}
+
+// EnvVar is an environment variable with the path of a certificate. A certificate stored in a secret is mounted as
+// a file by a new volume.
+func (c *CertificateVolumes) EnvVar(name string, certificate *v1alpha1.StringOrSecretParameter) v1.EnvVar {
+	secretKey := certificate.SecretKeySelector()
+	if secretKey == nil {
+		return v1.EnvVar{Name: name, Value: certificate.Value}
+	}
+
+	volumeName := fmt.Sprintf("db-ssl-%d", len(c.Volumes))
+	c.Volumes = append(c.Volumes, v1.Volume{
+		Name: volumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName:  secretKey.Name,
+				Items:       []v1.KeyToPath{{Key: secretKey.Key, Path: secretKey.Key}},
+				DefaultMode: &certificateFileMode,
+				Optional:    secretKey.Optional,
+			},
+		},
+	})
+	//each volume has its own directory, so the same key of different secrets doesn't collide
+	mountPath := path.Join(CertificatesMountPath, volumeName)
+	c.VolumeMounts = append(c.VolumeMounts, v1.VolumeMount{
+		Name:      volumeName,
+		MountPath: mountPath,
+		ReadOnly:  true,
+	})
+
+	return v1.EnvVar{Name: name, Value: path.Join(mountPath, secretKey.Key)}
+}
@@ -3,12 +3,17 @@ package components
 import (
 	"github.com/go-errors/errors"
 	"github.com/redhatinsights/xjoin-operator/controllers/kafka"
+	"hash/fnv"
 	"strings"
 )
 
+// SchemaHistoryTopicSuffix is appended to the connector's name for its schema history topic
+const SchemaHistoryTopicSuffix = ".schema-history"
+
 type DebeziumConnector struct {
 	name               string
 	version            string
+	Class              string
 	Template           string
 	KafkaClient        kafka.GenericKafka
 	TemplateParameters map[string]interface{}
@@ -26,13 +31,37 @@ func (dc *DebeziumConnector) Name() string {
 	return dc.name + "." + dc.version
 }
 
-func (dc *DebeziumConnector) Create() (err error) {
+// ReplicationSlotName is the name of the connector's PostgreSQL replication slot and publication
+func (dc *DebeziumConnector) ReplicationSlotName() string {
+	return replicationSlotName(dc.Name())
+}
+
+// SchemaHistoryTopicName is the name of the topic in which the MySQL and SQL Server connectors record the schema history
+func (dc *DebeziumConnector) SchemaHistoryTopicName() string {
+	return dc.Name() + SchemaHistoryTopicSuffix
+}
+
+// databaseServerId is the id the MySQL connector joins the replication with, it has to be unique in the cluster
+func (dc *DebeziumConnector) databaseServerId() uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(dc.Name()))
+	return h.Sum32()%(1<<31-1) + 1
+}
+
+func (dc *DebeziumConnector) templateParameters() map[string]interface{} {
 	m := dc.TemplateParameters
 	m["DatabaseServerName"] = dc.Name()
-	m["ReplicationSlotName"] = strings.ReplaceAll(dc.Name(), ".", "_")
+	m["DatabaseServerId"] = dc.databaseServerId()
+	m["ReplicationSlotName"] = dc.ReplicationSlotName()
+	m["PublicationName"] = dc.ReplicationSlotName()
+	m["SchemaHistoryTopicName"] = dc.SchemaHistoryTopicName()
 	m["TopicName"] = dc.Name()
+	return m
+}
 
-	err = dc.KafkaClient.CreateGenericDebeziumConnector(dc.Name(), dc.Template, m)
+func (dc *DebeziumConnector) Create() (err error) {
+	err = dc.KafkaClient.CreateGenericDebeziumConnector(
+		dc.Name(), dc.Class, dc.Template, dc.templateParameters())
 	if err != nil {
 		return errors.Wrap(err, 0)
 	}
@@ -48,6 +77,11 @@ func (dc *DebeziumConnector) Delete() (err error) {
 }
 
 func (dc *DebeziumConnector) CheckDeviation() (problem, err error) {
+	problem, err = dc.KafkaClient.CheckGenericDebeziumConnectorDeviation(
+		dc.Name(), dc.Class, dc.Template, dc.templateParameters())
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
 	return
 }
 
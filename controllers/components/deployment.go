@@ -0,0 +1,320 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SecretChecksumAnnotation is set on the pod template of deployments that reference secrets.
+// Updating it when a secret is rotated rolls out new pods which read the new secret values.
+const SecretChecksumAnnotation = "xjoin.cloud.redhat.com/secret-checksum"
+
+// WorkloadChecksumAnnotation is set on the pod template of deployments to the checksum of their resolved WorkloadSpec.
+// A changed workload is rolled out to the existing deployment instead of being reported as a deviation.
+const WorkloadChecksumAnnotation = "xjoin.cloud.redhat.com/workload-checksum"
+
+// secretEnvVar builds an unstructured env var that reads its value from a key of a secret.
+// The key is optional so credentials can be omitted when Elasticsearch does not require authentication.
+func secretEnvVar(name string, secret string, key string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": name,
+		"valueFrom": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{
+				"name":     secret,
+				"key":      key,
+				"optional": true,
+			},
+		},
+	}
+}
+
+// rolloutDeployment updates the secret checksum of an existing Deployment when it differs from the expected Deployment
+func rolloutDeployment(ctx context.Context, k8sClient client.Client, expectedDeployment *unstructured.Unstructured) error {
+	expectedChecksum, _, err := unstructured.NestedString(
+		expectedDeployment.Object, "spec", "template", "metadata", "annotations", SecretChecksumAnnotation)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	var actual appsv1.Deployment
+	err = k8sClient.Get(
+		ctx, client.ObjectKey{Name: expectedDeployment.GetName(), Namespace: expectedDeployment.GetNamespace()}, &actual)
+	if k8errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	if actual.Spec.Template.Annotations[SecretChecksumAnnotation] == expectedChecksum {
+		return nil
+	}
+
+	if actual.Spec.Template.Annotations == nil {
+		actual.Spec.Template.Annotations = make(map[string]string)
+	}
+	actual.Spec.Template.Annotations[SecretChecksumAnnotation] = expectedChecksum
+	err = k8sClient.Update(ctx, &actual)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// evolveDeployment updates the env of an existing Deployment's containers to the env of the expected Deployment
+func evolveDeployment(ctx context.Context, k8sClient client.Client, expectedDeployment *unstructured.Unstructured) error {
+	expectedJson, err := json.Marshal(expectedDeployment.Object)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	var expected appsv1.Deployment
+	err = json.Unmarshal(expectedJson, &expected)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	var actual appsv1.Deployment
+	err = k8sClient.Get(ctx, client.ObjectKey{Name: expected.Name, Namespace: expected.Namespace}, &actual)
+	if k8errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	updated := false
+	for i, expectedContainer := range expected.Spec.Template.Spec.Containers {
+		if i >= len(actual.Spec.Template.Spec.Containers) {
+			break
+		}
+		if !equality.Semantic.DeepEqual(expectedContainer.Env, actual.Spec.Template.Spec.Containers[i].Env) {
+			actual.Spec.Template.Spec.Containers[i].Env = expectedContainer.Env
+			updated = true
+		}
+	}
+
+	if !updated {
+		return nil
+	}
+
+	err = k8sClient.Update(ctx, &actual)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// rolloutDeploymentEnvVar updates an env var of an existing Deployment's containers to its value in the expected Deployment
+func rolloutDeploymentEnvVar(
+	ctx context.Context, k8sClient client.Client, expectedDeployment *unstructured.Unstructured, name string) error {
+
+	expectedJson, err := json.Marshal(expectedDeployment.Object)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	var expected appsv1.Deployment
+	err = json.Unmarshal(expectedJson, &expected)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	var actual appsv1.Deployment
+	err = k8sClient.Get(ctx, client.ObjectKey{Name: expected.Name, Namespace: expected.Namespace}, &actual)
+	if k8errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	updated := false
+	for i, expectedContainer := range expected.Spec.Template.Spec.Containers {
+		if i >= len(actual.Spec.Template.Spec.Containers) {
+			break
+		}
+		for _, expectedEnvVar := range expectedContainer.Env {
+			if expectedEnvVar.Name != name {
+				continue
+			}
+			actualEnv := actual.Spec.Template.Spec.Containers[i].Env
+			for j := range actualEnv {
+				if actualEnv[j].Name == name && !equality.Semantic.DeepEqual(actualEnv[j], expectedEnvVar) {
+					actualEnv[j] = expectedEnvVar
+					updated = true
+				}
+			}
+		}
+	}
+
+	if !updated {
+		return nil
+	}
+
+	err = k8sClient.Update(ctx, &actual)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// rolloutDeploymentWorkload applies the replicas, scheduling and the containers' image, resources and workload env
+// of the expected Deployment to an existing Deployment when its workload checksum differs
+func rolloutDeploymentWorkload(ctx context.Context, k8sClient client.Client,
+	expectedDeployment *unstructured.Unstructured, workload v1alpha1.WorkloadSpec) error {
+
+	expectedJson, err := json.Marshal(expectedDeployment.Object)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	var expected appsv1.Deployment
+	err = json.Unmarshal(expectedJson, &expected)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	var actual appsv1.Deployment
+	err = k8sClient.Get(ctx, client.ObjectKey{Name: expected.Name, Namespace: expected.Namespace}, &actual)
+	if k8errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	expectedChecksum := expected.Spec.Template.Annotations[WorkloadChecksumAnnotation]
+	if actual.Spec.Template.Annotations[WorkloadChecksumAnnotation] == expectedChecksum {
+		return nil
+	}
+
+	if actual.Spec.Template.Annotations == nil {
+		actual.Spec.Template.Annotations = make(map[string]string)
+	}
+	actual.Spec.Template.Annotations[WorkloadChecksumAnnotation] = expectedChecksum
+	if expected.Spec.Replicas != nil {
+		actual.Spec.Replicas = expected.Spec.Replicas
+	}
+	actual.Spec.Template.Spec.NodeSelector = expected.Spec.Template.Spec.NodeSelector
+	actual.Spec.Template.Spec.Tolerations = expected.Spec.Template.Spec.Tolerations
+
+	for i, expectedContainer := range expected.Spec.Template.Spec.Containers {
+		if i >= len(actual.Spec.Template.Spec.Containers) {
+			break
+		}
+		actualContainer := &actual.Spec.Template.Spec.Containers[i]
+		actualContainer.Image = expectedContainer.Image
+		actualContainer.ImagePullPolicy = expectedContainer.ImagePullPolicy
+		actualContainer.Resources = expectedContainer.Resources
+
+		//env vars removed from the workload are dropped, the env vars set by the operator are kept as they are
+		var env []corev1.EnvVar
+		for _, actualEnvVar := range actualContainer.Env {
+			if containsEnvVar(expectedContainer.Env, actualEnvVar.Name) {
+				env = append(env, actualEnvVar)
+			}
+		}
+		actualContainer.Env = env
+
+		//only the env vars of the workload, the others are set by the operator
+		for _, workloadEnvVar := range workload.Env {
+			for _, expectedEnvVar := range expectedContainer.Env {
+				if expectedEnvVar.Name != workloadEnvVar.Name {
+					continue
+				}
+				found := false
+				for j := range actualContainer.Env {
+					if actualContainer.Env[j].Name == expectedEnvVar.Name {
+						actualContainer.Env[j] = expectedEnvVar
+						found = true
+					}
+				}
+				if !found {
+					actualContainer.Env = append(actualContainer.Env, expectedEnvVar)
+				}
+			}
+		}
+	}
+
+	err = k8sClient.Update(ctx, &actual)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// checkDeploymentDeviation compares the containers of an existing Deployment with the Deployment that would be created now
+func checkDeploymentDeviation(
+	ctx context.Context, k8sClient client.Client, expectedDeployment *unstructured.Unstructured) (problem, err error) {
+
+	expectedJson, err := json.Marshal(expectedDeployment.Object)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	var expected appsv1.Deployment
+	err = json.Unmarshal(expectedJson, &expected)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	var actual appsv1.Deployment
+	err = k8sClient.Get(ctx, client.ObjectKey{Name: expected.Name, Namespace: expected.Namespace}, &actual)
+	if err != nil {
+		if k8errors.IsNotFound(err) {
+			return fmt.Errorf("deployment %s not found in %s", expected.Name, expected.Namespace), nil
+		}
+		return nil, errors.Wrap(err, 0)
+	}
+
+	expectedContainers := expected.Spec.Template.Spec.Containers
+	actualContainers := actual.Spec.Template.Spec.Containers
+	if len(expectedContainers) != len(actualContainers) {
+		return fmt.Errorf(
+			"deployment %s container count changed from %d to %d",
+			expected.Name, len(expectedContainers), len(actualContainers)), nil
+	}
+
+	for i, expectedContainer := range expectedContainers {
+		actualContainer := actualContainers[i]
+		if expectedContainer.Image != actualContainer.Image {
+			return fmt.Errorf(
+				"deployment %s container %s image changed from %s to %s",
+				expected.Name, expectedContainer.Name, expectedContainer.Image, actualContainer.Image), nil
+		}
+
+		//only the env vars set by the operator are compared, an env var left over from a workload is not a deviation.
+		//values are not included in the message because the env can contain credentials
+		for _, expectedEnvVar := range expectedContainer.Env {
+			actualEnvVar := findEnvVar(actualContainer.Env, expectedEnvVar.Name)
+			if actualEnvVar == nil || !equality.Semantic.DeepEqual(expectedEnvVar, *actualEnvVar) {
+				return fmt.Errorf("deployment %s container %s env %s has changed",
+					expected.Name, expectedContainer.Name, expectedEnvVar.Name), nil
+			}
+		}
+
+		if !equality.Semantic.DeepEqual(expectedContainer.Resources, actualContainer.Resources) {
+			return fmt.Errorf(
+				"deployment %s container %s resources changed from %s to %s",
+				expected.Name, expectedContainer.Name,
+				expectedContainer.Resources.String(), actualContainer.Resources.String()), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func findEnvVar(env []corev1.EnvVar, name string) *corev1.EnvVar {
+	for i := range env {
+		if env[i].Name == name {
+			return &env[i]
+		}
+	}
+	return nil
+}
+
+func containsEnvVar(env []corev1.EnvVar, name string) bool {
+	return findEnvVar(env, name) != nil
+}
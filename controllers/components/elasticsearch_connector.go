@@ -27,12 +27,15 @@ func (es ElasticsearchConnector) Name() string {
 	return es.name + "." + es.version
 }
 
-func (es ElasticsearchConnector) Create() (err error) {
+func (es ElasticsearchConnector) templateParameters() map[string]interface{} {
 	m := es.TemplateParameters
 	m["Topic"] = es.Topic
 	//m["RenameTopicReplacement"] = fmt.Sprintf("%s.%s", kafka.Parameters.ResourceNamePrefix.String(), pipelineVersion)
+	return m
+}
 
-	err = es.KafkaClient.CreateGenericElasticsearchConnector(es.Name(), es.Template, m)
+func (es ElasticsearchConnector) Create() (err error) {
+	err = es.KafkaClient.CreateGenericElasticsearchConnector(es.Name(), es.Template, es.templateParameters())
 	if err != nil {
 		return errors.Wrap(err, 0)
 	}
@@ -48,6 +51,10 @@ func (es ElasticsearchConnector) Delete() (err error) {
 }
 
 func (es *ElasticsearchConnector) CheckDeviation() (problem, err error) {
+	problem, err = es.KafkaClient.CheckGenericElasticsearchConnectorDeviation(es.Name(), es.Template, es.templateParameters())
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
 	return
 }
 
@@ -1,8 +1,11 @@
 package components
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/go-errors/errors"
 	"github.com/redhatinsights/xjoin-operator/controllers/elasticsearch"
+	"sort"
 	"strings"
 )
 
@@ -44,6 +47,31 @@ func (es *ElasticsearchIndex) Delete() (err error) {
 }
 
 func (es *ElasticsearchIndex) CheckDeviation() (problem, err error) {
+	exists, err := es.Exists()
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	if !exists {
+		return fmt.Errorf("elasticsearch index %s not found", es.Name()), nil
+	}
+
+	var expectedProperties map[string]interface{}
+	err = json.Unmarshal([]byte(es.Properties), &expectedProperties)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	actualProperties, err := es.GenericElasticsearch.GetIndexMappingProperties(es.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	differences := diffMappingProperties(expectedProperties, actualProperties, "")
+	if len(differences) > 0 {
+		return fmt.Errorf(
+			"elasticsearch index %s mapping has changed: %s", es.Name(), strings.Join(differences, ", ")), nil
+	}
+
 	return
 }
 
@@ -62,3 +90,72 @@ func (es *ElasticsearchIndex) ListInstalledVersions() (versions []string, err er
 	}
 	return
 }
+
+// EvolveSchema adds the properties of fields which are new in the Avro schema to the existing index's mapping
+func (es *ElasticsearchIndex) EvolveSchema() (err error) {
+	exists, err := es.Exists()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	if !exists {
+		return
+	}
+
+	err = es.GenericElasticsearch.PutIndexMappingProperties(es.Name(), es.Properties)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+// BreakingMappingChanges lists the properties of the previous mapping that are removed or changed by the next mapping.
+// The next mapping can be applied to an existing index when the list is empty.
+func BreakingMappingChanges(previousProperties string, nextProperties string) (changes []string, err error) {
+	var previous, next map[string]interface{}
+	err = json.Unmarshal([]byte(previousProperties), &previous)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	err = json.Unmarshal([]byte(nextProperties), &next)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	return diffMappingProperties(previous, next, ""), nil
+}
+
+// diffMappingProperties lists each expected mapping property that is missing or different in the actual mapping.
+// Properties added to the index by dynamic mapping are ignored.
+func diffMappingProperties(expected map[string]interface{}, actual map[string]interface{}, path string) (differences []string) {
+	for fieldName, expectedField := range expected {
+		fieldPath := path + fieldName
+		actualField, ok := actual[fieldName].(map[string]interface{})
+		if !ok {
+			differences = append(differences, fieldPath+" is missing")
+			continue
+		}
+
+		expectedFieldMap, _ := expectedField.(map[string]interface{})
+		for key, expectedValue := range expectedFieldMap {
+			if key == "properties" {
+				expectedNested, _ := expectedValue.(map[string]interface{})
+				actualNested, _ := actualField["properties"].(map[string]interface{})
+				differences = append(differences, diffMappingProperties(expectedNested, actualNested, fieldPath+".")...)
+				continue
+			}
+
+			//Elasticsearch omits the type of object fields when returning the mapping
+			if key == "type" && expectedValue == "object" && actualField["type"] == nil {
+				continue
+			}
+
+			if fmt.Sprint(expectedValue) != fmt.Sprint(actualField[key]) {
+				differences = append(differences, fmt.Sprintf(
+					"%s %s changed from %v to %v", fieldPath, key, expectedValue, actualField[key]))
+			}
+		}
+	}
+
+	sort.Strings(differences)
+	return
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/go-errors/errors"
 	"github.com/redhatinsights/xjoin-operator/controllers/elasticsearch"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
 	"strings"
 )
 
@@ -48,9 +49,53 @@ func (es ElasticsearchPipeline) Delete() (err error) {
 }
 
 func (es *ElasticsearchPipeline) CheckDeviation() (problem, err error) {
+	actualPipeline, err := es.GenericElasticsearch.GetPipeline(es.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	if actualPipeline == nil {
+		return fmt.Errorf("elasticsearch pipeline %s not found", es.Name()), nil
+	}
+
+	expectedPipelineString, err := es.jsonFieldsToESPipeline()
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	var expectedPipeline map[string]interface{}
+	err = json.Unmarshal([]byte(expectedPipelineString), &expectedPipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	changedFields := k8sUtils.ChangedKeys(
+		processorsByField(actualPipeline["processors"]), processorsByField(expectedPipeline["processors"]))
+	if len(changedFields) > 0 {
+		return fmt.Errorf("elasticsearch pipeline %s processors have changed for fields: %s",
+			es.Name(), strings.Join(changedFields, ", ")), nil
+	}
+
 	return
 }
 
+// processorsByField keys the json processors by their field, other processors are keyed by their type and position
+func processorsByField(processors interface{}) map[string]interface{} {
+	processorList, _ := processors.([]interface{})
+	byField := make(map[string]interface{})
+	for index, processor := range processorList {
+		processorMap, _ := processor.(map[string]interface{})
+		if jsonProcessor, ok := processorMap["json"].(map[string]interface{}); ok {
+			if field, ok := jsonProcessor["field"].(string); ok {
+				byField[field] = processor
+				continue
+			}
+		}
+		for processorType := range processorMap {
+			byField[fmt.Sprintf("%s processor %d", processorType, index)] = processor
+		}
+	}
+	return byField
+}
+
 func (es ElasticsearchPipeline) Exists() (exists bool, err error) {
 	exists, err = es.GenericElasticsearch.PipelineExists(es.Name())
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 type KafkaTopic struct {
 	name            string
 	version         string
+	Suffix          string //distinguishes a second topic of the same component, e.g. a Debezium schema history topic
 	KafkaTopics     kafka.StrimziTopics
 	TopicParameters kafka.TopicParameters
 }
@@ -22,7 +23,7 @@ func (kt *KafkaTopic) SetVersion(version string) {
 }
 
 func (kt *KafkaTopic) Name() string {
-	return kt.name + "." + kt.version
+	return kt.name + "." + kt.version + kt.Suffix
 }
 
 func (kt *KafkaTopic) Create() (err error) {
@@ -42,7 +43,11 @@ func (kt *KafkaTopic) Delete() (err error) {
 }
 
 func (kt *KafkaTopic) CheckDeviation() (problem, err error) {
-	return //TODO
+	problem, err = kt.KafkaTopics.CheckGenericTopicDeviation(kt.Name(), kt.TopicParameters)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	return
 }
 
 func (kt *KafkaTopic) Exists() (exists bool, err error) {
@@ -60,7 +65,14 @@ func (kt *KafkaTopic) ListInstalledVersions() (versions []string, err error) {
 	}
 
 	for _, name := range topicNames {
-		versions = append(versions, strings.Split(name, kt.name+".")[1])
+		version := strings.Split(name, kt.name+".")[1]
+		if kt.Suffix != "" {
+			if !strings.HasSuffix(version, kt.Suffix) {
+				continue
+			}
+			version = strings.TrimSuffix(version, kt.Suffix)
+		}
+		versions = append(versions, version)
 	}
 	return
 }
@@ -13,6 +13,17 @@ type Component interface {
 	ListInstalledVersions() ([]string, error)
 }
 
+// RolloutComponent is implemented by components whose pods are restarted in place when a referenced secret or their
+// workload changes
+type RolloutComponent interface {
+	Rollout() error
+}
+
+// SchemaEvolutionComponent is implemented by components that apply an additive change of the Avro schema in place
+type SchemaEvolutionComponent interface {
+	EvolveSchema() error
+}
+
 type ComponentManager struct {
 	components []Component
 	name       string
@@ -66,6 +77,38 @@ func (c *ComponentManager) DeleteAll() error {
 	return nil
 }
 
+// RolloutAll rolls out the components that reference a secret or use a workload which changed since they were created
+func (c *ComponentManager) RolloutAll() error {
+	for _, component := range c.components {
+		rolloutComponent, ok := component.(RolloutComponent)
+		if !ok {
+			continue
+		}
+
+		err := rolloutComponent.Rollout()
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
+
+// EvolveSchemaAll updates the existing components to an Avro schema which only adds fields to the previous schema
+func (c *ComponentManager) EvolveSchemaAll() error {
+	for _, component := range c.components {
+		schemaEvolutionComponent, ok := component.(SchemaEvolutionComponent)
+		if !ok {
+			continue
+		}
+
+		err := schemaEvolutionComponent.EvolveSchema()
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
+
 // CheckForDeviations checks each component's stored value against the expected value, returns true if deviation is found
 func (c *ComponentManager) CheckForDeviations() (problems []error, err error) {
 	for _, component := range c.components {
@@ -0,0 +1,135 @@
+package components
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	"github.com/redhatinsights/xjoin-operator/controllers/database"
+	"strings"
+)
+
+// ReplicationSlot is the PostgreSQL replication slot and publication created by a DebeziumConnector.
+// Debezium creates both when the connector starts, so Create is a no-op.
+type ReplicationSlot struct {
+	name     string
+	version  string
+	DBParams database.DBParams
+}
+
+func replicationSlotName(connectorName string) string {
+	return strings.ReplaceAll(connectorName, ".", "_")
+}
+
+func (rs *ReplicationSlot) SetName(name string) {
+	rs.name = strings.ToLower(name)
+}
+
+func (rs *ReplicationSlot) SetVersion(version string) {
+	rs.version = version
+}
+
+func (rs *ReplicationSlot) Name() string {
+	return replicationSlotName(rs.name + "." + rs.version)
+}
+
+func (rs *ReplicationSlot) prefix() string {
+	return replicationSlotName(rs.name) + "_"
+}
+
+func (rs *ReplicationSlot) withDatabase(query func(db *database.Database) error) (err error) {
+	db := database.NewDatabase(rs.DBParams)
+	err = db.Connect()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	defer func() {
+		closeErr := db.Close()
+		if err == nil && closeErr != nil {
+			err = errors.Wrap(closeErr, 0)
+		}
+	}()
+
+	return query(db)
+}
+
+func (rs *ReplicationSlot) Create() (err error) {
+	return
+}
+
+// Delete disconnects the slot's consumer and drops the slot and the publication
+func (rs *ReplicationSlot) Delete() (err error) {
+	err = rs.withDatabase(func(db *database.Database) (err error) {
+		err = db.TerminateAndRemoveReplicationSlot(rs.Name())
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		err = db.RemovePublication(rs.Name())
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		return
+	})
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+func (rs *ReplicationSlot) CheckDeviation() (problem, err error) {
+	return
+}
+
+func (rs *ReplicationSlot) Exists() (exists bool, err error) {
+	err = rs.withDatabase(func(db *database.Database) (err error) {
+		lag, err := db.GetReplicationSlotLag(rs.Name())
+		exists = lag != nil
+		return
+	})
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	return
+}
+
+// ListInstalledVersions returns the versions of the replication slots and publications named after the component.
+// Only numeric versions are returned, so the slots of a datasource whose name shares the prefix are not included.
+func (rs *ReplicationSlot) ListInstalledVersions() (versions []string, err error) {
+	var names []string
+	err = rs.withDatabase(func(db *database.Database) (err error) {
+		slots, err := db.ListReplicationSlots(rs.prefix())
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		publications, err := db.ListPublications(rs.prefix())
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		names = append(slots, publications...)
+		return
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	for _, name := range names {
+		version := strings.TrimPrefix(name, rs.prefix())
+		if isNumeric(version) && !utils.ContainsString(versions, version) {
+			versions = append(versions, version)
+		}
+	}
+	return
+}
+
+func isNumeric(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
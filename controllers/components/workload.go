@@ -0,0 +1,130 @@
+package components
+
+import (
+	"encoding/json"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func defaultDeploymentWorkload(image string) v1alpha1.WorkloadSpec {
+	replicas := int32(1)
+	return v1alpha1.WorkloadSpec{
+		Image:           image,
+		Tag:             "latest",
+		ImagePullPolicy: v1.PullAlways,
+		Replicas:        &replicas,
+		Resources: &v1.ResourceRequirements{
+			Limits: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("250m"),
+				v1.ResourceMemory: resource.MustParse("512Mi"),
+			},
+			Requests: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("100m"),
+				v1.ResourceMemory: resource.MustParse("64Mi"),
+			},
+		},
+	}
+}
+
+func DefaultCoreWorkload() v1alpha1.WorkloadSpec {
+	return defaultDeploymentWorkload("quay.io/cloudservices/xjoin-core")
+}
+
+func DefaultSubgraphWorkload() v1alpha1.WorkloadSpec {
+	return defaultDeploymentWorkload("quay.io/cloudservices/xjoin-api-subgraph")
+}
+
+func DefaultValidatorWorkload() v1alpha1.WorkloadSpec {
+	return v1alpha1.WorkloadSpec{
+		Image:           "quay.io/cloudservices/xjoin-validation",
+		Tag:             "latest",
+		ImagePullPolicy: v1.PullAlways,
+	}
+}
+
+// ResolveWorkload applies the JSON WorkloadSpec from the xjoin-generic ConfigMap and then the spec's WorkloadSpec
+// on top of the built-in defaults
+func ResolveWorkload(
+	defaults v1alpha1.WorkloadSpec, configMapWorkload string, specWorkload *v1alpha1.WorkloadSpec) (
+	workload v1alpha1.WorkloadSpec, err error) {
+
+	var configMapWorkloadSpec v1alpha1.WorkloadSpec
+	if configMapWorkload != "" {
+		err = json.Unmarshal([]byte(configMapWorkload), &configMapWorkloadSpec)
+		if err != nil {
+			return workload, errors.Wrap(errors.New("invalid workload in xjoin-generic ConfigMap: "+err.Error()), 0)
+		}
+	}
+
+	return defaults.Merge(&configMapWorkloadSpec).Merge(specWorkload), nil
+}
+
+// applyWorkload sets the replicas, scheduling and the container's image, resources and extra env of an unstructured
+// deployment spec, and the workload's checksum on its pod template
+func applyWorkload(
+	workload v1alpha1.WorkloadSpec, deploymentSpec map[string]interface{}, podSpec map[string]interface{},
+	container map[string]interface{}) (err error) {
+
+	checksum, err := k8sUtils.SpecHash(workload)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	err = unstructured.SetNestedField(
+		deploymentSpec, checksum, "template", "metadata", "annotations", WorkloadChecksumAnnotation)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	container["image"] = workload.ImageName()
+	container["imagePullPolicy"] = string(workload.ImagePullPolicy)
+
+	if workload.Replicas != nil {
+		deploymentSpec["replicas"] = int64(*workload.Replicas)
+	}
+
+	if workload.Resources != nil {
+		container["resources"], err = runtime.DefaultUnstructuredConverter.ToUnstructured(workload.Resources)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+
+	if len(workload.NodeSelector) > 0 {
+		nodeSelector := make(map[string]interface{})
+		for key, value := range workload.NodeSelector {
+			nodeSelector[key] = value
+		}
+		podSpec["nodeSelector"] = nodeSelector
+	}
+
+	env, _ := container["env"].([]map[string]interface{})
+	for _, envVar := range workload.Env {
+		envVar := envVar
+		envVarMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&envVar)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		env = append(env, envVarMap)
+	}
+	container["env"] = env
+
+	var tolerations []interface{}
+	for _, toleration := range workload.Tolerations {
+		toleration := toleration
+		tolerationMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&toleration)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		tolerations = append(tolerations, tolerationMap)
+	}
+	if len(tolerations) > 0 {
+		podSpec["tolerations"] = tolerations
+	}
+
+	return
+}
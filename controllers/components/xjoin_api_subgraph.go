@@ -3,6 +3,7 @@ package components
 import (
 	"context"
 	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -19,11 +20,11 @@ type XJoinAPISubGraph struct {
 	Namespace             string
 	AvroSchema            string
 	Registry              *schemaregistry.ConfluentClient
-	ElasticSearchUsername string
-	ElasticSearchPassword string
+	ElasticSearchSecret   string //name of the secret with the Elasticsearch username and password
+	ElasticSearchChecksum string //hash of the secret's data, a new value rolls out the deployment
 	ElasticSearchURL      string
 	ElasticSearchIndex    string
-	Image                 string
+	Workload              v1alpha1.WorkloadSpec
 	Suffix                string
 	GraphQLSchemaName     string
 }
@@ -45,103 +46,107 @@ func (x XJoinAPISubGraph) Name() string {
 	return x.name + "-" + x.version
 }
 
-func (x XJoinAPISubGraph) Create() (err error) {
-	deployment := &unstructured.Unstructured{}
-
-	labels := map[string]interface{}{
+func (x XJoinAPISubGraph) labels() map[string]interface{} {
+	return map[string]interface{}{
 		"app":         x.Name(),
 		"xjoin.index": x.name,
 	}
+}
 
-	deployment.Object = map[string]interface{}{
-		"metadata": map[string]interface{}{
-			"name":      x.Name(),
-			"namespace": x.Namespace,
-			"labels":    labels,
+func (x XJoinAPISubGraph) deployment() (*unstructured.Unstructured, error) {
+	deployment := &unstructured.Unstructured{}
+	labels := x.labels()
+
+	container := map[string]interface{}{
+		"ports": []map[string]interface{}{
+			{
+				"containerPort": 8000,
+				"name":          "web",
+				"protocol":      "TCP",
+			},
 		},
-		"spec": map[string]interface{}{
-			"replicas": 1,
-			"selector": map[string]interface{}{
-				"matchLabels": labels,
+		"env": []map[string]interface{}{
+			{
+				"name":  "AVRO_SCHEMA",
+				"value": x.AvroSchema,
 			},
-			"strategy": map[string]interface{}{
-				"rollingUpdate": map[string]interface{}{
-					"maxSurge":       "25%",
-					"maxUnavailable": "25%",
-				},
-				"type": "RollingUpdate",
+			{
+				"name":  "SCHEMA_REGISTRY_PROTOCOL",
+				"value": x.Registry.ConnectionParams.Protocol,
 			},
-			"template": map[string]interface{}{
-				"metadata": map[string]interface{}{
-					"labels": labels,
-				},
-				"spec": map[string]interface{}{
-					"containers": []map[string]interface{}{{
-						"ports": []map[string]interface{}{
-							{
-								"containerPort": 8000,
-								"name":          "web",
-								"protocol":      "TCP",
-							},
-						},
-						"env": []map[string]interface{}{
-							{
-								"name":  "AVRO_SCHEMA",
-								"value": x.AvroSchema,
-							},
-							{
-								"name":  "SCHEMA_REGISTRY_PROTOCOL",
-								"value": x.Registry.ConnectionParams.Protocol,
-							},
-							{
-								"name":  "SCHEMA_REGISTRY_HOSTNAME",
-								"value": x.Registry.ConnectionParams.Hostname,
-							},
-							{
-								"name":  "SCHEMA_REGISTRY_PORT",
-								"value": x.Registry.ConnectionParams.Port,
-							},
-							{
-								"name":  "ELASTIC_SEARCH_URL",
-								"value": x.ElasticSearchURL,
-							},
-							{
-								"name":  "ELASTIC_SEARCH_USERNAME",
-								"value": x.ElasticSearchUsername,
-							},
-							{
-								"name":  "ELASTIC_SEARCH_PASSWORD",
-								"value": x.ElasticSearchPassword,
-							},
-							{
-								"name":  "ELASTIC_SEARCH_INDEX",
-								"value": x.ElasticSearchIndex,
-							},
-							{
-								"name":  "GRAPHQL_SCHEMA_NAME",
-								"value": x.GraphQLSchemaName,
-							},
-						},
-						"image":           x.Image,
-						"imagePullPolicy": "Always",
-						"name":            x.Name(),
-						"resources": map[string]interface{}{
-							"limits": map[string]interface{}{
-								"cpu":    "250m",
-								"memory": "512Mi",
-							},
-							"requests": map[string]interface{}{
-								"cpu":    "100m",
-								"memory": "64Mi",
-							},
-						},
-					}},
+			{
+				"name":  "SCHEMA_REGISTRY_HOSTNAME",
+				"value": x.Registry.ConnectionParams.Hostname,
+			},
+			{
+				"name":  "SCHEMA_REGISTRY_PORT",
+				"value": x.Registry.ConnectionParams.Port,
+			},
+			{
+				"name":  "ELASTIC_SEARCH_URL",
+				"value": x.ElasticSearchURL,
+			},
+			secretEnvVar("ELASTIC_SEARCH_USERNAME", x.ElasticSearchSecret, "username"),
+			secretEnvVar("ELASTIC_SEARCH_PASSWORD", x.ElasticSearchSecret, "password"),
+			{
+				"name":  "ELASTIC_SEARCH_INDEX",
+				"value": x.ElasticSearchIndex,
+			},
+			{
+				"name":  "GRAPHQL_SCHEMA_NAME",
+				"value": x.GraphQLSchemaName,
+			},
+		},
+		"name": x.Name(),
+	}
+	podSpec := map[string]interface{}{
+		"containers": []map[string]interface{}{container},
+	}
+	deploymentSpec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": labels,
+		},
+		"strategy": map[string]interface{}{
+			"rollingUpdate": map[string]interface{}{
+				"maxSurge":       "25%",
+				"maxUnavailable": "25%",
+			},
+			"type": "RollingUpdate",
+		},
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": labels,
+				"annotations": map[string]interface{}{
+					SecretChecksumAnnotation: x.ElasticSearchChecksum,
 				},
 			},
+			"spec": podSpec,
+		},
+	}
+
+	err := applyWorkload(x.Workload, deploymentSpec, podSpec, container)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	deployment.Object = map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      x.Name(),
+			"namespace": x.Namespace,
+			"labels":    labels,
 		},
+		"spec": deploymentSpec,
 	}
 
 	deployment.SetGroupVersionKind(common.DeploymentGVK)
+	return deployment, nil
+}
+
+func (x XJoinAPISubGraph) Create() (err error) {
+	deployment, err := x.deployment()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
 
 	err = x.Client.Create(x.Context, deployment)
 	if err != nil {
@@ -150,6 +155,7 @@ func (x XJoinAPISubGraph) Create() (err error) {
 
 	//create the service
 	service := &unstructured.Unstructured{}
+	labels := x.labels()
 
 	service.Object = map[string]interface{}{
 		"metadata": map[string]interface{}{
@@ -220,7 +226,54 @@ func (x XJoinAPISubGraph) Delete() (err error) {
 	return
 }
 
+func (x *XJoinAPISubGraph) Rollout() (err error) {
+	deployment, err := x.deployment()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	err = rolloutDeployment(x.Context, x.Client, deployment)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	//switches to the XJoinIndex's alias when this version becomes active
+	err = rolloutDeploymentEnvVar(x.Context, x.Client, deployment, "ELASTIC_SEARCH_INDEX")
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	err = rolloutDeploymentWorkload(x.Context, x.Client, deployment, x.Workload)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+// EvolveSchema rolls out the deployment with the updated Avro schema
+func (x *XJoinAPISubGraph) EvolveSchema() (err error) {
+	deployment, err := x.deployment()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	err = evolveDeployment(x.Context, x.Client, deployment)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
 func (x *XJoinAPISubGraph) CheckDeviation() (problem, err error) {
+	deployment, err := x.deployment()
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	problem, err = checkDeploymentDeviation(x.Context, x.Client, deployment)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
 	return
 }
 
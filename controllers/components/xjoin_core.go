@@ -3,6 +3,7 @@ package components
 import (
 	"context"
 	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,6 +21,7 @@ type XJoinCore struct {
 	SchemaRegistryURL string
 	Namespace         string
 	Schema            string
+	Workload          v1alpha1.WorkloadSpec
 }
 
 func (xc *XJoinCore) SetName(name string) {
@@ -34,7 +36,7 @@ func (xc XJoinCore) Name() string {
 	return xc.name + "-" + xc.version
 }
 
-func (xc XJoinCore) Create() (err error) {
+func (xc XJoinCore) deployment() (*unstructured.Unstructured, error) {
 	deployment := &unstructured.Unstructured{}
 
 	labels := map[string]interface{}{
@@ -42,72 +44,76 @@ func (xc XJoinCore) Create() (err error) {
 		"xjoin.index": xc.name,
 	}
 
+	container := map[string]interface{}{
+		"env": []map[string]interface{}{
+			{
+				"name":  "SOURCE_TOPICS",
+				"value": xc.SourceTopics,
+			},
+			{
+				"name":  "SINK_TOPIC",
+				"value": xc.SinkTopic,
+			},
+			{
+				"name":  "SCHEMA_REGISTRY_URL",
+				"value": xc.SchemaRegistryURL + "/apis/registry/v2",
+			},
+			{
+				"name":  "KAFKA_BOOTSTRAP",
+				"value": xc.KafkaBootstrap,
+			},
+			{
+				"name":  "SINK_SCHEMA",
+				"value": xc.Schema,
+			},
+		},
+		"name": xc.Name(),
+	}
+	podSpec := map[string]interface{}{
+		"containers": []map[string]interface{}{container},
+	}
+	deploymentSpec := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": labels,
+		},
+		"strategy": map[string]interface{}{
+			"rollingUpdate": map[string]interface{}{
+				"maxSurge":       "25%",
+				"maxUnavailable": "25%",
+			},
+			"type": "RollingUpdate",
+		},
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": labels,
+			},
+			"spec": podSpec,
+		},
+	}
+
+	err := applyWorkload(xc.Workload, deploymentSpec, podSpec, container)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
 	deployment.Object = map[string]interface{}{
 		"metadata": map[string]interface{}{
 			"name":      xc.Name(),
 			"namespace": xc.Namespace,
 			"labels":    labels,
 		},
-		"spec": map[string]interface{}{
-			"replicas": 1,
-			"selector": map[string]interface{}{
-				"matchLabels": labels,
-			},
-			"strategy": map[string]interface{}{
-				"rollingUpdate": map[string]interface{}{
-					"maxSurge":       "25%",
-					"maxUnavailable": "25%",
-				},
-				"type": "RollingUpdate",
-			},
-			"template": map[string]interface{}{
-				"metadata": map[string]interface{}{
-					"labels": labels,
-				},
-				"spec": map[string]interface{}{
-					"containers": []map[string]interface{}{{
-						"env": []map[string]interface{}{
-							{
-								"name":  "SOURCE_TOPICS",
-								"value": xc.SourceTopics,
-							},
-							{
-								"name":  "SINK_TOPIC",
-								"value": xc.SinkTopic,
-							},
-							{
-								"name":  "SCHEMA_REGISTRY_URL",
-								"value": xc.SchemaRegistryURL + "/apis/registry/v2",
-							},
-							{
-								"name":  "KAFKA_BOOTSTRAP",
-								"value": xc.KafkaBootstrap,
-							},
-							{
-								"name":  "SINK_SCHEMA",
-								"value": xc.Schema,
-							},
-						},
-						"image":           "quay.io/cloudservices/xjoin-core:latest",
-						"imagePullPolicy": "Always",
-						"name":            xc.Name(),
-						"resources": map[string]interface{}{
-							"limits": map[string]interface{}{
-								"cpu":    "250m",
-								"memory": "512Mi",
-							},
-							"requests": map[string]interface{}{
-								"cpu":    "100m",
-								"memory": "64Mi",
-							},
-						},
-					}},
-				},
-			},
-		},
+		"spec": deploymentSpec,
 	}
 
 	deployment.SetGroupVersionKind(common.DeploymentGVK)
+	return deployment, nil
+}
+
+func (xc XJoinCore) Create() (err error) {
+	deployment, err := xc.deployment()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
 
 	err = xc.Client.Create(xc.Context, deployment)
 	if err != nil {
@@ -132,7 +138,44 @@ func (xc XJoinCore) Delete() (err error) {
 	return
 }
 
+// Rollout applies a changed workload to the deployment
+func (xc *XJoinCore) Rollout() (err error) {
+	deployment, err := xc.deployment()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	err = rolloutDeploymentWorkload(xc.Context, xc.Client, deployment, xc.Workload)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+// EvolveSchema rolls out the deployment with the updated Avro schema
+func (xc *XJoinCore) EvolveSchema() (err error) {
+	deployment, err := xc.deployment()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	err = evolveDeployment(xc.Context, xc.Client, deployment)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
 func (xc *XJoinCore) CheckDeviation() (problem, err error) {
+	deployment, err := xc.deployment()
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	problem, err = checkDeploymentDeviation(xc.Context, xc.Client, deployment)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
 	return
 }
 
@@ -0,0 +1,160 @@
+package components
+
+import (
+	"context"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
+)
+
+type XJoinDataSourceValidator struct {
+	name                string
+	version             string
+	Client              client.Client
+	Context             context.Context
+	Namespace           string
+	Schema              string
+	Pause               bool
+	ParentInstance      client.Object
+	TopicName           string
+	DatabaseHostname    *v1alpha1.StringOrSecretParameter
+	DatabasePort        *v1alpha1.StringOrSecretParameter
+	DatabaseUsername    *v1alpha1.StringOrSecretParameter
+	DatabasePassword    *v1alpha1.StringOrSecretParameter
+	DatabaseName        *v1alpha1.StringOrSecretParameter
+	DatabaseTable       *v1alpha1.StringOrSecretParameter
+	DatabaseSSLMode     *v1alpha1.StringOrSecretParameter
+	DatabaseSSLRootCert *v1alpha1.StringOrSecretParameter
+	DatabaseSSLCert     *v1alpha1.StringOrSecretParameter
+	DatabaseSSLKey      *v1alpha1.StringOrSecretParameter
+	DatabaseType        string
+	RowFilter           *v1alpha1.RowFilter
+}
+
+func (dv *XJoinDataSourceValidator) SetName(name string) {
+	dv.name = strings.ToLower(name)
+}
+
+func (dv *XJoinDataSourceValidator) SetVersion(version string) {
+	dv.version = version
+}
+
+func (dv *XJoinDataSourceValidator) Name() string {
+	return dv.name + "." + dv.version
+}
+
+func (dv *XJoinDataSourceValidator) Create() (err error) {
+	dataSourceValidator := unstructured.Unstructured{}
+	dataSourceValidator.Object = map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      dv.Name(),
+			"namespace": dv.Namespace,
+			"labels": map[string]interface{}{
+				common.COMPONENT_NAME_LABEL: dv.name,
+				"app":                       "xjoin-validator",
+			},
+		},
+		"spec": map[string]interface{}{
+			"name":                dv.name,
+			"version":             dv.version,
+			"avroSchema":          dv.Schema,
+			"pause":               dv.Pause,
+			"topicName":           dv.TopicName,
+			"databaseHostname":    dv.DatabaseHostname,
+			"databasePort":        dv.DatabasePort,
+			"databaseUsername":    dv.DatabaseUsername,
+			"databasePassword":    dv.DatabasePassword,
+			"databaseName":        dv.DatabaseName,
+			"databaseTable":       dv.DatabaseTable,
+			"databaseSSLMode":     dv.DatabaseSSLMode,
+			"databaseSSLRootCert": dv.DatabaseSSLRootCert,
+			"databaseSSLCert":     dv.DatabaseSSLCert,
+			"databaseSSLKey":      dv.DatabaseSSLKey,
+			"databaseType":        dv.DatabaseType,
+			"rowFilter":           dv.RowFilter,
+		},
+	}
+	dataSourceValidator.SetGroupVersionKind(common.DataSourceValidatorGVK)
+
+	blockOwnerDeletion := true
+	controller := true
+	ownerReference := metav1.OwnerReference{
+		APIVersion:         common.DataSourcePipelineGVK.Version,
+		Kind:               common.DataSourcePipelineGVK.Kind,
+		Name:               dv.ParentInstance.GetName(),
+		UID:                dv.ParentInstance.GetUID(),
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+	dataSourceValidator.SetOwnerReferences([]metav1.OwnerReference{ownerReference})
+
+	ctx, cancel := utils.DefaultContext()
+	defer cancel()
+	err = dv.Client.Create(ctx, &dataSourceValidator)
+
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+func (dv *XJoinDataSourceValidator) Delete() (err error) {
+	dataSourceValidator := &unstructured.Unstructured{}
+	dataSourceValidator.SetGroupVersionKind(common.DataSourceValidatorGVK)
+	err = dv.Client.Get(dv.Context, client.ObjectKey{Name: dv.Name(), Namespace: dv.Namespace}, dataSourceValidator)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	err = dv.Client.Delete(dv.Context, dataSourceValidator)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+func (dv *XJoinDataSourceValidator) CheckDeviation() (problem, err error) {
+	return
+}
+
+func (dv *XJoinDataSourceValidator) Exists() (exists bool, err error) {
+	validators := &unstructured.UnstructuredList{}
+	validators.SetGroupVersionKind(common.DataSourceValidatorGVK)
+	fields := client.MatchingFields{}
+	fields["metadata.name"] = dv.Name()
+	fields["metadata.namespace"] = dv.Namespace
+	err = dv.Client.List(dv.Context, validators, fields)
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+
+	if len(validators.Items) > 0 {
+		exists = true
+	}
+	return
+}
+
+func (dv *XJoinDataSourceValidator) ListInstalledVersions() (versions []string, err error) {
+	validators := &unstructured.UnstructuredList{}
+	validators.SetGroupVersionKind(common.DataSourceValidatorGVK)
+	labels := client.MatchingLabels{}
+	labels[common.COMPONENT_NAME_LABEL] = dv.name
+	fields := client.MatchingFields{
+		"metadata.namespace": dv.Namespace,
+	}
+	err = dv.Client.List(dv.Context, validators, labels, fields)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	for _, validator := range validators.Items {
+		versions = append(versions, validator.GetName())
+	}
+
+	return
+}
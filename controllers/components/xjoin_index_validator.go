@@ -4,9 +4,13 @@ import (
 	"context"
 	"github.com/go-errors/errors"
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
@@ -22,6 +26,8 @@ type XJoinIndexValidator struct {
 	Pause                  bool
 	ParentInstance         client.Object
 	ElasticsearchIndexName string
+	Workload               *v1alpha1.WorkloadSpec
+	Validation             *v1alpha1.XJoinIndexValidation
 }
 
 func (xv *XJoinIndexValidator) SetName(name string) {
@@ -55,6 +61,20 @@ func (xv *XJoinIndexValidator) Create() (err error) {
 			"indexName":  xv.ElasticsearchIndexName,
 		},
 	}
+	if xv.Workload != nil {
+		workload, err := runtime.DefaultUnstructuredConverter.ToUnstructured(xv.Workload)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		indexValidator.Object["spec"].(map[string]interface{})["workload"] = workload
+	}
+	if xv.Validation != nil {
+		validation, err := runtime.DefaultUnstructuredConverter.ToUnstructured(xv.Validation)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		indexValidator.Object["spec"].(map[string]interface{})["validation"] = validation
+	}
 	indexValidator.SetGroupVersionKind(common.IndexValidatorGVK)
 
 	//create child resource
@@ -112,6 +132,95 @@ func (xv *XJoinIndexValidator) CheckDeviation() (problem, err error) {
 	return
 }
 
+// Rollout updates the validator's workload and validation thresholds, they are used by the next validation job
+func (xv *XJoinIndexValidator) Rollout() (err error) {
+	indexValidator := &unstructured.Unstructured{}
+	indexValidator.SetGroupVersionKind(common.IndexValidatorGVK)
+	err = xv.Client.Get(xv.Context, client.ObjectKey{Name: xv.Name(), Namespace: xv.Namespace}, indexValidator)
+	if k8errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	workloadUpdated, err := setValidatorSpecField(indexValidator, "workload", xv.Workload)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	validationUpdated, err := setValidatorSpecField(indexValidator, "validation", xv.Validation)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	if !workloadUpdated && !validationUpdated {
+		return
+	}
+
+	err = xv.Client.Update(xv.Context, indexValidator)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+// setValidatorSpecField sets a field of the unstructured validator's spec to value, a nil value removes the field
+func setValidatorSpecField(indexValidator *unstructured.Unstructured, field string, value interface{}) (
+	updated bool, err error) {
+
+	current, _, err := unstructured.NestedMap(indexValidator.Object, "spec", field)
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	var expected map[string]interface{}
+	if !reflect.ValueOf(value).IsNil() {
+		expected, err = runtime.DefaultUnstructuredConverter.ToUnstructured(value)
+		if err != nil {
+			return false, errors.Wrap(err, 0)
+		}
+	}
+	if equality.Semantic.DeepEqual(current, expected) {
+		return false, nil
+	}
+
+	if expected == nil {
+		unstructured.RemoveNestedField(indexValidator.Object, "spec", field)
+	} else {
+		err = unstructured.SetNestedMap(indexValidator.Object, expected, "spec", field)
+		if err != nil {
+			return false, errors.Wrap(err, 0)
+		}
+	}
+	return true, nil
+}
+
+// EvolveSchema validates the index with the updated Avro schema
+func (xv *XJoinIndexValidator) EvolveSchema() (err error) {
+	indexValidator := &unstructured.Unstructured{}
+	indexValidator.SetGroupVersionKind(common.IndexValidatorGVK)
+	err = xv.Client.Get(xv.Context, client.ObjectKey{Name: xv.Name(), Namespace: xv.Namespace}, indexValidator)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	schema, _, err := unstructured.NestedString(indexValidator.Object, "spec", "avroSchema")
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	if schema == xv.Schema {
+		return
+	}
+
+	err = unstructured.SetNestedField(indexValidator.Object, xv.Schema, "spec", "avroSchema")
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	err = xv.Client.Update(xv.Context, indexValidator)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
 func (xv *XJoinIndexValidator) Exists() (exists bool, err error) {
 	validators := &unstructured.UnstructuredList{}
 	validators.SetGroupVersionKind(common.IndexValidatorGVK)
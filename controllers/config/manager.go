@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
+	"strings"
 )
 
 type Spec interface{}
@@ -139,10 +140,15 @@ func (m *Manager) loadSecrets() error {
 func (m *Manager) parseParameterValue(param Parameter) (value interface{}, err error) {
 	if param.SpecKey != "" {
 		specReflection := reflect.ValueOf(&m.spec).Elem().Elem()
-		field := specReflection.FieldByName(param.SpecKey)
+		field := specField(specReflection, param.SpecKey)
 
 		if !field.IsValid() {
 			log.Debug(fmt.Sprintf("key %s not found in spec", param.SpecKey))
+		} else if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() != reflect.Struct {
+			//an optional value of the spec, e.g. *int, the ConfigMap is used when it is not set
+			if !field.IsNil() {
+				return field.Elem().Interface(), nil
+			}
 		} else if field.Type() == reflect.TypeOf(&v1alpha1.StringOrSecretParameter{}) {
 			fieldParam := field.Interface().(*v1alpha1.StringOrSecretParameter)
 			if fieldParam == nil {
@@ -157,6 +163,9 @@ func (m *Manager) parseParameterValue(param Parameter) (value interface{}, err e
 				}
 				value = readSecretValue(*secret, []string{fieldParam.ValueFrom.SecretKeyRef.Key})
 			}
+			if value != nil {
+				return value, nil
+			}
 		} else {
 			value = field.Interface()
 			if err != nil {
@@ -223,6 +232,25 @@ func (m *Manager) parseParameterValue(param Parameter) (value interface{}, err e
 	return value, nil
 }
 
+// specField looks up a field of the spec by its name, the names of nested fields are separated by dots.
+// The field is invalid when it does not exist or a struct pointer on its path is nil.
+func specField(spec reflect.Value, key string) (field reflect.Value) {
+	field = spec
+	for _, name := range strings.Split(key, ".") {
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				return reflect.Value{}
+			}
+			field = field.Elem()
+		}
+		field = field.FieldByName(name)
+		if !field.IsValid() {
+			return
+		}
+	}
+	return
+}
+
 func readSecretValue(secret v1.Secret, keys []string) (value string) {
 	for _, key := range keys {
 		value = string(secret.Data[key])
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -14,8 +16,9 @@ import (
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
 
 	"github.com/go-errors/errors"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/redhatinsights/xjoin-operator/controllers/data"
 	logger "github.com/redhatinsights/xjoin-operator/controllers/log"
 )
@@ -25,6 +28,8 @@ var log = logger.NewLogger("database")
 type Database struct {
 	connection *sqlx.DB
 	Config     DBParams
+
+	certificateFiles []string //written for this connection, removed by Close
 }
 
 type DBParams struct {
@@ -35,6 +40,8 @@ type DBParams struct {
 	Port        string
 	SSLMode     string
 	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
 }
 
 func NewDatabase(config DBParams) *Database {
@@ -49,30 +56,97 @@ func (db *Database) Connect() (err error) {
 	}
 
 	if db.connection, err = db.GetConnection(); err != nil {
+		db.removeCertificateFiles()
 		return fmt.Errorf("error connecting to %s:%s/%s as %s : %s", db.Config.Host, db.Config.Port, db.Config.Name, db.Config.User, err)
 	}
 
 	return nil
 }
 
-func (db *Database) GetConnection() (connection *sqlx.DB, err error) {
-	connectionStringTemplate := "host=%s user=%s password=%s port=%s sslmode=%s"
+// ConnectionString is the lib/pq connection string of the database. Each value is quoted, so values with spaces or
+// quotes don't corrupt the connection string.
+func (db *Database) ConnectionString() (connStr string, err error) {
+	params := [][2]string{
+		{"host", db.Config.Host},
+		{"user", db.Config.User},
+		{"password", db.Config.Password},
+		{"port", db.Config.Port},
+		{"sslmode", db.Config.SSLMode},
+	}
 
+	var certificates [][2]string
 	if db.Config.SSLMode != "disable" {
-		connectionStringTemplate = connectionStringTemplate + " sslrootcert=" + db.Config.SSLRootCert
+		certificates = append(certificates, [2]string{"sslrootcert", db.Config.SSLRootCert})
+	}
+	certificates = append(certificates, [2]string{"sslcert", db.Config.SSLCert}, [2]string{"sslkey", db.Config.SSLKey})
+	for _, certificate := range certificates {
+		if certificate[1] == "" {
+			continue
+		}
+		certificatePath, err := db.certificateFile(certificate[1])
+		if err != nil {
+			return "", errors.Wrap(err, 0)
+		}
+		params = append(params, [2]string{certificate[0], certificatePath})
 	}
-
-	connStr := fmt.Sprintf(
-		connectionStringTemplate,
-		db.Config.Host,
-		db.Config.User,
-		db.Config.Password,
-		db.Config.Port,
-		db.Config.SSLMode)
 
 	//db.Config.Name is empty before creating the test database
 	if db.Config.Name != "" {
-		connStr = connStr + " dbname=" + db.Config.Name
+		params = append(params, [2]string{"dbname", db.Config.Name})
+	}
+
+	var connParams []string
+	for _, param := range params {
+		connParams = append(connParams, param[0]+"="+quoteConnectionValue(param[1]))
+	}
+	return strings.Join(connParams, " "), nil
+}
+
+func quoteConnectionValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// certificateFile is the path of a certificate. A certificate stored in a secret is resolved to its PEM contents by
+// the operator, which are written to a file because lib/pq only reads certificates from files. The file belongs to
+// this connection and is removed by Close.
+func (db *Database) certificateFile(certificate string) (string, error) {
+	if !strings.HasPrefix(strings.TrimSpace(certificate), "-----BEGIN") {
+		return certificate, nil
+	}
+
+	//the temporary file is only readable by the operator, as lib/pq requires for keys
+	file, err := os.CreateTemp(os.TempDir(), "xjoin-db-ssl-*.pem")
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	_, err = file.WriteString(certificate)
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", errors.Wrap(err, 0)
+	}
+
+	db.certificateFiles = append(db.certificateFiles, file.Name())
+	return file.Name(), nil
+}
+
+func (db *Database) removeCertificateFiles() {
+	for _, certificateFile := range db.certificateFiles {
+		err := os.Remove(certificateFile)
+		if err != nil && !os.IsNotExist(err) {
+			log.Error(err, "Unable to remove the certificate file", "file", certificateFile)
+		}
+	}
+	db.certificateFiles = nil
+}
+
+func (db *Database) GetConnection() (connection *sqlx.DB, err error) {
+	connStr, err := db.ConnectionString()
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
 	}
 
 	if connection, err = sqlx.Connect("postgres", connStr); err != nil {
@@ -83,6 +157,7 @@ func (db *Database) GetConnection() (connection *sqlx.DB, err error) {
 }
 
 func (db *Database) Close() error {
+	db.removeCertificateFiles()
 	if db.connection != nil {
 		return db.connection.Close()
 	}
@@ -97,11 +172,11 @@ func (db *Database) SetMaxConnections(numConnections int) {
 	}
 }
 
-func (db *Database) RunQuery(query string) (*sqlx.Rows, error) {
+func (db *Database) RunQuery(query string, args ...interface{}) (*sqlx.Rows, error) {
 	if db.connection == nil {
 		return nil, errors.New("cannot run query because there is no database connection")
 	}
-	rows, err := db.connection.Queryx(query)
+	rows, err := db.connection.Queryx(query, args...)
 
 	if err != nil {
 		return nil, fmt.Errorf("error executing query (%s) : %w", query, err)
@@ -123,6 +198,202 @@ func (db *Database) ExecQuery(query string) (result sql.Result, err error) {
 	return result, nil
 }
 
+// quoteQualifiedIdentifier quotes each part of a schema qualified table name
+func quoteQualifiedIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = pq.QuoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// IdsCondition is an SQL condition matching the rows of a table whose column is one of ids
+func IdsCondition(column string, ids []string) string {
+	quotedIds := make([]string, len(ids))
+	for i, id := range ids {
+		quotedIds[i] = pq.QuoteLiteral(id)
+	}
+	return pq.QuoteIdentifier(column) + " IN (" + strings.Join(quotedIds, ",") + ")"
+}
+
+// SignalDataMaxLength is the size of the data column of a Debezium signaling table
+const SignalDataMaxLength = 2048
+
+// IdsSignalConditions splits ids into as few conditions as possible whose incremental snapshot signals for
+// dataCollection fit into the data column of a signaling table. Each condition is combined with filter when it is set.
+func IdsSignalConditions(
+	dataCollection string, filter string, column string, ids []string) (conditions []string, err error) {
+
+	idsCondition := func(ids []string) string {
+		if filter == "" {
+			return IdsCondition(column, ids)
+		}
+		return "(" + filter + ") AND " + IdsCondition(column, ids)
+	}
+	fits := func(ids []string) (bool, error) {
+		signalData, err := incrementalSnapshotSignalData(dataCollection, idsCondition(ids))
+		return len(signalData) <= SignalDataMaxLength, err
+	}
+
+	var chunk []string
+	for _, id := range ids {
+		candidate := append(append([]string{}, chunk...), id)
+		ok, err := fits(candidate)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+
+		if !ok && len(chunk) > 0 {
+			conditions = append(conditions, idsCondition(chunk))
+			candidate = []string{id}
+			ok, err = fits(candidate)
+			if err != nil {
+				return nil, errors.Wrap(err, 0)
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("the signal for id %s is longer than %d characters", id, SignalDataMaxLength)
+		}
+		chunk = candidate
+	}
+
+	if len(chunk) > 0 {
+		conditions = append(conditions, idsCondition(chunk))
+	}
+	return
+}
+
+func incrementalSnapshotSignalData(dataCollection string, condition string) (string, error) {
+	data := map[string]interface{}{
+		"type":             "incremental",
+		"data-collections": []string{dataCollection},
+	}
+	if condition != "" {
+		data["additional-conditions"] = []map[string]string{{
+			"data-collection": dataCollection,
+			"filter":          condition,
+		}}
+	}
+	signalData, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	return string(signalData), nil
+}
+
+// SendIncrementalSnapshotSignal inserts an execute-snapshot signal into a Debezium signaling table. The connector then
+// re-emits the rows of dataCollection matching condition, or every row when condition is empty.
+func (db *Database) SendIncrementalSnapshotSignal(
+	signalTable string, dataCollection string, condition string) (signalId string, err error) {
+
+	if db.connection == nil {
+		return "", errors.New("cannot send signal because there is no database connection")
+	}
+
+	signalData, err := incrementalSnapshotSignalData(dataCollection, condition)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	if len(signalData) > SignalDataMaxLength {
+		return "", fmt.Errorf("the signal is longer than the %d characters of the data column", SignalDataMaxLength)
+	}
+
+	signalId = uuid.NewString()
+	query := "INSERT INTO " + quoteQualifiedIdentifier(signalTable) + " (id, type, data) VALUES ($1, $2, $3)"
+	_, err = db.connection.Exec(query, signalId, "execute-snapshot", signalData)
+	if err != nil {
+		return "", fmt.Errorf("error sending signal to %s : %w", signalTable, err)
+	}
+
+	return signalId, nil
+}
+
+// CreateSignalTable creates a Debezium signaling table unless it already exists
+func (db *Database) CreateSignalTable(signalTable string) error {
+	_, err := db.ExecQuery("CREATE TABLE IF NOT EXISTS " + quoteQualifiedIdentifier(signalTable) +
+		" (id VARCHAR(42) PRIMARY KEY, type VARCHAR(32) NOT NULL, data VARCHAR(" + strconv.Itoa(SignalDataMaxLength) + ") NULL)")
+	return err
+}
+
+// CountSnapshotChunks counts the chunk watermarks Debezium has written to a signaling table since the signal with
+// signalId. Each incremental snapshot chunk is closed by a snapshot-window-close row. The rows do not name their
+// snapshot, they are attributed to the signal by being written in a later transaction. age(xmin) handles the
+// wraparound of transaction ids.
+func (db *Database) CountSnapshotChunks(signalTable string, signalId string) (int, error) {
+	table := quoteQualifiedIdentifier(signalTable)
+	return db.countQuery("SELECT count(*) FROM "+table+" WHERE type = 'snapshot-window-close' "+
+		"AND age(xmin) < (SELECT age(xmin) FROM "+table+" WHERE id = $1)", signalId)
+}
+
+// CountRows counts the rows of table matching condition, or every row when condition is empty
+func (db *Database) CountRows(table string, condition string) (int, error) {
+	query := "SELECT count(*) FROM " + quoteQualifiedIdentifier(table)
+	if condition != "" {
+		query = query + " WHERE " + condition
+	}
+	return db.countQuery(query)
+}
+
+// Column is a column of a table read from information_schema.columns
+type Column struct {
+	Name       string `db:"column_name"`
+	DataType   string `db:"data_type"` //e.g. timestamp with time zone, ARRAY, USER-DEFINED
+	UDTName    string `db:"udt_name"`  //e.g. timestamptz, _text, citext
+	Nullable   bool   `db:"nullable"`
+	PrimaryKey bool   `db:"primary_key"`
+}
+
+// TableColumns lists the columns of a schema qualified table in their ordinal position
+func (db *Database) TableColumns(table string) (columns []Column, err error) {
+	if db.connection == nil {
+		return nil, errors.New("cannot list columns because there is no database connection")
+	}
+
+	schema, name := "public", table
+	if parts := strings.SplitN(table, ".", 2); len(parts) == 2 {
+		schema, name = parts[0], parts[1]
+	}
+
+	query := `SELECT c.column_name, c.data_type, c.udt_name, c.is_nullable = 'YES' AS nullable,
+		EXISTS (
+			SELECT 1 FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+				ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name
+			WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema
+				AND tc.table_name = c.table_name AND kcu.column_name = c.column_name
+		) AS primary_key
+		FROM information_schema.columns c
+		WHERE c.table_schema = $1 AND c.table_name = $2
+		ORDER BY c.ordinal_position`
+	err = db.connection.Select(&columns, query, schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("error listing the columns of %s : %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	return columns, nil
+}
+
+func (db *Database) countQuery(query string, args ...interface{}) (int, error) {
+	rows, err := db.RunQuery(query, args...)
+	if err != nil {
+		return -1, err
+	}
+	defer closeRows(rows)
+
+	var count int
+	for rows.Next() {
+		err = rows.Scan(&count)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	return count, nil
+}
+
 func (db *Database) hostCountQuery() string {
 	return "SELECT count(*) FROM hosts"
 }
@@ -193,6 +464,76 @@ func (db *Database) RemoveReplicationSlot(slot string) error {
 	return nil
 }
 
+// ReplicationSlotLag is the WAL retained by a replication slot
+type ReplicationSlotLag struct {
+	Active                 bool
+	RetainedWALBytes       int64 //WAL between the slot's restart_lsn and the current WAL position
+	ConfirmedFlushLagBytes int64 //WAL between the slot's confirmed_flush_lsn and the current WAL position
+}
+
+// GetReplicationSlotLag returns nil when the replication slot does not exist
+func (db *Database) GetReplicationSlotLag(slot string) (*ReplicationSlotLag, error) {
+	if db.connection == nil {
+		return nil, errors.New("cannot run query because there is no database connection")
+	}
+
+	query := `SELECT active,
+		COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), restart_lsn), 0)::bigint,
+		COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), confirmed_flush_lsn), 0)::bigint
+		FROM pg_catalog.pg_replication_slots WHERE slot_name = $1`
+
+	var lag ReplicationSlotLag
+	err := db.connection.QueryRowx(query, slot).Scan(&lag.Active, &lag.RetainedWALBytes, &lag.ConfirmedFlushLagBytes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error executing query (%s) : %w", query, err)
+	}
+
+	return &lag, nil
+}
+
+// TerminateAndRemoveReplicationSlot disconnects the slot's consumer before removing an active replication slot
+func (db *Database) TerminateAndRemoveReplicationSlot(slot string) error {
+	if db.connection == nil {
+		return errors.New("cannot run query because there is no database connection")
+	}
+
+	_, err := db.connection.Exec(`SELECT pg_terminate_backend(active_pid) FROM pg_catalog.pg_replication_slots
+		WHERE slot_name = $1 AND active_pid IS NOT NULL`, slot)
+	if err != nil {
+		return fmt.Errorf("error terminating the consumer of replication slot %s : %w", slot, err)
+	}
+
+	return db.RemoveReplicationSlot(slot)
+}
+
+// ListPublications returns the names of the publications that start with prefix
+func (db *Database) ListPublications(prefix string) (publications []string, err error) {
+	rows, err := db.RunQuery("SELECT pubname FROM pg_catalog.pg_publication")
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var publication string
+		err = rows.Scan(&publication)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(publication, prefix) {
+			publications = append(publications, publication)
+		}
+	}
+	return publications, nil
+}
+
+func (db *Database) RemovePublication(publication string) error {
+	_, err := db.ExecQuery("DROP PUBLICATION IF EXISTS " + pq.QuoteIdentifier(publication))
+	return err
+}
+
 func (db *Database) RemoveReplicationSlotsForPrefix(resourceNamePrefix string) error {
 	prefix := ReplicationSlotPrefix(resourceNamePrefix)
 	rows, err := db.RunQuery(
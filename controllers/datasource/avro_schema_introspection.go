@@ -0,0 +1,124 @@
+package datasource
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-go-lib/pkg/avro"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/database"
+)
+
+// postgresAvroTypes are the Avro types the Debezium postgres connector produces for each column udt_name.
+// Debezium emits timestamp columns as epoch microseconds and date columns as epoch days. Elasticsearch has no date
+// format for either, so their xjoin.type keeps them numeric for range queries instead of mapping them to a date.
+var postgresAvroTypes = map[string]avro.Type{
+	"uuid":        {Type: "string", ConnectVersion: 1, ConnectName: "io.debezium.data.Uuid", XJoinType: "string"},
+	"text":        {Type: "string", XJoinType: "string"},
+	"varchar":     {Type: "string", XJoinType: "string"},
+	"bpchar":      {Type: "string", XJoinType: "string"},
+	"citext":      {Type: "string", XJoinType: "string"},
+	"inet":        {Type: "string", XJoinType: "string"},
+	"json":        {Type: "string", ConnectVersion: 1, ConnectName: "io.debezium.data.Json", XJoinType: "json"},
+	"jsonb":       {Type: "string", ConnectVersion: 1, ConnectName: "io.debezium.data.Json", XJoinType: "json"},
+	"timestamptz": {Type: "string", ConnectVersion: 1, ConnectName: "io.debezium.time.ZonedTimestamp", XJoinType: "date_nanos"},
+	"timestamp":   {Type: "long", ConnectVersion: 1, ConnectName: "io.debezium.time.MicroTimestamp", XJoinType: "timestamp-micros"},
+	"date":        {Type: "int", ConnectVersion: 1, ConnectName: "io.debezium.time.Date", XJoinType: "date"},
+	"bool":        {Type: "boolean", XJoinType: "boolean"},
+	"int2":        {Type: "int", XJoinType: "int"},
+	"int4":        {Type: "int", XJoinType: "int"},
+	"int8":        {Type: "long", XJoinType: "long"},
+	"float4":      {Type: "float", XJoinType: "float"},
+	"float8":      {Type: "double", XJoinType: "double"},
+	"numeric":     {Type: "double", XJoinType: "decimal"},
+}
+
+type introspectedSchema struct {
+	Type      string              `json:"type"`
+	Name      string              `json:"name"`
+	Namespace string              `json:"namespace"`
+	Fields    []introspectedField `json:"fields"`
+}
+
+type introspectedField struct {
+	Name    string          `json:"name"`
+	Type    interface{}     `json:"type"`
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// AvroSchemaFromColumns generates the Avro schema of a datasource from its table's columns. Nullable columns are
+// optional fields. Columns of types without a mapping are returned as skipped, they are left out of the schema.
+func AvroSchemaFromColumns(dataSourceName string, columns []database.Column) (schema string, skipped []string, err error) {
+	record := introspectedSchema{
+		Type:      "record",
+		Name:      "Value",
+		Namespace: "xjoindatasourcepipeline." + dataSourceName,
+	}
+
+	for _, column := range columns {
+		avroType, ok := postgresAvroTypes[column.UDTName]
+		if !ok {
+			skipped = append(skipped, column.Name+" ("+column.DataType+")")
+			continue
+		}
+		avroType.XJoinPrimaryKey = column.PrimaryKey
+
+		field := introspectedField{Name: column.Name, Type: avroType}
+		if column.Nullable && !column.PrimaryKey {
+			field.Type = []interface{}{"null", avroType}
+			field.Default = json.RawMessage("null")
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	if len(record.Fields) == 0 {
+		return "", skipped, errors.Wrap(errors.New("none of the table's columns has a supported type"), 0)
+	}
+
+	schemaBytes, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return "", skipped, errors.Wrap(err, 0)
+	}
+	return string(schemaBytes), skipped, nil
+}
+
+// IntrospectAvroSchema generates the datasource's Avro schema from the columns of its databaseTable
+func (i *XJoinDataSourceIteration) IntrospectAvroSchema() (schema string, err error) {
+	if i.GetInstance().KafkaTopicSource() != nil {
+		return "", errors.Wrap(errors.New("avroSchemaFrom introspect requires a database table"), 0)
+	}
+
+	//information_schema is read through the operator's Postgres connection
+	databaseType, err := databaseTypeOf(i.Parameters)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	} else if databaseType.Name != v1alpha1.DatabaseTypePostgres {
+		return "", errors.Wrap(
+			fmt.Errorf("avroSchemaFrom introspect is not supported for databaseType %s", databaseType.Name), 0)
+	}
+
+	table := i.Parameters.DatabaseTable.String()
+	err = withDatabase(i.Parameters, i.Log, func(db *database.Database) error {
+		columns, err := db.TableColumns(table)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		var skipped []string
+		schema, skipped, err = AvroSchemaFromColumns(i.GetInstance().Name, columns)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		if len(skipped) > 0 {
+			i.Log.Info("Columns with unsupported types are left out of the introspected Avro schema",
+				"table", table, "columns", skipped)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	return schema, nil
+}
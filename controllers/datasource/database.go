@@ -0,0 +1,80 @@
+package datasource
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/database"
+	logger "github.com/redhatinsights/xjoin-operator/controllers/log"
+	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
+	"strings"
+)
+
+// ConnectExternalConfigurationPath is where Strimzi mounts the externalConfiguration volumes of Kafka Connect
+const ConnectExternalConfigurationPath = "/opt/kafka/external-configuration"
+
+// OperatorDBParams are the parameters of the operator's own connections to the datasource's database. Certificates
+// under the ConnectExternalConfigurationPath, e.g. the default root certificate, only exist in Kafka Connect, so the
+// operator does not use them.
+func OperatorDBParams(p parameters.DataSourceParameters) database.DBParams {
+	return database.DBParams{
+		User:        p.DatabaseUsername.String(),
+		Password:    p.DatabasePassword.String(),
+		Host:        p.DatabaseHostname.String(),
+		Name:        p.DatabaseName.String(),
+		Port:        p.DatabasePort.String(),
+		SSLMode:     p.DatabaseSSLMode.String(),
+		SSLRootCert: operatorCertificate(p.DatabaseSSLRootCert.String()),
+		SSLCert:     operatorCertificate(p.DatabaseSSLCert.String()),
+		SSLKey:      operatorCertificate(p.DatabaseSSLKey.String()),
+	}
+}
+
+func operatorCertificate(certificate string) string {
+	if strings.HasPrefix(certificate, ConnectExternalConfigurationPath+"/") {
+		return ""
+	}
+	return certificate
+}
+
+// DBParams are the connection parameters of the datasource's database
+func (i *XJoinDataSourceIteration) DBParams() database.DBParams {
+	return OperatorDBParams(i.Parameters)
+}
+
+// DBParams are the connection parameters of the datasource pipeline's database
+func (i *XJoinDataSourcePipelineIteration) DBParams() database.DBParams {
+	return OperatorDBParams(i.Parameters)
+}
+
+// CertificateTemplateParameters sets the connector's certificates to their paths in Kafka Connect. A certificate
+// stored in a secret is read from the externalConfiguration volume named after the secret.
+func (i *XJoinDataSourcePipelineIteration) CertificateTemplateParameters(templateParameters map[string]interface{}) {
+	spec := i.GetInstance().Spec
+	certificates := map[string]*v1alpha1.StringOrSecretParameter{
+		"DatabaseSSLRootCert": spec.DatabaseSSLRootCert,
+		"DatabaseSSLCert":     spec.DatabaseSSLCert,
+		"DatabaseSSLKey":      spec.DatabaseSSLKey,
+	}
+	for key, certificate := range certificates {
+		if certificate != nil {
+			templateParameters[key] = certificate.FilePath(ConnectExternalConfigurationPath)
+		}
+	}
+}
+
+// withDatabase runs query with a connection to the datasource's database
+func withDatabase(p parameters.DataSourceParameters, log logger.Log, query func(db *database.Database) error) (err error) {
+	db := database.NewDatabase(OperatorDBParams(p))
+	err = db.Connect()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			log.Error(closeErr, "Unable to close the database connection")
+		}
+	}()
+
+	return query(db)
+}
@@ -0,0 +1,179 @@
+package datasource
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/config"
+	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
+	"sort"
+	"strings"
+)
+
+// DatabaseType is how the Debezium source connector of a datasource's databaseType is configured
+type DatabaseType struct {
+	Name           string
+	ConnectorClass string
+
+	// ReplicationSlots is true when the connector streams from a replication slot that is managed by the operator
+	ReplicationSlots bool
+
+	// Signals is true when the operator can send incremental snapshot signals to the database
+	Signals bool
+
+	// SchemaHistory is true when the connector records the database's schema history in a Kafka topic
+	SchemaHistory bool
+
+	// ClientCertificates is true when the connector accepts the databaseSSLCert and databaseSSLKey files
+	ClientCertificates bool
+
+	// QualifiedByDatabase is true when the table is qualified by the database name instead of the schema
+	QualifiedByDatabase bool
+
+	sslModes map[string]string //accepted databaseSSLMode values mapped to the connector's value
+	template func(p parameters.DataSourceParameters) string
+}
+
+var databaseTypes = map[string]DatabaseType{
+	v1alpha1.DatabaseTypePostgres: {
+		Name:               v1alpha1.DatabaseTypePostgres,
+		ConnectorClass:     "io.debezium.connector.postgresql.PostgresConnector",
+		ReplicationSlots:   true,
+		Signals:            true,
+		ClientCertificates: true,
+		sslModes: map[string]string{
+			"disable":     "disable",
+			"allow":       "allow",
+			"prefer":      "prefer",
+			"require":     "require",
+			"verify-ca":   "verify-ca",
+			"verify-full": "verify-full",
+		},
+		template: func(p parameters.DataSourceParameters) string {
+			return p.DebeziumConnectorTemplate.String()
+		},
+	},
+	v1alpha1.DatabaseTypeMySQL: {
+		Name:                v1alpha1.DatabaseTypeMySQL,
+		ConnectorClass:      "io.debezium.connector.mysql.MySqlConnector",
+		SchemaHistory:       true,
+		QualifiedByDatabase: true,
+		sslModes: map[string]string{
+			"disable":         "disabled",
+			"disabled":        "disabled",
+			"prefer":          "preferred",
+			"preferred":       "preferred",
+			"require":         "required",
+			"required":        "required",
+			"verify-ca":       "verify_ca",
+			"verify_ca":       "verify_ca",
+			"verify-full":     "verify_identity",
+			"verify_identity": "verify_identity",
+		},
+		template: func(p parameters.DataSourceParameters) string {
+			return p.DebeziumMySQLTemplate.String()
+		},
+	},
+	v1alpha1.DatabaseTypeSQLServer: {
+		Name:           v1alpha1.DatabaseTypeSQLServer,
+		ConnectorClass: "io.debezium.connector.sqlserver.SqlServerConnector",
+		SchemaHistory:  true,
+		sslModes: map[string]string{
+			"disable":     "disable",
+			"require":     "require",
+			"verify-full": "verify-full",
+		},
+		template: func(p parameters.DataSourceParameters) string {
+			return p.DebeziumSQLServerTemplate.String()
+		},
+	},
+}
+
+// databaseTypeOf returns the datasource's DatabaseType, postgres when the databaseType is empty
+func databaseTypeOf(p parameters.DataSourceParameters) (DatabaseType, error) {
+	name := p.DatabaseType.String()
+	if name == "" {
+		name = v1alpha1.DatabaseTypePostgres
+	}
+
+	databaseType, ok := databaseTypes[name]
+	if !ok {
+		return DatabaseType{Name: name}, errors.Wrap(fmt.Errorf("unsupported databaseType %s", name), 0)
+	}
+	return databaseType, nil
+}
+
+// ConnectorTemplate is the Debezium connector template of the databaseType
+func (t DatabaseType) ConnectorTemplate(p parameters.DataSourceParameters) string {
+	if t.template == nil {
+		return ""
+	}
+	return t.template(p)
+}
+
+// ConnectorTemplateParameters are the datasource's parameters with the databaseSSLMode of the connector
+func (t DatabaseType) ConnectorTemplateParameters(p parameters.DataSourceParameters) map[string]interface{} {
+	m := config.ParametersToMap(p)
+	if sslMode, ok := t.sslModes[p.DatabaseSSLMode.String()]; ok {
+		m["DatabaseSSLMode"] = sslMode
+	}
+	return m
+}
+
+// Validate returns a problem when the parameters can't be used with the databaseType
+func (t DatabaseType) Validate(p parameters.DataSourceParameters) (problem error) {
+	sslMode := p.DatabaseSSLMode.String()
+	if _, ok := t.sslModes[sslMode]; !ok {
+		var sslModes []string
+		for mode := range t.sslModes {
+			sslModes = append(sslModes, mode)
+		}
+		sort.Strings(sslModes)
+		return fmt.Errorf("databaseSSLMode %s is not supported for databaseType %s, use one of %s",
+			sslMode, t.Name, strings.Join(sslModes, ", "))
+	}
+
+	if !t.ClientCertificates && (p.DatabaseSSLCert.String() != "" || p.DatabaseSSLKey.String() != "") {
+		return fmt.Errorf("databaseSSLCert and databaseSSLKey are not supported for databaseType %s", t.Name)
+	}
+
+	//the postgres connector's table.whitelist is a list of patterns, so the table is not checked
+	if t.Name == v1alpha1.DatabaseTypePostgres {
+		return
+	}
+
+	if p.DatabaseName.String() == "" {
+		return fmt.Errorf("databaseName is required for databaseType %s", t.Name)
+	}
+
+	tables := [][2]string{{"databaseTable", p.DatabaseTable.String()}}
+	if p.DatabaseSignalTable.String() != "" {
+		tables = append(tables, [2]string{"databaseSignalTable", p.DatabaseSignalTable.String()})
+	}
+	for _, keyAndTable := range tables {
+		key, table := keyAndTable[0], keyAndTable[1]
+		parts := strings.Split(table, ".")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			qualifier := "<schema>"
+			if t.QualifiedByDatabase {
+				qualifier = "<database>"
+			}
+			return fmt.Errorf("%s %s of databaseType %s has to be %s.<table>", key, table, t.Name, qualifier)
+		}
+		if t.QualifiedByDatabase && parts[0] != p.DatabaseName.String() {
+			return fmt.Errorf("%s %s of databaseType %s has to be in the database %s",
+				key, table, t.Name, p.DatabaseName.String())
+		}
+	}
+
+	return
+}
+
+// DatabaseType is the pipeline's DatabaseType. The problem is set when the databaseType or its parameters are invalid.
+func (i *XJoinDataSourcePipelineIteration) DatabaseType() (databaseType DatabaseType, problem error) {
+	databaseType, err := databaseTypeOf(i.Parameters)
+	if err != nil {
+		return databaseType, err
+	}
+	return databaseType, databaseType.Validate(i.Parameters)
+}
@@ -2,10 +2,14 @@ package datasource
 
 import (
 	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	"github.com/redhatinsights/xjoin-operator/controllers/components"
 	"github.com/redhatinsights/xjoin-operator/controllers/kafka"
 	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"time"
 )
 
 type ReconcileMethods struct {
@@ -69,13 +73,40 @@ func (d *ReconcileMethods) Refreshing() (err error) {
 }
 
 func (d *ReconcileMethods) RefreshComplete() (err error) {
-	err = d.iteration.DeleteDataSourcePipeline(d.iteration.GetInstance().Name, d.iteration.GetInstance().Status.ActiveVersion)
+	instance := d.iteration.GetInstance()
+	if instance.Status.ActiveVersion == "" {
+		return
+	}
+
+	//keep the active version as the previous version to allow a fast rollback,
+	//an existing previous version is then removed like any other orphaned version
+	retention := d.iteration.Parameters.PreviousVersionRetention.Int()
+	if retention > 0 {
+		expiration := metav1.NewTime(time.Now().Add(time.Duration(retention) * time.Second))
+		instance.SetPreviousVersion(instance.Status.ActiveVersion, &expiration)
+		return
+	}
+
+	err = d.iteration.DeleteDataSourcePipeline(instance.Name, instance.Status.ActiveVersion)
 	if err != nil {
 		return errors.Wrap(err, 0)
 	}
 	return
 }
 
+func (d *ReconcileMethods) AwaitingApproval() (summary v1alpha1.RefreshSummary, err error) {
+	err = d.iteration.ReconcilePipelines()
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	summary, err = d.iteration.RefreshSummary()
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+	return
+}
+
 func (d *ReconcileMethods) Scrub() (errs []error) {
 	var validVersions []string
 	if d.iteration.GetInstance().Status.ActiveVersion != "" {
@@ -84,6 +115,9 @@ func (d *ReconcileMethods) Scrub() (errs []error) {
 	if d.iteration.GetInstance().Status.RefreshingVersion != "" {
 		validVersions = append(validVersions, d.iteration.GetInstance().Status.RefreshingVersion)
 	}
+	if d.iteration.GetInstance().Status.PreviousVersion != "" {
+		validVersions = append(validVersions, d.iteration.GetInstance().Status.PreviousVersion)
+	}
 
 	kafkaClient := kafka.GenericKafka{
 		Context:          d.iteration.Context,
@@ -131,5 +165,22 @@ func (d *ReconcileMethods) Scrub() (errs []error) {
 	custodian.AddComponent(&components.DebeziumConnector{
 		KafkaClient: kafkaClient,
 	})
-	return custodian.Scrub()
+	errs = custodian.Scrub()
+
+	//replication slots are named after the XJoinDataSourcePipeline. An unreachable database is logged instead of
+	//returned so it does not block reconciling the datasource.
+	databaseType, err := databaseTypeOf(d.iteration.Parameters)
+	if err != nil || !databaseType.ReplicationSlots || d.iteration.GetInstance().KafkaTopicSource() != nil {
+		return errs
+	}
+	slotCustodian := components.NewCustodian(
+		common.DataSourcePipelineGVK.Kind+"."+d.iteration.GetInstance().Name, validVersions)
+	slotCustodian.AddComponent(&components.ReplicationSlot{
+		DBParams: d.iteration.DBParams(),
+	})
+	for _, err := range slotCustodian.Scrub() {
+		d.iteration.Log.Error(err, "Unable to remove orphaned replication slots")
+	}
+
+	return errs
 }
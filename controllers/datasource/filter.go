@@ -0,0 +1,96 @@
+package datasource
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-errors/errors"
+	. "github.com/redhatinsights/xjoin-go-lib/pkg/avro"
+	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+)
+
+// ColumnIncludeList is the table's columns that are fields of the datasource's Avro schema, qualified by the table.
+// It is empty when the schema has no fields, so every column is captured.
+func ColumnIncludeList(avroSchema string, table string) (columns []string, err error) {
+	var schema Schema
+	err = json.Unmarshal([]byte(avroSchema), &schema)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	for _, field := range schema.Fields {
+		//fields like __deleted are added by Debezium's transforms, they are not columns
+		if strings.HasPrefix(field.Name, "__") {
+			continue
+		}
+		columns = append(columns, table+"."+field.Name)
+	}
+	return
+}
+
+// DecimalHandlingMode is double when a field of the Avro schema is a decimal emitted as an Avro double, like the
+// numeric columns of an introspected schema. It is empty otherwise, so decimals keep Debezium's default bytes encoding.
+func DecimalHandlingMode(avroSchema string) (mode string, err error) {
+	var schema Schema
+	err = json.Unmarshal([]byte(avroSchema), &schema)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	for _, field := range schema.Fields {
+		for _, fieldType := range field.Type {
+			if fieldType.XJoinType == "decimal" && fieldType.Type == "double" {
+				return "double", nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// FilterTemplateParameters adds the pipeline's column and row filters and the decimal handling mode of its Avro schema
+// to the connector's template parameters. The values are escaped to be used inside the template's JSON strings.
+func (i *XJoinDataSourcePipelineIteration) FilterTemplateParameters(templateParameters map[string]interface{}) error {
+	table := i.Parameters.DatabaseTable.String()
+	columns, err := ColumnIncludeList(i.Parameters.AvroSchema.String(), table)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	if rowFilter := i.GetInstance().Spec.RowFilter; rowFilter != nil && rowFilter.Condition != "" {
+		if len(rowFilter.Columns) == 0 {
+			//the columns read by the filter transform are unknown
+			columns = nil
+		}
+		for _, column := range rowFilter.Columns {
+			if len(columns) > 0 && !utils.ContainsString(columns, table+"."+column) {
+				columns = append(columns, table+"."+column)
+			}
+		}
+	}
+	templateParameters["ColumnIncludeList"] = jsonStringContent(strings.Join(columns, ","))
+
+	templateParameters["DecimalHandlingMode"], err = DecimalHandlingMode(i.Parameters.AvroSchema.String())
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	templateParameters["SnapshotSelectStatement"] = ""
+	templateParameters["RowFilterCondition"] = ""
+	if rowFilter := i.GetInstance().Spec.RowFilter; rowFilter != nil {
+		templateParameters["SnapshotSelectStatement"] = jsonStringContent(
+			"SELECT * FROM " + table + " WHERE " + rowFilter.Where)
+		if rowFilter.Condition != "" {
+			//the deletes rewritten by the unwrap transform are always passed on. A change that doesn't match the
+			//condition is rewritten to a delete, so a row that stops matching, e.g. a soft-deleted one, is removed.
+			templateParameters["RowFilterCondition"] = jsonStringContent(
+				"value.__deleted == 'true' || (" + rowFilter.Condition + ") || " +
+					"value.put('__deleted', 'true') != null")
+		}
+	}
+	return nil
+}
+
+// jsonStringContent is s escaped as the content of a JSON string, without the quotes
+func jsonStringContent(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted[1 : len(quoted)-1])
+}
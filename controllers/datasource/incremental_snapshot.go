@@ -0,0 +1,254 @@
+package datasource
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/database"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+// awaitingSnapshotValidation is true after an incremental snapshot completed until the active version is validated again
+func awaitingSnapshotValidation(instance *v1alpha1.XJoinDataSource, activePipeline *v1alpha1.XJoinDataSourcePipeline) bool {
+	snapshot := instance.Status.IncrementalSnapshot
+	return snapshot != nil && snapshot.Version == instance.Status.ActiveVersion &&
+		snapshot.Phase == v1alpha1.IncrementalSnapshotCompleted &&
+		activePipeline.Status.ValidationResponse.Result == ""
+}
+
+// UpdateIncrementalSnapshot updates the progress of the active version's incremental snapshot. The returned
+// snapshotPending is true while the snapshot is in progress or another datasource's snapshot uses the same signal
+// table, a refresh requested via the annotation then stays pending until the snapshot ends.
+func (i *XJoinDataSourceIteration) UpdateIncrementalSnapshot(
+	activePipeline *v1alpha1.XJoinDataSourcePipeline) (snapshotPending bool, err error) {
+
+	instance := i.GetInstance()
+	if !instance.RefreshWithIncrementalSnapshot() || instance.GetDeletionTimestamp() != nil || activePipeline == nil {
+		return false, nil
+	}
+
+	if instance.IncrementalSnapshotInProgress() {
+		err = i.updateIncrementalSnapshotProgress(activePipeline)
+		if err != nil {
+			return false, errors.Wrap(err, 0)
+		}
+		if instance.IncrementalSnapshotInProgress() {
+			return true, nil
+		}
+	}
+
+	signalTableSnapshot, err := i.signalTableSnapshot()
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	return signalTableSnapshot != "", nil
+}
+
+// signalTableSnapshot is the name of another datasource whose incremental snapshot uses the same signal table
+func (i *XJoinDataSourceIteration) signalTableSnapshot() (string, error) {
+	instance := i.GetInstance()
+	if instance.Spec.DatabaseSignalTable == "" {
+		return "", nil
+	}
+
+	name, err := k8sUtils.SignalTableSnapshot(i.Client, i.Context, instance.GetNamespace(), instance.GetName(),
+		instance.Spec.DatabaseHostname, instance.Spec.DatabaseName, instance.Spec.DatabaseSignalTable)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	return name, nil
+}
+
+// ReconcileIncrementalSnapshot refreshes the active version through a Debezium incremental snapshot instead of a new
+// XJoinDataSourcePipeline when the refresh strategy is IncrementalSnapshot. The returned refresh reason is empty when
+// the refresh is handled by a snapshot. Spec changes and datasources without a signal table are still recreated, as is
+// an active version that is invalid after a snapshot.
+func (i *XJoinDataSourceIteration) ReconcileIncrementalSnapshot(
+	refreshReason string, activePipeline *v1alpha1.XJoinDataSourcePipeline) (string, error) {
+
+	instance := i.GetInstance()
+	if !instance.RefreshWithIncrementalSnapshot() || instance.GetDeletionTimestamp() != nil || activePipeline == nil {
+		return refreshReason, nil
+	}
+
+	healthy := common.ComponentsAreHealthy(activePipeline.Status.Conditions)
+	deferred := false
+
+	if instance.IncrementalSnapshotInProgress() {
+		if refreshReason != "" {
+			i.Log.Info("Ignoring refresh while an incremental snapshot is in progress", "reason", refreshReason)
+			refreshReason = ""
+		}
+	} else if refreshReason != "" || i.activeVersionNeedsSnapshot(activePipeline, healthy) {
+		reason := refreshReason
+		if reason == "" {
+			reason = "active version " + instance.Status.ActiveVersion + " failed validation"
+		}
+
+		started, err := i.startIncrementalSnapshot(reason)
+		if err != nil {
+			return refreshReason, errors.Wrap(err, 0)
+		}
+		if started {
+			refreshReason = ""
+			deferred = !instance.IncrementalSnapshotInProgress()
+		}
+	}
+
+	//validation results are expected to mismatch while the table is re-read or waits for the signal table
+	if deferred || instance.IncrementalSnapshotInProgress() || awaitingSnapshotValidation(instance, activePipeline) {
+		instance.Status.ActiveVersionIsValid = healthy
+	}
+
+	return refreshReason, nil
+}
+
+// activeVersionNeedsSnapshot is true when the healthy active version failed validation and has not been snapshotted yet
+func (i *XJoinDataSourceIteration) activeVersionNeedsSnapshot(
+	activePipeline *v1alpha1.XJoinDataSourcePipeline, healthy bool) bool {
+
+	instance := i.GetInstance()
+	snapshot := instance.Status.IncrementalSnapshot
+	return healthy && !instance.Status.ActiveVersionIsValid && !instance.Status.ActiveVersionValidationSkipped &&
+		activePipeline.Status.ValidationResponse.Result != "" &&
+		(snapshot == nil || snapshot.Version != instance.Status.ActiveVersion)
+}
+
+// startIncrementalSnapshot signals the active version's Debezium connector to re-read the whole table. The snapshot is
+// deferred while another datasource's snapshot uses the signal table.
+// Returns false when the refresh has to recreate the pipeline instead.
+func (i *XJoinDataSourceIteration) startIncrementalSnapshot(reason string) (started bool, err error) {
+	instance := i.GetInstance()
+	signalTable := i.Parameters.DatabaseSignalTable.String()
+
+	specHash, err := k8sUtils.SpecHash(instance.GetSpec())
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+
+	if instance.Status.ActiveVersion == "" || instance.Status.RefreshingVersion != "" ||
+		instance.Status.SpecHash != specHash {
+		return false, nil
+	} else if instance.KafkaTopicSource() != nil {
+		i.Log.Info("Recreating the pipeline because the datasource is read from a Kafka topic", "reason", reason)
+		return false, nil
+	} else if signalTable == "" {
+		i.Log.Info("Recreating the pipeline because the datasource does not have a databaseSignalTable",
+			"reason", reason)
+		return false, nil
+	}
+
+	if databaseType, err := databaseTypeOf(i.Parameters); err != nil || !databaseType.Signals {
+		i.Log.Info("Recreating the pipeline because incremental snapshots are not supported for the databaseType",
+			"reason", reason, "databaseType", databaseType.Name)
+		return false, nil
+	}
+
+	signalTableSnapshot, err := i.signalTableSnapshot()
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	} else if signalTableSnapshot != "" {
+		i.Log.Info("Deferring the incremental snapshot while another datasource's snapshot uses the signal table",
+			"reason", reason, "dataSource", signalTableSnapshot)
+		return true, nil
+	}
+
+	snapshot := &v1alpha1.IncrementalSnapshotStatus{
+		Version:   instance.Status.ActiveVersion,
+		Reason:    reason,
+		Phase:     v1alpha1.IncrementalSnapshotRequested,
+		StartTime: metav1.Now(),
+	}
+
+	//the snapshot only re-reads the rows that are sent to Kafka
+	var rowFilter string
+	if instance.Spec.RowFilter != nil {
+		rowFilter = instance.Spec.RowFilter.Where
+	}
+
+	err = withDatabase(i.Parameters, i.Log, func(db *database.Database) (err error) {
+		err = db.CreateSignalTable(signalTable)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		rows, err := db.CountRows(i.Parameters.DatabaseTable.String(), rowFilter)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		chunkSize := i.Parameters.DebeziumSnapshotChunkSize.Int()
+		snapshot.ChunksEstimated = (rows + chunkSize - 1) / chunkSize
+
+		snapshot.SignalId, err = db.SendIncrementalSnapshotSignal(
+			signalTable, i.Parameters.DatabaseTable.String(), rowFilter)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		return
+	})
+	if err != nil {
+		i.Log.Error(err, "Unable to start an incremental snapshot, recreating the pipeline instead", "reason", reason)
+		return false, nil
+	}
+
+	i.Log.Info("Started an incremental snapshot", "reason", reason, "version", snapshot.Version,
+		"signal", snapshot.SignalId)
+	instance.Status.IncrementalSnapshot = snapshot
+	instance.Status.LastRefreshReason = reason
+	return true, nil
+}
+
+// updateIncrementalSnapshotProgress counts the chunks read since the snapshot started.
+// The snapshot fails when it does not complete within the incremental.snapshot.timeout.
+func (i *XJoinDataSourceIteration) updateIncrementalSnapshotProgress(
+	activePipeline *v1alpha1.XJoinDataSourcePipeline) (err error) {
+
+	snapshot := i.GetInstance().Status.IncrementalSnapshot
+	timeout := time.Duration(i.Parameters.IncrementalSnapshotTimeout.Int()) * time.Second
+	if time.Since(snapshot.StartTime.Time) > timeout {
+		now := metav1.Now()
+		snapshot.Phase = v1alpha1.IncrementalSnapshotFailed
+		snapshot.CompletionTime = &now
+		snapshot.Message = fmt.Sprintf("the snapshot did not complete within %s", timeout)
+		i.Log.Info("Incremental snapshot timed out", "version", snapshot.Version, "signal", snapshot.SignalId)
+		return
+	}
+
+	var chunks int
+	err = withDatabase(i.Parameters, i.Log, func(db *database.Database) (err error) {
+		chunks, err = db.CountSnapshotChunks(i.Parameters.DatabaseSignalTable.String(), snapshot.SignalId)
+		return
+	})
+	if err != nil {
+		//progress is retried on the next reconcile until the snapshot times out
+		i.Log.Error(err, "Unable to read the incremental snapshot progress", "signal", snapshot.SignalId)
+		snapshot.Message = err.Error()
+		return nil
+	}
+
+	snapshot.Message = ""
+	snapshot.ChunksCompleted = chunks
+	if snapshot.ChunksCompleted > 0 {
+		snapshot.Phase = v1alpha1.IncrementalSnapshotRunning
+	}
+
+	if snapshot.ChunksCompleted >= snapshot.ChunksEstimated {
+		//the previous validation result is outdated, the active version is valid until it is validated again
+		activePipeline.Status.ValidationResponse.Result = ""
+		err = i.Client.Status().Update(i.Context, activePipeline)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		now := metav1.Now()
+		snapshot.Phase = v1alpha1.IncrementalSnapshotCompleted
+		snapshot.CompletionTime = &now
+		i.Log.Info("Incremental snapshot completed", "version", snapshot.Version, "signal", snapshot.SignalId,
+			"chunks", snapshot.ChunksCompleted)
+	}
+
+	return
+}
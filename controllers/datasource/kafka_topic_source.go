@@ -0,0 +1,34 @@
+package datasource
+
+import (
+	"fmt"
+
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
+	"github.com/riferrei/srclient"
+)
+
+// KafkaTopicSourceSchema is the Avro schema of the pipeline's existing Kafka topic. The spec's avroSchema is used
+// when it is set, otherwise the latest schema of the topic's value subject is read from the registry.
+func (i *XJoinDataSourcePipelineIteration) KafkaTopicSourceSchema(
+	registry *schemaregistry.ConfluentClient) (schema string, problem error, err error) {
+
+	schema = i.Parameters.AvroSchema.String()
+	if schema != "" && schema != "{}" {
+		return schema, nil, nil
+	}
+
+	subject := i.GetInstance().KafkaTopicSource().ValueSubject()
+	registry.Client.ResetCache()
+	srschema, err := registry.Client.GetLatestSchema(subject)
+	if err != nil {
+		srerr, isSrerr := err.(srclient.Error)
+		if isSrerr && srerr.Code == 40401 {
+			// Error code 40401 – Subject not found
+			return "", fmt.Errorf("schema for subject %s of the kafkaTopic source not found in registry", subject), nil
+		}
+		return "", nil, errors.Wrap(err, 0)
+	}
+
+	return srschema.Schema(), nil, nil
+}
@@ -0,0 +1,83 @@
+package datasource
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/database"
+	"github.com/redhatinsights/xjoin-operator/controllers/metrics"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const replicationSlotDroppedReason = "SlotDropped"
+
+// ReconcileReplicationSlot exports the lag of the Debezium connector's replication slot and sets the
+// ReplicationLagHigh condition. When the slot retains more than replication.lag.limit bytes of WAL it is dropped to
+// protect the database's disk. The returned problem then keeps the pipeline unhealthy so the datasource is refreshed.
+func (i *XJoinDataSourcePipelineIteration) ReconcileReplicationSlot(slot string) (problem error) {
+	instance := i.GetInstance()
+	condition := metav1.Condition{
+		Type:               v1alpha1.ReplicationLagHighConditionType,
+		ObservedGeneration: instance.Generation,
+	}
+
+	existing := meta.FindStatusCondition(instance.Status.Conditions, v1alpha1.ReplicationLagHighConditionType)
+	if existing != nil && existing.Reason == replicationSlotDroppedReason {
+		return errors.New(existing.Message)
+	}
+
+	var lag *database.ReplicationSlotLag
+	err := withDatabase(i.Parameters, i.Log, func(db *database.Database) (err error) {
+		lag, err = db.GetReplicationSlotLag(slot)
+		if err != nil || lag == nil {
+			return
+		}
+
+		limit := i.Parameters.ReplicationLagLimit.Int()
+		if limit > 0 && lag.RetainedWALBytes > int64(limit) {
+			err = db.TerminateAndRemoveReplicationSlot(slot)
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+			problem = fmt.Errorf("replication slot %s was dropped because it retained %d bytes of WAL, more than "+
+				"the replication.lag.limit of %d bytes", slot, lag.RetainedWALBytes, limit)
+		}
+		return
+	})
+
+	switch {
+	case err != nil:
+		i.Log.Error(err, "Unable to check the replication slot", "slot", slot)
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "QueryFailed"
+		condition.Message = err.Error()
+	case lag == nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "SlotNotFound"
+		condition.Message = "Replication slot " + slot + " does not exist"
+	case problem != nil:
+		i.Log.Info("Dropped the replication slot", "slot", slot, "retainedWALBytes", lag.RetainedWALBytes)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = replicationSlotDroppedReason
+		condition.Message = problem.Error()
+	default:
+		threshold := i.Parameters.ReplicationLagThreshold.Int()
+		condition.Message = fmt.Sprintf("Replication slot %s retains %d bytes of WAL, %d bytes are not confirmed",
+			slot, lag.RetainedWALBytes, lag.ConfirmedFlushLagBytes)
+		if lag.RetainedWALBytes > int64(threshold) {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "ThresholdExceeded"
+		} else {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "WithinThreshold"
+		}
+	}
+
+	if lag != nil {
+		metrics.ReplicationSlotLag(
+			instance.Spec.Name, instance.Spec.Version, lag.RetainedWALBytes, lag.ConfirmedFlushLagBytes)
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, condition)
+	return
+}
@@ -6,7 +6,9 @@ import (
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -16,6 +18,42 @@ type XJoinDataSourceIteration struct {
 }
 
 func (i *XJoinDataSourceIteration) CreateDataSourcePipeline(name string, version string) (err error) {
+	avroSchema := i.Parameters.AvroSchema.String()
+	if i.GetInstance().Spec.AvroSchemaFrom == v1alpha1.AvroSchemaFromIntrospect {
+		avroSchema, err = i.IntrospectAvroSchema()
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		i.GetInstance().Status.IntrospectedAvroSchema = avroSchema
+	}
+
+	spec := map[string]interface{}{
+		"name":                name,
+		"version":             version,
+		"avroSchema":          avroSchema,
+		"databaseHostname":    i.GetInstance().Spec.DatabaseHostname,
+		"databasePort":        i.GetInstance().Spec.DatabasePort,
+		"databaseName":        i.GetInstance().Spec.DatabaseName,
+		"databaseUsername":    i.GetInstance().Spec.DatabaseUsername,
+		"databasePassword":    i.GetInstance().Spec.DatabasePassword,
+		"databaseTable":       i.GetInstance().Spec.DatabaseTable,
+		"databaseSignalTable": i.GetInstance().Spec.DatabaseSignalTable,
+		"databaseSSLMode":     i.GetInstance().Spec.DatabaseSSLMode,
+		"databaseSSLRootCert": i.GetInstance().Spec.DatabaseSSLRootCert,
+		"databaseSSLCert":     i.GetInstance().Spec.DatabaseSSLCert,
+		"databaseSSLKey":      i.GetInstance().Spec.DatabaseSSLKey,
+		"pause":               i.Parameters.Pause.Bool(),
+	}
+	if i.GetInstance().Spec.DatabaseType != "" {
+		spec["databaseType"] = i.GetInstance().Spec.DatabaseType
+	}
+	if i.GetInstance().Spec.RowFilter != nil {
+		spec["rowFilter"] = i.GetInstance().Spec.RowFilter
+	}
+	if i.GetInstance().Spec.Source != nil {
+		spec["source"] = i.GetInstance().Spec.Source
+	}
+
 	dataSourcePipeline := unstructured.Unstructured{}
 	dataSourcePipeline.Object = map[string]interface{}{
 		"metadata": map[string]interface{}{
@@ -25,18 +63,7 @@ func (i *XJoinDataSourceIteration) CreateDataSourcePipeline(name string, version
 				common.COMPONENT_NAME_LABEL: name,
 			},
 		},
-		"spec": map[string]interface{}{
-			"name":             name,
-			"version":          version,
-			"avroSchema":       i.Parameters.AvroSchema.String(),
-			"databaseHostname": i.GetInstance().Spec.DatabaseHostname,
-			"databasePort":     i.GetInstance().Spec.DatabasePort,
-			"databaseName":     i.GetInstance().Spec.DatabaseName,
-			"databaseUsername": i.GetInstance().Spec.DatabaseUsername,
-			"databasePassword": i.GetInstance().Spec.DatabasePassword,
-			"databaseTable":    i.GetInstance().Spec.DatabaseTable,
-			"pause":            i.Parameters.Pause.Bool(),
-		},
+		"spec": spec,
 	}
 	dataSourcePipeline.SetGroupVersionKind(common.DataSourcePipelineGVK)
 	err = i.CreateChildResource(dataSourcePipeline, common.DataSourceGVK)
@@ -63,6 +90,37 @@ func (i *XJoinDataSourceIteration) ReconcilePipelines() (err error) {
 	return
 }
 
+// RefreshSummary compares the active and refreshing XJoinDataSourcePipelines
+func (i *XJoinDataSourceIteration) RefreshSummary() (summary v1alpha1.RefreshSummary, err error) {
+	instance := i.GetInstance()
+	activePipeline, err := k8sUtils.FetchXJoinDataSourcePipeline(i.Client, types.NamespacedName{
+		Name:      instance.Name + "." + instance.Status.ActiveVersion,
+		Namespace: instance.Namespace,
+	}, i.Context)
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	refreshingPipeline, err := k8sUtils.FetchXJoinDataSourcePipeline(i.Client, types.NamespacedName{
+		Name:      instance.Name + "." + instance.Status.RefreshingVersion,
+		Namespace: instance.Namespace,
+	}, i.Context)
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	summary.ActiveVersion = instance.Status.ActiveVersion
+	summary.RefreshingVersion = instance.Status.RefreshingVersion
+	summary.ActiveValidation = common.ValidationSummary(activePipeline.Status.ValidationResponse)
+	summary.RefreshingValidation = common.ValidationSummary(refreshingPipeline.Status.ValidationResponse)
+	summary.SchemaChanges, err = common.AvroSchemaChanges(activePipeline.Spec.AvroSchema, refreshingPipeline.Spec.AvroSchema)
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	return
+}
+
 func (i *XJoinDataSourceIteration) Finalize() (err error) {
 	i.Log.Info("Starting finalizer")
 
@@ -0,0 +1,311 @@
+package datasource
+
+import (
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/components"
+	"github.com/redhatinsights/xjoin-operator/controllers/k8s"
+	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
+)
+
+const XJoinDataSourceValidatorFinalizer = "finalizer.xjoin.datasourcevalidator.cloud.redhat.com"
+
+const ValidatorPodRunning = "running"
+const ValidatorPodSuccess = "success"
+const ValidatorPodFailed = "failed"
+
+type XJoinDataSourceValidatorIteration struct {
+	common.Iteration
+	Parameters   parameters.DataSourceParameters
+	ClientSet    kubernetes.Interface
+	PodLogReader k8s.LogReader
+}
+
+func (i *XJoinDataSourceValidatorIteration) Finalize() (err error) {
+	i.Log.Info("Starting finalizer")
+	controllerutil.RemoveFinalizer(i.Instance, XJoinDataSourceValidatorFinalizer)
+
+	ctx, cancel := utils.DefaultContext()
+	defer cancel()
+	err = i.Client.Update(ctx, i.Instance)
+	if err != nil {
+		return
+	}
+
+	i.Log.Info("Successfully finalized")
+	return nil
+}
+
+// ReconcileValidationJob runs an xjoin-validation job that compares the rows in the datasource's table with the
+// records on the datasource's topic. The result is written to the owning XJoinDataSourcePipeline's status.
+func (i *XJoinDataSourceValidatorIteration) ReconcileValidationJob() (phase string, err error) {
+	//check if the validation job was already created
+	job, err := k8s.PendingValidationJob(i.Context, i.Client, i.Instance.GetNamespace(), i.validationJobLabels())
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	if job == nil {
+		dbConnectionEnvVars, certificates, err := i.buildDBConnectionEnvVars()
+		if err != nil {
+			return "", errors.Wrap(err, 0)
+		}
+		err = i.createValidationJob(dbConnectionEnvVars, certificates)
+		if err != nil {
+			return "", errors.Wrap(err, 0)
+		}
+
+		return ValidatorPodRunning, nil
+	}
+
+	if failed := k8s.JobCondition(job, batchv1.JobFailed); failed != nil {
+		i.Log.Info("Validation job failed", "job", job.Name, "reason", failed.Reason, "message", failed.Message)
+		err = k8s.RecordValidationJob(i.Context, i.Client, job)
+		if err != nil {
+			return "", errors.Wrap(err, 0)
+		}
+
+		return ValidatorPodFailed, nil
+	} else if job.Status.Succeeded == 0 {
+		return ValidatorPodRunning, nil
+	}
+
+	//parse the results of the xjoin-validation job
+	response, err := k8s.ParseValidationJobResponse(i.Context, i.Client, i.PodLogReader, job)
+	if err != nil {
+		i.Log.Error(err, "Unable to parse the validation result", "job", job.Name)
+		err = k8s.RecordValidationJob(i.Context, i.Client, job)
+		if err != nil {
+			return "", errors.Wrap(err, 0)
+		}
+
+		return ValidatorPodFailed, nil
+	}
+
+	//update xjoindatasourcepipeline resource based on xjoin-validation job's output
+	i.Log.Info(response.Message)
+
+	dataSourcePipelineNamespacedName := types.NamespacedName{
+		Name:      i.Instance.GetOwnerReferences()[0].Name,
+		Namespace: i.Instance.GetNamespace(),
+	}
+	dataSourcePipeline, err := k8sUtils.FetchXJoinDataSourcePipeline(i.Client, dataSourcePipelineNamespacedName, i.Context)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	dataSourcePipeline.Status.ValidationResponse = response
+	now := metav1.Now()
+	dataSourcePipeline.Status.LastValidationTime = &now
+
+	if err := i.Client.Status().Update(i.Context, dataSourcePipeline); err != nil {
+		if k8errors.IsConflict(err) {
+			i.Log.Error(err, "Status conflict")
+			return "", errors.Wrap(err, 0)
+		}
+
+		return "", errors.Wrap(err, 0)
+	}
+
+	err = k8s.RecordValidationJob(i.Context, i.Client, job)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	return ValidatorPodSuccess, nil
+}
+
+func (i *XJoinDataSourceValidatorIteration) GetInstance() *v1alpha1.XJoinDataSourceValidator {
+	return i.Instance.(*v1alpha1.XJoinDataSourceValidator)
+}
+
+// validationJobLabels select the validation jobs of the validator
+func (i *XJoinDataSourceValidatorIteration) validationJobLabels() client.MatchingLabels {
+	return client.MatchingLabels{
+		"xjoin.datasource":          i.Instance.GetName(),
+		common.COMPONENT_NAME_LABEL: "XJoinDataSourceValidator",
+	}
+}
+
+// ValidationPodName is the prefix of the names of the validation jobs, each job gets a generated suffix
+func (i *XJoinDataSourceValidatorIteration) ValidationPodName() string {
+	name := "xjoin-validation-" + i.Instance.GetName()
+	name = strings.ReplaceAll(name, ".", "-")
+	return name
+}
+
+// envVarPrefix matches the prefix used by the XJoinIndexValidator, i.e. the XJoinDataSource's name
+func (i *XJoinDataSourceValidatorIteration) envVarPrefix() string {
+	name := strings.TrimPrefix(i.GetInstance().Spec.Name, strings.ToLower(common.DataSourcePipelineGVK.Kind)+".")
+	return strings.Split(name, ".")[0]
+}
+
+func (i *XJoinDataSourceValidatorIteration) buildDBConnectionEnvVars() (
+	envVars []v1.EnvVar, certificates components.CertificateVolumes, err error) {
+	spec := i.GetInstance().Spec
+	envVarPrefix := i.envVarPrefix()
+
+	dbParams := []struct {
+		name  string
+		value *v1alpha1.StringOrSecretParameter
+	}{
+		{name: "_DB_HOSTNAME", value: spec.DatabaseHostname},
+		{name: "_DB_USERNAME", value: spec.DatabaseUsername},
+		{name: "_DB_PASSWORD", value: spec.DatabasePassword},
+		{name: "_DB_NAME", value: spec.DatabaseName},
+		{name: "_DB_PORT", value: spec.DatabasePort},
+		{name: "_DB_TABLE", value: spec.DatabaseTable},
+	}
+
+	for _, dbParam := range dbParams {
+		if dbParam.value == nil {
+			return envVars, certificates, errors.Wrap(
+				errors.New("missing database parameter for "+envVarPrefix+dbParam.name), 0)
+		}
+
+		envVar, err := dbParam.value.ConvertToEnvVar(envVarPrefix + dbParam.name)
+		if err != nil {
+			return envVars, certificates, errors.Wrap(err, 0)
+		}
+		envVars = append(envVars, envVar)
+	}
+
+	databaseType := spec.DatabaseType
+	if databaseType == "" {
+		databaseType = v1alpha1.DatabaseTypePostgres
+	}
+	envVars = append(envVars, v1.EnvVar{Name: envVarPrefix + "_DB_TYPE", Value: databaseType})
+
+	//TLS options are only passed to the validation pod when they are set on the datasource
+	if spec.DatabaseSSLMode != nil {
+		envVar, err := spec.DatabaseSSLMode.ConvertToEnvVar(envVarPrefix + "_DB_SSL_MODE")
+		if err != nil {
+			return envVars, certificates, errors.Wrap(err, 0)
+		}
+		envVars = append(envVars, envVar)
+	}
+
+	//the certificates are paths, a certificate stored in a secret is mounted into the validation pod
+	sslCertificates := []struct {
+		name  string
+		value *v1alpha1.StringOrSecretParameter
+	}{
+		{name: "_DB_SSL_ROOT_CERT", value: spec.DatabaseSSLRootCert},
+		{name: "_DB_SSL_CERT", value: spec.DatabaseSSLCert},
+		{name: "_DB_SSL_KEY", value: spec.DatabaseSSLKey},
+	}
+
+	for _, sslCertificate := range sslCertificates {
+		if sslCertificate.value == nil {
+			continue
+		}
+		envVars = append(envVars, certificates.EnvVar(envVarPrefix+sslCertificate.name, sslCertificate.value))
+	}
+
+	//the validation only compares the rows that are sent to Kafka
+	if spec.RowFilter != nil {
+		envVars = append(envVars, v1.EnvVar{Name: envVarPrefix + "_DB_ROW_FILTER", Value: spec.RowFilter.Where})
+	}
+
+	return
+}
+
+func (i *XJoinDataSourceValidatorIteration) createValidationJob(
+	dbConnectionEnvVars []v1.EnvVar, certificates components.CertificateVolumes) error {
+	workload, err := components.ResolveWorkload(
+		components.DefaultValidatorWorkload(), i.Parameters.ValidatorWorkload.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	schemaRegistryURL := i.Parameters.SchemaRegistryProtocol.String() + "://" +
+		i.Parameters.SchemaRegistryHost.String() + ":" + i.Parameters.SchemaRegistryPort.String()
+
+	var resources v1.ResourceRequirements
+	if workload.Resources != nil {
+		resources = *workload.Resources
+	}
+
+	labels := i.validationJobLabels()
+	controller := true
+	blockOwnerDeletion := true
+	deadline := int64(i.Parameters.ValidationJobDeadline.Int())
+	backoffLimit := int32(i.Parameters.ValidationJobBackoffLimit.Int())
+	ttl := int32(i.Parameters.ValidationJobTTL.Int())
+
+	//run separate xjoin-validation job
+	//the finished jobs are kept until their ttl passes, so each job has a new name
+	err = i.Client.Create(i.Context, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: i.ValidationPodName() + "-",
+			Namespace:    i.Instance.GetNamespace(),
+			Labels:       labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         common.DataSourceValidatorGVK.GroupVersion().String(),
+				Kind:               common.DataSourceValidatorGVK.Kind,
+				Name:               i.Instance.GetName(),
+				UID:                i.Instance.GetUID(),
+				Controller:         &controller,
+				BlockOwnerDeletion: &blockOwnerDeletion,
+			}},
+		},
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds:   &deadline,
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					RestartPolicy: "Never",
+					NodeSelector:  workload.NodeSelector,
+					Tolerations:   workload.Tolerations,
+					Volumes:       certificates.Volumes,
+					Containers: []v1.Container{{
+						Name:  i.ValidationPodName(),
+						Image: workload.ImageName(),
+						Env: append(append(dbConnectionEnvVars, []v1.EnvVar{{
+							Name:  "VALIDATION_TYPE",
+							Value: "datasource",
+						}, {
+							Name:  "DATASOURCE_NAME",
+							Value: i.envVarPrefix(),
+						}, {
+							Name:  "KAFKA_BOOTSTRAP_URL",
+							Value: i.Parameters.KafkaBootstrapURL.String(),
+						}, {
+							Name:  "KAFKA_TOPIC",
+							Value: i.GetInstance().Spec.TopicName,
+						}, {
+							Name:  "SCHEMA_REGISTRY_URL",
+							Value: schemaRegistryURL,
+						}, {
+							Name:  "FULL_AVRO_SCHEMA",
+							Value: i.GetInstance().Spec.AvroSchema,
+						}}...), workload.Env...),
+						ImagePullPolicy:          workload.ImagePullPolicy,
+						Resources:                resources,
+						TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
+						VolumeMounts:             certificates.VolumeMounts,
+					}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	return nil
+}
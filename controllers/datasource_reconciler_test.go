@@ -2,13 +2,16 @@ package controllers_test
 
 import (
 	"context"
+	"errors"
 	"github.com/redhatinsights/xjoin-operator/controllers"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	"os"
 	"time"
 
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/gomega"
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -19,10 +22,14 @@ import (
 )
 
 type DatasourceTestReconciler struct {
-	Namespace          string
-	Name               string
-	K8sClient          client.Client
-	AvroSchemaFileName string
+	Namespace           string
+	Name                string
+	K8sClient           client.Client
+	AvroSchemaFileName  string
+	Pause               bool
+	RequireApproval     bool
+	RefreshStrategy     string
+	DatabaseSignalTable string
 }
 
 func (d *DatasourceTestReconciler) ReconcileNew() v1alpha1.XJoinDataSource {
@@ -56,6 +63,7 @@ func (d *DatasourceTestReconciler) ReconcileNew() v1alpha1.XJoinDataSource {
 
 func (d *DatasourceTestReconciler) ReconcileValid() v1alpha1.XJoinDataSource {
 	d.registerValidMocks()
+	d.setRefreshingPipelineValidationResult("valid")
 	result := d.reconcile()
 	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
 
@@ -69,7 +77,7 @@ func (d *DatasourceTestReconciler) ReconcileValid() v1alpha1.XJoinDataSource {
 
 	Expect(createdDatasource.Status.ActiveVersion).ToNot(Equal(""))
 	Expect(createdDatasource.Status.ActiveVersionIsValid).To(Equal(true))
-	Expect(createdDatasource.Status.RefreshingVersion).ToNot(Equal(""))
+	Expect(createdDatasource.Status.RefreshingVersion).To(Equal(""))
 	Expect(createdDatasource.Status.RefreshingVersionIsValid).To(Equal(false))
 	Expect(createdDatasource.Status.SpecHash).ToNot(Equal(""))
 	Expect(createdDatasource.Finalizers).To(HaveLen(1))
@@ -82,6 +90,249 @@ func (d *DatasourceTestReconciler) ReconcileValid() v1alpha1.XJoinDataSource {
 	return *createdDatasource
 }
 
+func (d *DatasourceTestReconciler) ReconcileInvalid() v1alpha1.XJoinDataSource {
+	d.registerValidMocks()
+	d.setRefreshingPipelineValidationResult("invalid")
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	createdDatasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+
+	Eventually(func() bool {
+		err := d.K8sClient.Get(context.Background(), datasourceLookupKey, createdDatasource)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+	Expect(createdDatasource.Status.ActiveVersion).To(Equal(""))
+	Expect(createdDatasource.Status.ActiveVersionIsValid).To(Equal(false))
+	Expect(createdDatasource.Status.RefreshingVersion).ToNot(Equal(""))
+	Expect(createdDatasource.Status.RefreshingVersionIsValid).To(Equal(false))
+
+	return *createdDatasource
+}
+
+func (d *DatasourceTestReconciler) ReconcilePaused() v1alpha1.XJoinDataSource {
+	d.registerNewMocks()
+	d.createValidDataSource()
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 0}))
+
+	createdDatasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, createdDatasource)
+	checkError(err)
+
+	Expect(createdDatasource.Status.ActiveVersion).To(Equal(""))
+	Expect(createdDatasource.Status.RefreshingVersion).To(Equal(""))
+
+	return *createdDatasource
+}
+
+func (d *DatasourceTestReconciler) ReconcileActiveComponentsUnhealthy() v1alpha1.XJoinDataSource {
+	d.registerValidMocks()
+	d.setActivePipelineComponentDeviation("kafkatopic.test has a deviation")
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	createdDatasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+
+	Eventually(func() bool {
+		err := d.K8sClient.Get(context.Background(), datasourceLookupKey, createdDatasource)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+	Expect(createdDatasource.Status.ActiveVersion).ToNot(Equal(""))
+	Expect(createdDatasource.Status.ActiveVersionIsValid).To(Equal(false))
+	Expect(createdDatasource.Status.RefreshingVersion).ToNot(Equal(""))
+
+	return *createdDatasource
+}
+
+func (d *DatasourceTestReconciler) ReconcileAwaitingApproval() v1alpha1.XJoinDataSource {
+	d.registerValidMocks()
+	d.setRefreshingPipelineValidationResult("valid")
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	createdDatasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, createdDatasource)
+	checkError(err)
+
+	Expect(createdDatasource.Status.ActiveVersion).ToNot(Equal(""))
+	Expect(createdDatasource.Status.RefreshingVersion).ToNot(Equal(""))
+	Expect(createdDatasource.Status.RefreshingVersionIsValid).To(Equal(true))
+	Expect(createdDatasource.Status.Phase).To(Equal("AWAITING_APPROVAL"))
+
+	return *createdDatasource
+}
+
+func (d *DatasourceTestReconciler) ReconcileWithAnnotation(annotation string, value string) v1alpha1.XJoinDataSource {
+	datasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, datasource)
+	checkError(err)
+
+	datasource.SetAnnotations(map[string]string{annotation: value})
+	err = d.K8sClient.Update(context.Background(), datasource)
+	checkError(err)
+
+	d.registerValidMocks()
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	updatedDatasource := &v1alpha1.XJoinDataSource{}
+	err = d.K8sClient.Get(context.Background(), datasourceLookupKey, updatedDatasource)
+	checkError(err)
+	Expect(updatedDatasource.GetAnnotations()).ToNot(HaveKey(annotation))
+	Expect(updatedDatasource.Status.LastAction).ToNot(BeNil())
+	Expect(updatedDatasource.Status.LastAction.Annotation).To(Equal(annotation))
+	Expect(updatedDatasource.Status.LastAction.Value).To(Equal(value))
+
+	return *updatedDatasource
+}
+
+// ReconcileWithPendingAnnotation reconciles the datasource with an annotation that is kept for a later reconcile
+func (d *DatasourceTestReconciler) ReconcileWithPendingAnnotation(annotation string, value string) v1alpha1.XJoinDataSource {
+	datasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, datasource)
+	checkError(err)
+
+	datasource.SetAnnotations(map[string]string{annotation: value})
+	err = d.K8sClient.Update(context.Background(), datasource)
+	checkError(err)
+
+	d.registerValidMocks()
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	updatedDatasource := &v1alpha1.XJoinDataSource{}
+	err = d.K8sClient.Get(context.Background(), datasourceLookupKey, updatedDatasource)
+	checkError(err)
+	Expect(updatedDatasource.GetAnnotations()).To(HaveKeyWithValue(annotation, value))
+
+	return *updatedDatasource
+}
+
+// setActivePipelineComponentDeviation mimics the XJoinDataSourcePipeline reconciler finding a component deviation
+func (d *DatasourceTestReconciler) setActivePipelineComponentDeviation(problem string) {
+	datasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, datasource)
+	checkError(err)
+
+	pipeline := &v1alpha1.XJoinDataSourcePipeline{}
+	pipelineLookupKey := types.NamespacedName{
+		Name:      d.Name + "." + datasource.Status.ActiveVersion,
+		Namespace: d.Namespace,
+	}
+	err = d.K8sClient.Get(context.Background(), pipelineLookupKey, pipeline)
+	checkError(err)
+
+	common.SetComponentsHealthyCondition(&pipeline.Status.Conditions, []error{errors.New(problem)})
+	err = d.K8sClient.Status().Update(context.Background(), pipeline)
+	checkError(err)
+}
+
+// setRefreshingPipelineValidationResult mimics the XJoinDataSourceValidator writing its result to the pipeline
+func (d *DatasourceTestReconciler) setRefreshingPipelineValidationResult(result string) {
+	datasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, datasource)
+	checkError(err)
+
+	pipeline := &v1alpha1.XJoinDataSourcePipeline{}
+	pipelineLookupKey := types.NamespacedName{
+		Name:      d.Name + "." + datasource.Status.RefreshingVersion,
+		Namespace: d.Namespace,
+	}
+	Eventually(func() bool {
+		err := d.K8sClient.Get(context.Background(), pipelineLookupKey, pipeline)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+	pipeline.Status.ValidationResponse.Result = result
+	err = d.K8sClient.Status().Update(context.Background(), pipeline)
+	checkError(err)
+}
+
+// ReconcileActiveVersion reconciles the datasource after the active pipeline was validated with the given result
+func (d *DatasourceTestReconciler) ReconcileActiveVersion(validationResult string) v1alpha1.XJoinDataSource {
+	d.setActivePipelineValidationResult(validationResult)
+	d.registerValidMocks()
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	updatedDatasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, updatedDatasource)
+	checkError(err)
+	return *updatedDatasource
+}
+
+// SetIncrementalSnapshot mimics an incremental snapshot of the active version started at startTime
+func (d *DatasourceTestReconciler) SetIncrementalSnapshot(phase string, startTime time.Time) {
+	datasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, datasource)
+	checkError(err)
+
+	datasource.Status.IncrementalSnapshot = &v1alpha1.IncrementalSnapshotStatus{
+		Version:         datasource.Status.ActiveVersion,
+		SignalId:        "test-signal",
+		Reason:          "test",
+		Phase:           phase,
+		StartTime:       metav1.NewTime(startTime),
+		ChunksEstimated: 10,
+	}
+	err = d.K8sClient.Status().Update(context.Background(), datasource)
+	checkError(err)
+}
+
+// SetActivePipelineCondition mimics the XJoinDataSourcePipeline reconciler setting a condition on the active pipeline
+func (d *DatasourceTestReconciler) SetActivePipelineCondition(condition metav1.Condition) {
+	datasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, datasource)
+	checkError(err)
+
+	pipeline := &v1alpha1.XJoinDataSourcePipeline{}
+	pipelineLookupKey := types.NamespacedName{
+		Name:      d.Name + "." + datasource.Status.ActiveVersion,
+		Namespace: d.Namespace,
+	}
+	err = d.K8sClient.Get(context.Background(), pipelineLookupKey, pipeline)
+	checkError(err)
+
+	meta.SetStatusCondition(&pipeline.Status.Conditions, condition)
+	err = d.K8sClient.Status().Update(context.Background(), pipeline)
+	checkError(err)
+}
+
+// setActivePipelineValidationResult mimics the XJoinDataSourceValidator writing its result to the active pipeline
+func (d *DatasourceTestReconciler) setActivePipelineValidationResult(result string) {
+	datasource := &v1alpha1.XJoinDataSource{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, datasource)
+	checkError(err)
+
+	pipeline := &v1alpha1.XJoinDataSourcePipeline{}
+	pipelineLookupKey := types.NamespacedName{
+		Name:      d.Name + "." + datasource.Status.ActiveVersion,
+		Namespace: d.Namespace,
+	}
+	err = d.K8sClient.Get(context.Background(), pipelineLookupKey, pipeline)
+	checkError(err)
+
+	pipeline.Status.ValidationResponse.Result = result
+	pipeline.Status.LastValidationTime = validationTime()
+	err = d.K8sClient.Status().Update(context.Background(), pipeline)
+	checkError(err)
+}
+
 func (d *DatasourceTestReconciler) ReconcileDelete() {
 	d.registerDeleteMocks()
 	result := d.reconcile()
@@ -106,14 +357,16 @@ func (d *DatasourceTestReconciler) createValidDataSource() {
 	}
 
 	datasourceSpec := v1alpha1.XJoinDataSourceSpec{
-		AvroSchema:       datasourceAvroSchema,
-		DatabaseHostname: &v1alpha1.StringOrSecretParameter{Value: "dbHost"},
-		DatabasePort:     &v1alpha1.StringOrSecretParameter{Value: "8080"},
-		DatabaseUsername: &v1alpha1.StringOrSecretParameter{Value: "dbUsername"},
-		DatabasePassword: &v1alpha1.StringOrSecretParameter{Value: "dbPassword"},
-		DatabaseName:     &v1alpha1.StringOrSecretParameter{Value: "dbName"},
-		DatabaseTable:    &v1alpha1.StringOrSecretParameter{Value: "dbTable"},
-		Pause:            false,
+		AvroSchema:          datasourceAvroSchema,
+		DatabaseHostname:    &v1alpha1.StringOrSecretParameter{Value: "dbHost"},
+		DatabasePort:        &v1alpha1.StringOrSecretParameter{Value: "8080"},
+		DatabaseUsername:    &v1alpha1.StringOrSecretParameter{Value: "dbUsername"},
+		DatabasePassword:    &v1alpha1.StringOrSecretParameter{Value: "dbPassword"},
+		DatabaseName:        &v1alpha1.StringOrSecretParameter{Value: "dbName"},
+		DatabaseTable:       &v1alpha1.StringOrSecretParameter{Value: "dbTable"},
+		DatabaseSignalTable: d.DatabaseSignalTable,
+		Pause:               d.Pause,
+		RefreshPolicy:       &v1alpha1.RefreshPolicy{RequireApproval: d.RequireApproval, Strategy: d.RefreshStrategy},
 	}
 
 	datasource := &v1alpha1.XJoinDataSource{
@@ -138,7 +391,7 @@ func (d *DatasourceTestReconciler) createValidDataSource() {
 		err := d.K8sClient.Get(ctx, datasourceLookupKey, createdDatasource)
 		return err == nil
 	}, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
-	Expect(createdDatasource.Spec.Pause).Should(Equal(false))
+	Expect(createdDatasource.Spec.Pause).Should(Equal(d.Pause))
 	Expect(createdDatasource.Spec.AvroSchema).Should(Equal(datasourceAvroSchema))
 	Expect(createdDatasource.Spec.DatabaseHostname).Should(Equal(&v1alpha1.StringOrSecretParameter{Value: "dbHost"}))
 	Expect(createdDatasource.Spec.DatabasePort).Should(Equal(&v1alpha1.StringOrSecretParameter{Value: "8080"}))
@@ -19,10 +19,18 @@ import (
 )
 
 type DatasourcePipelineTestReconciler struct {
-	Namespace          string
-	Name               string
-	K8sClient          client.Client
-	AvroSchemaFileName string
+	Namespace           string
+	Name                string
+	K8sClient           client.Client
+	AvroSchemaFileName  string
+	DatabaseSignalTable string
+	DatabaseSSLMode     *v1alpha1.StringOrSecretParameter
+	DatabaseSSLCert     *v1alpha1.StringOrSecretParameter
+	DatabaseSSLKey      *v1alpha1.StringOrSecretParameter
+	DatabaseType        string
+	DatabaseTable       string
+	Source              *v1alpha1.DataSourceSource
+	RowFilter           *v1alpha1.RowFilter
 }
 
 func (d *DatasourcePipelineTestReconciler) newXJoinDataSourcePipelineReconciler() *controllers.XJoinDataSourcePipelineReconciler {
@@ -48,17 +56,29 @@ func (d *DatasourcePipelineTestReconciler) CreateValidDataSourcePipeline() {
 		datasourceAvroSchema = "{}"
 	}
 
+	databaseTable := d.DatabaseTable
+	if databaseTable == "" {
+		databaseTable = "dbTable"
+	}
+
 	datasourceSpec := v1alpha1.XJoinDataSourcePipelineSpec{
-		Name:             d.Name,
-		Version:          "1234",
-		AvroSchema:       datasourceAvroSchema,
-		DatabaseHostname: &v1alpha1.StringOrSecretParameter{Value: "dbHost"},
-		DatabasePort:     &v1alpha1.StringOrSecretParameter{Value: "8080"},
-		DatabaseUsername: &v1alpha1.StringOrSecretParameter{Value: "dbUsername"},
-		DatabasePassword: &v1alpha1.StringOrSecretParameter{Value: "dbPassword"},
-		DatabaseName:     &v1alpha1.StringOrSecretParameter{Value: "dbName"},
-		DatabaseTable:    &v1alpha1.StringOrSecretParameter{Value: "dbTable"},
-		Pause:            false,
+		Name:                d.Name,
+		Version:             "1234",
+		AvroSchema:          datasourceAvroSchema,
+		DatabaseHostname:    &v1alpha1.StringOrSecretParameter{Value: "dbHost"},
+		DatabasePort:        &v1alpha1.StringOrSecretParameter{Value: "8080"},
+		DatabaseUsername:    &v1alpha1.StringOrSecretParameter{Value: "dbUsername"},
+		DatabasePassword:    &v1alpha1.StringOrSecretParameter{Value: "dbPassword"},
+		DatabaseName:        &v1alpha1.StringOrSecretParameter{Value: "dbName"},
+		DatabaseTable:       &v1alpha1.StringOrSecretParameter{Value: databaseTable},
+		DatabaseSignalTable: d.DatabaseSignalTable,
+		DatabaseType:        d.DatabaseType,
+		DatabaseSSLMode:     d.DatabaseSSLMode,
+		DatabaseSSLCert:     d.DatabaseSSLCert,
+		DatabaseSSLKey:      d.DatabaseSSLKey,
+		Pause:               false,
+		Source:              d.Source,
+		RowFilter:           d.RowFilter,
 	}
 
 	datasource := &v1alpha1.XJoinDataSourcePipeline{
@@ -93,7 +113,7 @@ func (d *DatasourcePipelineTestReconciler) CreateValidDataSourcePipeline() {
 	Expect(createdDataSourcePipeline.Spec.DatabaseUsername).Should(Equal(&v1alpha1.StringOrSecretParameter{Value: "dbUsername"}))
 	Expect(createdDataSourcePipeline.Spec.DatabasePassword).Should(Equal(&v1alpha1.StringOrSecretParameter{Value: "dbPassword"}))
 	Expect(createdDataSourcePipeline.Spec.DatabaseName).Should(Equal(&v1alpha1.StringOrSecretParameter{Value: "dbName"}))
-	Expect(createdDataSourcePipeline.Spec.DatabaseTable).Should(Equal(&v1alpha1.StringOrSecretParameter{Value: "dbTable"}))
+	Expect(createdDataSourcePipeline.Spec.DatabaseTable).Should(Equal(&v1alpha1.StringOrSecretParameter{Value: databaseTable}))
 }
 
 func (d *DatasourcePipelineTestReconciler) ReconcileNew() v1alpha1.XJoinDataSourcePipeline {
@@ -112,6 +132,18 @@ func (d *DatasourcePipelineTestReconciler) ReconcileNew() v1alpha1.XJoinDataSour
 	return *createdDataSourcePipeline
 }
 
+func (d *DatasourcePipelineTestReconciler) ReconcileExisting() v1alpha1.XJoinDataSourcePipeline {
+	d.registerNewMocks()
+	result := d.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	dataSourcePipeline := &v1alpha1.XJoinDataSourcePipeline{}
+	datasourceLookupKey := types.NamespacedName{Name: d.Name, Namespace: d.Namespace}
+	err := d.K8sClient.Get(context.Background(), datasourceLookupKey, dataSourcePipeline)
+	checkError(err)
+	return *dataSourcePipeline
+}
+
 func (d *DatasourcePipelineTestReconciler) ReconcileDelete() {
 	d.registerDeleteMocks()
 	result := d.reconcile()
@@ -186,5 +218,13 @@ func (d *DatasourcePipelineTestReconciler) registerNewMocks() {
 	httpmock.RegisterResponder(
 		"GET",
 		"http://apicurio:1080/apis/ccompat/v6/subjects/xjoindatasourcepipeline."+d.Name+".1234-value/versions/latest",
-		httpmock.NewStringResponder(200, "{}"))
+		httpmock.NewStringResponder(200, `{"subject":"xjoindatasourcepipeline.`+d.Name+`.1234-value","version":1,"id":1,"schema":"{\"name\":\"Value\",\"namespace\":\"xjoindatasourcepipeline.`+d.Name+`\"}","schemaType":"AVRO","references":[]}`))
+
+	//schema of the kafkaTopic source's existing subject
+	if d.Source != nil && d.Source.KafkaTopic != nil {
+		httpmock.RegisterResponder(
+			"GET",
+			"http://apicurio:1080/apis/ccompat/v6/subjects/"+d.Source.KafkaTopic.ValueSubject()+"/versions/latest",
+			httpmock.NewStringResponder(200, `{"subject":"`+d.Source.KafkaTopic.ValueSubject()+`","version":3,"id":7,"schema":"{\"type\":\"record\",\"name\":\"Record\",\"namespace\":\"app\"}","schemaType":"AVRO","references":[]}`))
+	}
 }
@@ -230,3 +230,159 @@ func (es GenericElasticsearch) CreateIndex(
 	}
 	return nil
 }
+
+// GetIndexMappingProperties returns the mapping properties of an index as stored in Elasticsearch
+func (es GenericElasticsearch) GetIndexMappingProperties(indexName string) (properties map[string]interface{}, err error) {
+	req := esapi.IndicesGetMappingRequest{
+		Index: []string{indexName},
+	}
+
+	res, err := req.Do(es.Context, es.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	_, body, err := parseResponse(res)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	index, _ := body[indexName].(map[string]interface{})
+	mappings, _ := index["mappings"].(map[string]interface{})
+	properties, _ = mappings["properties"].(map[string]interface{})
+	return
+}
+
+// PutIndexMappingProperties adds mapping properties to an existing index. Existing properties cannot be changed.
+func (es GenericElasticsearch) PutIndexMappingProperties(indexName string, properties string) (err error) {
+	req := esapi.IndicesPutMappingRequest{
+		Index: []string{indexName},
+		Body:  strings.NewReader(`{"properties":` + properties + `}`),
+	}
+
+	res, err := req.Do(es.Context, es.Client)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	_, _, err = parseResponse(res)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+// GetPipeline returns the definition of an ingest pipeline as stored in Elasticsearch
+func (es GenericElasticsearch) GetPipeline(name string) (pipeline map[string]interface{}, err error) {
+	req := esapi.IngestGetPipelineRequest{
+		DocumentID: name,
+	}
+
+	res, err := req.Do(es.Context, es.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	resCode, body, err := parseResponse(res)
+	if resCode == 404 {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	pipeline, _ = body[name].(map[string]interface{})
+	return
+}
+
+// CountIndex returns the number of documents in an index
+func (es GenericElasticsearch) CountIndex(indexName string) (count int, err error) {
+	req := esapi.CountRequest{
+		Index: []string{indexName},
+	}
+
+	res, err := req.Do(es.Context, es.Client)
+	if err != nil {
+		return -1, errors.Wrap(err, 0)
+	}
+
+	_, body, err := parseResponse(res)
+	if err != nil {
+		return -1, errors.Wrap(err, 0)
+	}
+
+	countValue, ok := body["count"].(float64)
+	if !ok {
+		return -1, errors.Wrap(errors.New("count not found in Elasticsearch response for index "+indexName), 0)
+	}
+	return int(countValue), nil
+}
+
+// GetIndicesWithAlias returns the indices an alias currently points to
+func (es GenericElasticsearch) GetIndicesWithAlias(alias string) (indices []string, err error) {
+	req := esapi.CatAliasesRequest{
+		Name:   []string{alias},
+		Format: "JSON",
+	}
+	res, err := req.Do(es.Context, es.Client)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	defer res.Body.Close()
+
+	byteValue, _ := io.ReadAll(res.Body)
+	if res.StatusCode == 404 {
+		return indices, nil
+	} else if res.IsError() {
+		return nil, errors.Wrap(errors.New(fmt.Sprintf(
+			"Unable to get indices for alias %s. StatusCode: %s, Body: %s",
+			alias, strconv.Itoa(res.StatusCode), byteValue)), 0)
+	}
+
+	var aliasesResponse []CatAliasResponse
+	err = json.Unmarshal(byteValue, &aliasesResponse)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	for _, aliasResponse := range aliasesResponse {
+		indices = append(indices, aliasResponse.Index)
+	}
+	return
+}
+
+// MoveAlias points an alias to a single index. The alias is removed from every other index in the same request
+// so readers of the alias never see zero or two indices.
+func (es GenericElasticsearch) MoveAlias(alias string, index string) (err error) {
+	currentIndices, err := es.GetIndicesWithAlias(alias)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	if len(currentIndices) == 1 && currentIndices[0] == index {
+		return
+	}
+
+	var actions []UpdateAliasAction
+	for _, currentIndex := range currentIndices {
+		if currentIndex != index {
+			actions = append(actions, RemoveAliasAction{Remove: UpdateAliasIndex{Index: currentIndex, Alias: alias}})
+		}
+	}
+	actions = append(actions, AddAliasAction{Add: UpdateAliasIndex{Index: index, Alias: alias}})
+
+	reqJSON, err := json.Marshal(UpdateAliasRequest{Actions: actions})
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	res, err := es.Client.Indices.UpdateAliases(bytes.NewReader(reqJSON))
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	_, _, err = parseResponse(res)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
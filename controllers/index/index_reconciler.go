@@ -2,12 +2,13 @@ package index
 
 import (
 	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/components"
-	"github.com/redhatinsights/xjoin-operator/controllers/config"
-	"github.com/redhatinsights/xjoin-operator/controllers/elasticsearch"
 	"github.com/redhatinsights/xjoin-operator/controllers/kafka"
 	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"time"
 )
 
 type ReconcileMethods struct {
@@ -71,11 +72,36 @@ func (d *ReconcileMethods) Refreshing() (err error) {
 }
 
 func (d *ReconcileMethods) RefreshComplete() (err error) {
-	if d.iteration.GetInstance().Status.ActiveVersion != "" {
-		err = d.iteration.DeleteIndexPipeline(d.iteration.GetInstance().Name, d.iteration.GetInstance().Status.ActiveVersion)
-		if err != nil {
-			return errors.Wrap(err, 0)
-		}
+	instance := d.iteration.GetInstance()
+	if instance.Status.ActiveVersion == "" {
+		return
+	}
+
+	//keep the active version as the previous version to allow a fast rollback,
+	//an existing previous version is then removed like any other orphaned version
+	retention := d.iteration.Parameters.PreviousVersionRetention.Int()
+	if retention > 0 {
+		expiration := metav1.NewTime(time.Now().Add(time.Duration(retention) * time.Second))
+		instance.SetPreviousVersion(instance.Status.ActiveVersion, &expiration)
+		return
+	}
+
+	err = d.iteration.DeleteIndexPipeline(instance.Name, instance.Status.ActiveVersion)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return
+}
+
+func (d *ReconcileMethods) AwaitingApproval() (summary v1alpha1.RefreshSummary, err error) {
+	err = d.iteration.ReconcileChildren()
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	summary, err = d.iteration.RefreshSummary()
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
 	}
 	return
 }
@@ -88,6 +114,9 @@ func (d *ReconcileMethods) Scrub() (errs []error) {
 	if d.iteration.GetInstance().Status.RefreshingVersion != "" {
 		validVersions = append(validVersions, d.iteration.GetInstance().Status.RefreshingVersion)
 	}
+	if d.iteration.GetInstance().Status.PreviousVersion != "" {
+		validVersions = append(validVersions, d.iteration.GetInstance().Status.PreviousVersion)
+	}
 
 	kafkaClient := kafka.GenericKafka{
 		Context:          d.iteration.Context,
@@ -98,13 +127,7 @@ func (d *ReconcileMethods) Scrub() (errs []error) {
 		ConnectCluster:   d.iteration.Parameters.ConnectCluster.String(),
 	}
 
-	genericElasticsearch, err := elasticsearch.NewGenericElasticsearch(elasticsearch.GenericElasticSearchParameters{
-		Url:        d.iteration.Parameters.ElasticSearchURL.String(),
-		Username:   d.iteration.Parameters.ElasticSearchUsername.String(),
-		Password:   d.iteration.Parameters.ElasticSearchPassword.String(),
-		Parameters: config.ParametersToMap(d.iteration.Parameters),
-		Context:    d.iteration.Context,
-	})
+	genericElasticsearch, err := d.iteration.NewGenericElasticsearch()
 	if err != nil {
 		return append(errs, errors.Wrap(err, 0))
 	}
@@ -0,0 +1,209 @@
+package index
+
+import (
+	"fmt"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/avro"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/components"
+	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	"github.com/riferrei/srclient"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"strings"
+)
+
+// EvolveSchema applies a change of the avroSchema to the active XJoinIndexPipeline in place when the change only
+// adds fields and is compatible with the registered schema. The datasources then re-emit their records through
+// incremental snapshots so the existing documents get the added fields. When the change is breaking, or a datasource
+// cannot re-emit its records, refreshReason describes why.
+// Nothing is evolved when other fields of the spec changed too, these changes are applied by a refresh.
+func (i *XJoinIndexIteration) EvolveSchema() (evolved bool, refreshReason string, err error) {
+	instance := i.GetInstance()
+	if instance.Status.ActiveVersion == "" || !instance.Status.ActiveVersionIsValid ||
+		instance.Status.RefreshingVersion != "" || instance.Status.SpecHash == "" {
+		return false, "", nil
+	}
+
+	activeIndexPipeline, err := k8sUtils.FetchXJoinIndexPipeline(i.Client, types.NamespacedName{
+		Name:      instance.GetName() + "." + instance.Status.ActiveVersion,
+		Namespace: instance.GetNamespace(),
+	}, i.Context)
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+	if activeIndexPipeline.Spec.AvroSchema == instance.Spec.AvroSchema {
+		return false, "", nil
+	}
+
+	activeInstance := instance.DeepCopy()
+	activeInstance.Spec.AvroSchema = activeIndexPipeline.Spec.AvroSchema
+	activeSpecHash, err := k8sUtils.SpecHash(activeInstance.GetSpec())
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+	if activeSpecHash != instance.Status.SpecHash {
+		return false, "", nil
+	}
+
+	registry := schemaregistry.NewSchemaRegistryConfluentClient(
+		schemaregistry.ConnectionParams{
+			Protocol: i.Parameters.SchemaRegistryProtocol.String(),
+			Hostname: i.Parameters.SchemaRegistryHost.String(),
+			Port:     i.Parameters.SchemaRegistryPort.String(),
+		})
+	registry.Init()
+
+	previous, err := i.parseAvroSchema(activeIndexPipeline, activeIndexPipeline.Spec.AvroSchema, registry)
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+	next, err := i.parseAvroSchema(activeIndexPipeline, instance.Spec.AvroSchema, registry)
+	if err != nil {
+		//the refresh reports the error through the new XJoinIndexPipeline
+		return false, "avroSchema change could not be parsed: " + err.Error(), nil
+	}
+
+	changes, err := breakingSchemaChanges(previous, next)
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+	if len(changes) > 0 {
+		return false, "avroSchema change is not additive: " + strings.Join(changes, ", "), nil
+	}
+
+	//the documents already in the index only get the added fields when their records are re-emitted
+	dataSources, refreshReason, err := i.fetchReemittableDataSources(next.References)
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+	if refreshReason != "" {
+		return false, refreshReason, nil
+	}
+
+	avroSchema := components.NewAvroSchema(components.AvroSchemaParameters{
+		Schema:   next.AvroSchemaString,
+		Registry: registry,
+	})
+	avroSchema.SetName(common.IndexPipelineGVK.Kind + "." + instance.GetName())
+	avroSchema.SetVersion(instance.Status.ActiveVersion)
+	compatible, err := avroSchema.IsCompatible()
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+	if !compatible {
+		return false, fmt.Sprintf(
+			"avroSchema change is not compatible with the registered schema %s", avroSchema.Name()), nil
+	}
+
+	i.Log.Info("Evolving the avroSchema of the active version in place", "version", instance.Status.ActiveVersion)
+	activeIndexPipeline.Spec.AvroSchema = instance.Spec.AvroSchema
+	err = i.Client.Update(i.Context, activeIndexPipeline)
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+
+	avroSchemaHash, err := k8sUtils.SpecHash(instance.Spec.AvroSchema)
+	if err != nil {
+		return false, "", errors.Wrap(err, 0)
+	}
+	for _, dataSource := range dataSources {
+		err = i.requestIncrementalSnapshot(dataSource, instance.GetName()+"."+avroSchemaHash)
+		if err != nil {
+			return false, "", errors.Wrap(err, 0)
+		}
+	}
+
+	return true, "", nil
+}
+
+// fetchReemittableDataSources fetches the datasources of the index. The refreshReason is set when one of them
+// cannot re-emit its records through an incremental snapshot.
+func (i *XJoinIndexIteration) fetchReemittableDataSources(references []srclient.Reference) (
+	dataSources []*v1alpha1.XJoinDataSource, refreshReason string, err error) {
+
+	for _, reference := range references {
+		name, err := DataSourceName(reference)
+		if err != nil {
+			return nil, "", errors.Wrap(err, 0)
+		}
+		dataSource, err := k8sUtils.FetchXJoinDataSource(i.Client, types.NamespacedName{
+			Name:      name,
+			Namespace: i.Instance.GetNamespace(),
+		}, i.Context)
+		if err != nil {
+			return nil, "", errors.Wrap(err, 0)
+		}
+
+		if !dataSource.RefreshWithIncrementalSnapshot() || dataSource.Spec.DatabaseSignalTable == "" ||
+			dataSource.KafkaTopicSource() != nil {
+			return nil, fmt.Sprintf("avroSchema change requires the records of datasource %s to be re-emitted, "+
+				"which needs an IncrementalSnapshot refreshPolicy and a databaseSignalTable", name), nil
+		}
+		dataSources = append(dataSources, dataSource)
+	}
+	return
+}
+
+// requestIncrementalSnapshot refreshes a datasource through the refresh annotation, the datasource's refresh
+// strategy re-reads its table with an incremental snapshot
+func (i *XJoinIndexIteration) requestIncrementalSnapshot(dataSource *v1alpha1.XJoinDataSource, value string) error {
+	annotations := dataSource.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[v1alpha1.RefreshAnnotation] = value
+	dataSource.SetAnnotations(annotations)
+
+	err := i.Client.Update(i.Context, dataSource)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	i.Log.Info("Requested an incremental snapshot to re-emit the records with the evolved avroSchema",
+		"dataSource", dataSource.GetName())
+	return nil
+}
+
+func (i *XJoinIndexIteration) parseAvroSchema(indexPipeline *v1alpha1.XJoinIndexPipeline, schema string,
+	registry *schemaregistry.ConfluentClient) (avro.IndexAvroSchema, error) {
+
+	parser := avro.IndexAvroSchemaParser{
+		AvroSchema:      schema,
+		Client:          i.Client,
+		Context:         i.Context,
+		Namespace:       indexPipeline.GetNamespace(),
+		Log:             i.Log,
+		SchemaRegistry:  registry,
+		SchemaNamespace: indexPipeline.GetName(),
+	}
+	indexAvroSchema, err := parser.Parse()
+	if err != nil {
+		return indexAvroSchema, errors.Wrap(err, 0)
+	}
+	return indexAvroSchema, nil
+}
+
+// breakingSchemaChanges lists the changes between two index schemas that cannot be applied to the existing components.
+// Fields can be added, but existing fields cannot be changed or removed and the datasources have to stay the same.
+func breakingSchemaChanges(previous avro.IndexAvroSchema, next avro.IndexAvroSchema) (changes []string, err error) {
+	if previous.SourceTopics != next.SourceTopics {
+		changes = append(changes, fmt.Sprintf(
+			"source topics changed from [%s] to [%s]", previous.SourceTopics, next.SourceTopics))
+	}
+
+	//the Elasticsearch pipeline which parses json fields is only created with the index
+	if !reflect.DeepEqual(previous.JSONFields, next.JSONFields) {
+		changes = append(changes, fmt.Sprintf(
+			"json fields changed from %v to %v", previous.JSONFields, next.JSONFields))
+	}
+
+	mappingChanges, err := components.BreakingMappingChanges(previous.ESProperties, next.ESProperties)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	changes = append(changes, mappingChanges...)
+	return
+}
@@ -0,0 +1,203 @@
+package index
+
+import (
+	"encoding/json"
+	"github.com/go-errors/errors"
+	"github.com/redhatinsights/xjoin-go-lib/pkg/avro"
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/config"
+	"github.com/redhatinsights/xjoin-operator/controllers/database"
+	"github.com/redhatinsights/xjoin-operator/controllers/datasource"
+	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	"github.com/riferrei/srclient"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"time"
+)
+
+// ValidationRepair records the records re-emitted after a validation run. Error is set when only some of the
+// signals were sent.
+type ValidationRepair struct {
+	Ids         int      `json:"ids"`
+	Signals     int      `json:"signals"`
+	DataSources []string `json:"dataSources,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// repairableIds are the mismatched records that still exist in the database.
+// Records only in Elasticsearch cannot be re-emitted.
+func repairableIds(details validation.ResponseDetails) (ids []string) {
+	idsMap := make(map[string]bool)
+	for _, id := range append(details.IdsMissingFromElasticsearch, details.IdsWithMismatchContent...) {
+		idsMap[id] = true
+	}
+	for id := range idsMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return
+}
+
+// shouldRepair is true when the mismatched records of a failed validation are few enough to be repaired
+// and the pipeline was not repaired within the repair interval
+func (i *XJoinIndexValidatorIteration) shouldRepair(
+	response validation.ValidationResponse, pipeline *v1alpha1.XJoinIndexPipeline, mismatchPercentage float64) bool {
+
+	threshold := i.Parameters.ValidationRepairThreshold.Int()
+	if threshold == 0 || response.Result == "valid" || mismatchPercentage > float64(threshold) {
+		return false
+	}
+
+	ids := repairableIds(response.Details)
+	if len(ids) == 0 || len(ids) > i.Parameters.ValidationRepairMaxIds.Int() {
+		return false
+	}
+
+	interval := time.Duration(i.Parameters.ValidationRepairInterval.Int()) * time.Second
+	lastRepair := pipeline.Status.LastValidationRepair
+	return lastRepair == nil || time.Since(lastRepair.Time) >= interval
+}
+
+// RepairMismatches asks the Debezium connector of the index's root datasource to re-emit the records with the given
+// ids. The root datasource is the first reference of the index avro schema, its primary key is the document id.
+// Returns nil when the datasource was not signaled, e.g. because it has no signal table. A repair that failed after
+// sending some signals is still returned, so it is recorded and not repeated before the repair interval passed.
+func (i *XJoinIndexValidatorIteration) RepairMismatches(
+	references []srclient.Reference, ids []string) *ValidationRepair {
+
+	if len(references) == 0 {
+		return nil
+	}
+
+	dataSourcePipelineName, err := DataSourcePipelineName(references[0])
+	if err != nil {
+		i.Log.Error(err, "Unable to repair mismatched records")
+		return nil
+	}
+	signals, err := i.signalDataSource(dataSourcePipelineName, ids)
+	if err != nil {
+		i.Log.Error(err, "Unable to repair mismatched records",
+			"dataSourcePipeline", dataSourcePipelineName, "signals", signals)
+		if signals == 0 {
+			return nil
+		}
+	}
+
+	repair := &ValidationRepair{Ids: len(ids), Signals: signals, DataSources: []string{dataSourcePipelineName}}
+	if err != nil {
+		repair.Error = err.Error()
+	}
+	return repair
+}
+
+// PrimaryKeyColumn is the field of a datasource avro schema marked with xjoin.primary.key, or defaultColumn when no
+// field is marked
+func PrimaryKeyColumn(avroSchema string, defaultColumn string) (string, error) {
+	var schema avro.Schema
+	err := json.Unmarshal([]byte(avroSchema), &schema)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	for _, field := range schema.Fields {
+		for _, fieldType := range field.Type {
+			if fieldType.XJoinPrimaryKey {
+				return field.Name, nil
+			}
+		}
+	}
+	return defaultColumn, nil
+}
+
+func (i *XJoinIndexValidatorIteration) signalDataSource(
+	dataSourcePipelineName string, ids []string) (signals int, err error) {
+	dataSourcePipeline := &v1alpha1.XJoinDataSourcePipeline{}
+	err = i.Client.Get(
+		i.Context,
+		client.ObjectKey{Name: dataSourcePipelineName, Namespace: i.Instance.GetNamespace()},
+		dataSourcePipeline)
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	}
+
+	p := parameters.BuildDataSourceParameters()
+	configManager, err := config.NewManager(config.ManagerOptions{
+		Client:         i.Client,
+		Parameters:     p,
+		ConfigMapNames: []string{"xjoin-generic"},
+		Namespace:      i.Instance.GetNamespace(),
+		Spec:           dataSourcePipeline.Spec,
+		Context:        i.Context,
+		Log:            i.Log,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	}
+	err = configManager.Parse()
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	}
+
+	if p.DatabaseSignalTable.String() == "" {
+		return 0, errors.Wrap(errors.New("the datasource does not have a databaseSignalTable"), 0)
+	}
+
+	//the repair's watermarks would be counted as chunks of a running incremental snapshot
+	signalTableSnapshot, err := k8sUtils.SignalTableSnapshot(i.Client, i.Context, i.Instance.GetNamespace(), "",
+		dataSourcePipeline.Spec.DatabaseHostname, dataSourcePipeline.Spec.DatabaseName, p.DatabaseSignalTable.String())
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	} else if signalTableSnapshot != "" {
+		return 0, errors.Wrap(errors.New(
+			"the incremental snapshot of datasource "+signalTableSnapshot+" is using the signal table"), 0)
+	}
+
+	//the operator only connects to PostgreSQL databases
+	if databaseType := p.DatabaseType.String(); databaseType != "" && databaseType != v1alpha1.DatabaseTypePostgres {
+		return 0, errors.Wrap(errors.New("repairs are not supported for databaseType "+databaseType), 0)
+	}
+
+	db := database.NewDatabase(datasource.OperatorDBParams(*p))
+	err = db.Connect()
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	}
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			i.Log.Error(closeErr, "Unable to close the database connection")
+		}
+	}()
+
+	idColumn, err := PrimaryKeyColumn(
+		dataSourcePipeline.Spec.AvroSchema, i.Parameters.ValidationRepairIdColumn.String())
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	}
+
+	filter := ""
+	if dataSourcePipeline.Spec.RowFilter != nil {
+		filter = dataSourcePipeline.Spec.RowFilter.Where
+	}
+
+	//each signal has to fit into the data column of the signal table
+	conditions, err := database.IdsSignalConditions(p.DatabaseTable.String(), filter, idColumn, ids)
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	}
+
+	for _, condition := range conditions {
+		signalId, err := db.SendIncrementalSnapshotSignal(
+			p.DatabaseSignalTable.String(), p.DatabaseTable.String(), condition)
+		if err != nil {
+			return signals, errors.Wrap(err, 0)
+		}
+		signals++
+
+		i.Log.Info("Requested a repair of mismatched records", "dataSourcePipeline", dataSourcePipelineName,
+			"idColumn", idColumn, "signal", signalId)
+	}
+	return
+}
@@ -0,0 +1,205 @@
+package index
+
+import (
+	"encoding/json"
+	"github.com/go-errors/errors"
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	v1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const ValidationReportHistoryKey = "history"
+
+// ValidationReportMaxBytes keeps the history below the 1 MiB size limit of a ConfigMap, leaving room for its metadata
+const ValidationReportMaxBytes = 900 * 1024
+
+// ValidationReportEntry is a single validation run stored in a validation report ConfigMap
+type ValidationReportEntry struct {
+	Time    metav1.Time `json:"time"`
+	Version string      `json:"version"`
+	Failed  bool        `json:"failed"`
+	validation.ValidationResponse
+	Repair *ValidationRepair `json:"repair,omitempty"`
+}
+
+// ValidationReportName is the name of the ConfigMap holding the validation history of an XJoinIndexPipeline
+func ValidationReportName(pipelineName string) string {
+	return "xjoin-validation-report-" + pipelineName
+}
+
+// ParseValidationReport returns the validation runs stored in a validation report ConfigMap, newest first
+func ParseValidationReport(configMap *v1.ConfigMap) (history []ValidationReportEntry, err error) {
+	historyJson := configMap.Data[ValidationReportHistoryKey]
+	if historyJson == "" {
+		return
+	}
+
+	err = json.Unmarshal([]byte(historyJson), &history)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	return
+}
+
+// truncateValidationDetails limits each list of mismatched record ids to maxIds entries.
+// The counts keep the total number of mismatched records.
+func truncateValidationDetails(details validation.ResponseDetails, maxIds int) validation.ResponseDetails {
+	if maxIds < 0 {
+		maxIds = 0
+	}
+	if len(details.IdsMissingFromElasticsearch) > maxIds {
+		details.IdsMissingFromElasticsearch = details.IdsMissingFromElasticsearch[:maxIds]
+	}
+	if len(details.IdsOnlyInElasticsearch) > maxIds {
+		details.IdsOnlyInElasticsearch = details.IdsOnlyInElasticsearch[:maxIds]
+	}
+	if len(details.IdsWithMismatchContent) > maxIds {
+		details.IdsWithMismatchContent = details.IdsWithMismatchContent[:maxIds]
+	}
+	if len(details.MismatchContentDetails) > maxIds {
+		details.MismatchContentDetails = details.MismatchContentDetails[:maxIds]
+	}
+	return details
+}
+
+// longestValidationDetails is the length of the longest list of mismatched record ids
+func longestValidationDetails(details validation.ResponseDetails) int {
+	longest := len(details.IdsMissingFromElasticsearch)
+	for _, length := range []int{
+		len(details.IdsOnlyInElasticsearch),
+		len(details.IdsWithMismatchContent),
+		len(details.MismatchContentDetails),
+	} {
+		if length > longest {
+			longest = length
+		}
+	}
+	return longest
+}
+
+// statusValidationResponse is the response stored in the XJoinIndexPipeline's status. Like the report, its lists of
+// record ids are limited to maxIds entries and halved until the response fits in ValidationReportMaxBytes.
+func statusValidationResponse(
+	response validation.ValidationResponse, maxIds int) (validation.ValidationResponse, error) {
+
+	response.Details = truncateValidationDetails(response.Details, maxIds)
+	for {
+		responseJson, err := json.Marshal(response)
+		if err != nil {
+			return response, errors.Wrap(err, 0)
+		}
+		longest := longestValidationDetails(response.Details)
+		if len(responseJson) <= ValidationReportMaxBytes || longest == 0 {
+			return response, nil
+		}
+		response.Details = truncateValidationDetails(response.Details, longest/2)
+	}
+}
+
+// marshalValidationReport serializes the history, dropping the oldest runs until it fits in ValidationReportMaxBytes.
+// When the latest run alone is too large, its lists of record ids are halved until it fits.
+func marshalValidationReport(history []ValidationReportEntry) (historyJson []byte, err error) {
+	for {
+		historyJson, err = json.Marshal(history)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		if len(historyJson) <= ValidationReportMaxBytes {
+			return
+		}
+
+		if len(history) > 1 {
+			history = history[:len(history)-1]
+			continue
+		}
+
+		longest := longestValidationDetails(history[0].Details)
+		if longest == 0 {
+			return nil, errors.Wrap(errors.New(
+				"the validation report exceeds the ConfigMap size limit without any mismatched record ids"), 0)
+		}
+		history[0].Details = truncateValidationDetails(history[0].Details, longest/2)
+	}
+}
+
+// writeValidationReport adds a validation run to the pipeline's validation report ConfigMap.
+// Only the latest validation.report.history.size runs are kept, at least the latest one.
+func (i *XJoinIndexValidatorIteration) writeValidationReport(
+	pipeline *v1alpha1.XJoinIndexPipeline, response validation.ValidationResponse, failed bool,
+	repair *ValidationRepair) (name string, err error) {
+
+	name = ValidationReportName(pipeline.Name)
+	entry := ValidationReportEntry{
+		Time:               metav1.Now(),
+		Version:            pipeline.Spec.Version,
+		Failed:             failed,
+		ValidationResponse: response,
+		Repair:             repair,
+	}
+	entry.Details = truncateValidationDetails(response.Details, i.Parameters.ValidationReportMaxIds.Int())
+
+	configMap := &v1.ConfigMap{}
+	err = i.Client.Get(i.Context, client.ObjectKey{Name: name, Namespace: pipeline.Namespace}, configMap)
+	exists := true
+	if k8errors.IsNotFound(err) {
+		exists = false
+		controller := true
+		blockOwnerDeletion := true
+		configMap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: pipeline.Namespace,
+				Labels: map[string]string{
+					common.COMPONENT_NAME_LABEL: "XJoinIndexValidator",
+					"xjoin.index":               i.Instance.GetName(),
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion:         common.IndexPipelineGVK.GroupVersion().String(),
+					Kind:               common.IndexPipelineGVK.Kind,
+					Name:               pipeline.Name,
+					UID:                pipeline.UID,
+					Controller:         &controller,
+					BlockOwnerDeletion: &blockOwnerDeletion,
+				}},
+			},
+		}
+	} else if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	history, err := ParseValidationReport(configMap)
+	if err != nil {
+		i.Log.Error(err, "Unable to parse the validation report, starting a new history", "configMap", name)
+		history = nil
+	}
+
+	history = append([]ValidationReportEntry{entry}, history...)
+	historySize := i.Parameters.ValidationReportHistorySize.Int()
+	if historySize < 1 {
+		historySize = 1
+	}
+	if len(history) > historySize {
+		history = history[:historySize]
+	}
+
+	historyJson, err := marshalValidationReport(history)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	configMap.Data = map[string]string{ValidationReportHistoryKey: string(historyJson)}
+
+	if exists {
+		err = i.Client.Update(i.Context, configMap)
+	} else {
+		err = i.Client.Create(i.Context, configMap)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	return
+}
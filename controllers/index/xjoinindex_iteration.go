@@ -1,13 +1,21 @@
 package index
 
 import (
+	"fmt"
 	"github.com/go-errors/errors"
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/config"
+	"github.com/redhatinsights/xjoin-operator/controllers/elasticsearch"
 	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"strings"
 )
 
 type XJoinIndexIteration struct {
@@ -33,6 +41,13 @@ func (i *XJoinIndexIteration) CreateIndexPipeline(name string, version string) (
 			"customSubgraphImages": i.Parameters.CustomSubgraphImages.Value(),
 		},
 	}
+	if i.GetInstance().Spec.Workloads != nil {
+		workloads, err := runtime.DefaultUnstructuredConverter.ToUnstructured(i.GetInstance().Spec.Workloads)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		indexPipeline.Object["spec"].(map[string]interface{})["workloads"] = workloads
+	}
 	indexPipeline.SetGroupVersionKind(common.IndexPipelineGVK)
 
 	err = i.CreateChildResource(indexPipeline, common.IndexGVK)
@@ -50,6 +65,186 @@ func (i *XJoinIndexIteration) DeleteIndexPipeline(name string, version string) (
 	return
 }
 
+// CheckDataSources compares the datasources recorded in the index status with their current active version and
+// avro schema. A non-empty refreshReason is returned when a change to any datasource requires the index to refresh.
+func (i *XJoinIndexIteration) CheckDataSources() (
+	current map[string]v1alpha1.XJoinIndexDataSource, refreshReason string, err error) {
+
+	current = make(map[string]v1alpha1.XJoinIndexDataSource)
+	for name, recorded := range i.GetInstance().Status.DataSourceVersions {
+		dataSource, err := k8sUtils.FetchXJoinDataSource(i.Client, types.NamespacedName{
+			Name:      name,
+			Namespace: i.GetInstance().GetNamespace(),
+		}, i.Context)
+		if err != nil {
+			return nil, "", errors.Wrap(err, 0)
+		}
+
+		//the datasource has not finished its initial sync, keep what was recorded until it has
+		if dataSource.Status.ActiveVersion == "" {
+			current[name] = recorded
+			continue
+		}
+
+		dataSourcePipeline, err := k8sUtils.FetchXJoinDataSourcePipeline(i.Client, types.NamespacedName{
+			Name:      name + "." + dataSource.Status.ActiveVersion,
+			Namespace: i.GetInstance().GetNamespace(),
+		}, i.Context)
+		if err != nil {
+			return nil, "", errors.Wrap(err, 0)
+		}
+
+		avroSchemaHash, err := k8sUtils.SpecHash(dataSourcePipeline.Spec.AvroSchema)
+		if err != nil {
+			return nil, "", errors.Wrap(err, 0)
+		}
+
+		current[name] = v1alpha1.XJoinIndexDataSource{
+			ActiveVersion:  dataSource.Status.ActiveVersion,
+			AvroSchemaHash: avroSchemaHash,
+		}
+
+		if recorded == (v1alpha1.XJoinIndexDataSource{}) || refreshReason != "" {
+			continue
+		} else if recorded.ActiveVersion != current[name].ActiveVersion {
+			refreshReason = fmt.Sprintf("datasource %s active version changed from %s to %s",
+				name, recorded.ActiveVersion, current[name].ActiveVersion)
+		} else if recorded.AvroSchemaHash != current[name].AvroSchemaHash {
+			refreshReason = fmt.Sprintf("datasource %s avro schema changed", name)
+		}
+	}
+
+	return
+}
+
+func (i *XJoinIndexIteration) NewGenericElasticsearch() (*elasticsearch.GenericElasticsearch, error) {
+	genericElasticsearch, err := elasticsearch.NewGenericElasticsearch(elasticsearch.GenericElasticSearchParameters{
+		Url:        i.Parameters.ElasticSearchURL.String(),
+		Username:   i.Parameters.ElasticSearchUsername.String(),
+		Password:   i.Parameters.ElasticSearchPassword.String(),
+		Parameters: config.ParametersToMap(i.Parameters),
+		Context:    i.Context,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+	return genericElasticsearch, nil
+}
+
+// RefreshSummary compares the active and refreshing XJoinIndexPipelines, including their Elasticsearch document counts
+func (i *XJoinIndexIteration) RefreshSummary() (summary v1alpha1.RefreshSummary, err error) {
+	instance := i.GetInstance()
+	activePipeline, err := k8sUtils.FetchXJoinIndexPipeline(i.Client, types.NamespacedName{
+		Name:      instance.Name + "." + instance.Status.ActiveVersion,
+		Namespace: instance.Namespace,
+	}, i.Context)
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	refreshingPipeline, err := k8sUtils.FetchXJoinIndexPipeline(i.Client, types.NamespacedName{
+		Name:      instance.Name + "." + instance.Status.RefreshingVersion,
+		Namespace: instance.Namespace,
+	}, i.Context)
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	summary.ActiveVersion = instance.Status.ActiveVersion
+	summary.RefreshingVersion = instance.Status.RefreshingVersion
+	summary.ActiveValidation = common.ValidationSummary(activePipeline.Status.ValidationResponse)
+	summary.RefreshingValidation = common.ValidationSummary(refreshingPipeline.Status.ValidationResponse)
+	summary.SchemaChanges, err = common.AvroSchemaChanges(activePipeline.Spec.AvroSchema, refreshingPipeline.Spec.AvroSchema)
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	genericElasticsearch, err := i.NewGenericElasticsearch()
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+
+	activeCount, err := genericElasticsearch.CountIndex(ElasticsearchIndexName(instance.Name, instance.Status.ActiveVersion))
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+	summary.ActiveDocumentCount = &activeCount
+
+	refreshingCount, err := genericElasticsearch.CountIndex(
+		ElasticsearchIndexName(instance.Name, instance.Status.RefreshingVersion))
+	if err != nil {
+		return summary, errors.Wrap(err, 0)
+	}
+	summary.RefreshingDocumentCount = &refreshingCount
+
+	return
+}
+
+// ReconcileAlias points the index's stable Elasticsearch alias to the Elasticsearch index of the active version
+func (i *XJoinIndexIteration) ReconcileAlias() (err error) {
+	instance := i.GetInstance()
+	if instance.Status.ActiveVersion == "" {
+		return
+	}
+
+	genericElasticsearch, err := i.NewGenericElasticsearch()
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	alias := ElasticsearchAliasName(instance.Name)
+	err = genericElasticsearch.MoveAlias(alias, ElasticsearchIndexName(instance.Name, instance.Status.ActiveVersion))
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	instance.Status.ElasticsearchAlias = alias
+	return
+}
+
+// ReconcileWorkloads copies the workloads and validation thresholds of the XJoinIndex to its existing
+// XJoinIndexPipelines. The pipelines roll them out to their deployments and validator without a refresh.
+func (i *XJoinIndexIteration) ReconcileWorkloads() (err error) {
+	instance := i.GetInstance()
+	for _, version := range []string{instance.Status.ActiveVersion, instance.Status.RefreshingVersion} {
+		if version == "" {
+			continue
+		}
+
+		indexPipeline, err := k8sUtils.FetchXJoinIndexPipeline(i.Client, types.NamespacedName{
+			Name:      instance.GetName() + "." + version,
+			Namespace: instance.GetNamespace(),
+		}, i.Context)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+
+		if equality.Semantic.DeepEqual(indexPipeline.Spec.Workloads, instance.Spec.Workloads) &&
+			equality.Semantic.DeepEqual(indexPipeline.Spec.Validation, instance.Spec.Validation) {
+			continue
+		}
+
+		indexPipeline.Spec.Workloads = instance.Spec.Workloads.DeepCopy()
+		indexPipeline.Spec.Validation = instance.Spec.Validation.DeepCopy()
+		err = i.Client.Update(i.Context, indexPipeline)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		i.Log.Info("Updated the workloads and validation of the XJoinIndexPipeline", "version", version)
+	}
+	return
+}
+
+// ElasticsearchAliasName is the stable alias of an XJoinIndex, it always points to the index of the active version
+func ElasticsearchAliasName(indexName string) string {
+	return strings.ToLower(common.IndexGVK.Kind + "." + indexName)
+}
+
+// ElasticsearchIndexName is the Elasticsearch index created by the XJoinIndexPipeline of an XJoinIndex version
+func ElasticsearchIndexName(indexName string, version string) string {
+	return strings.ToLower(common.IndexPipelineGVK.Kind + "." + indexName + "." + version)
+}
+
 func (i XJoinIndexIteration) GetInstance() *v1alpha1.XJoinIndex {
 	return i.Instance.(*v1alpha1.XJoinIndex)
 }
@@ -95,3 +290,12 @@ func (i *XJoinIndexIteration) ReconcileChildren() (err error) {
 	}
 	return
 }
+
+// ValidationIsWithinBudget is true when a pipeline has been validated and has fewer consecutive failed validations
+// than attemptsThreshold
+func ValidationIsWithinBudget(status v1alpha1.XJoinIndexPipelineStatus, attemptsThreshold int) bool {
+	if status.ValidationResponse.Result == "valid" {
+		return true
+	}
+	return status.ValidationResponse.Result != "" && status.ValidationFailedCount < attemptsThreshold
+}
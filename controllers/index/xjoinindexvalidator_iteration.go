@@ -1,20 +1,25 @@
 package index
 
 import (
-	"encoding/json"
+	"fmt"
 	"github.com/go-errors/errors"
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
 	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/avro"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/components"
+	"github.com/redhatinsights/xjoin-operator/controllers/config"
+	"github.com/redhatinsights/xjoin-operator/controllers/elasticsearch"
 	"github.com/redhatinsights/xjoin-operator/controllers/k8s"
 	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
 	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
 	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
 	"github.com/riferrei/srclient"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -52,7 +57,8 @@ func (i *XJoinIndexValidatorIteration) Finalize() (err error) {
 	return nil
 }
 
-func (i *XJoinIndexValidatorIteration) ReconcileValidationPod() (phase string, err error) {
+// ReconcileValidationJob runs an xjoin-validation job and stores its result on the owning XJoinIndexPipeline
+func (i *XJoinIndexValidatorIteration) ReconcileValidationJob() (phase string, err error) {
 	//Get index avro schema, references
 	registry := schemaregistry.NewSchemaRegistryConfluentClient(
 		schemaregistry.ConnectionParams{
@@ -77,23 +83,21 @@ func (i *XJoinIndexValidatorIteration) ReconcileValidationPod() (phase string, e
 		return "", errors.Wrap(err, 0)
 	}
 
-	//check if pod is already running
-	labels := client.MatchingLabels{}
-	labels["xjoin.index"] = i.Instance.GetName()
-	labels[common.COMPONENT_NAME_LABEL] = "XJoinIndexValidator"
-	podList := &v1.PodList{}
-	err = i.Client.List(i.Context, podList, client.InNamespace(i.Instance.GetNamespace()), labels)
+	if len(indexAvroSchema.KafkaTopicDataSources) > 0 {
+		return i.skipValidation(indexAvroSchema.KafkaTopicDataSources)
+	}
+
+	//check if the validation job was already created
+	job, err := k8s.PendingValidationJob(i.Context, i.Client, i.Instance.GetNamespace(), i.validationJobLabels())
 	if err != nil {
 		return "", errors.Wrap(err, 0)
 	}
-
-	//create the pod if not already running
-	if len(podList.Items) == 0 {
-		dbConnectionEnvVars, err := i.buildDBConnectionEnvVars(indexAvroSchema.References)
+	if job == nil {
+		dbConnectionEnvVars, certificates, err := i.buildDBConnectionEnvVars(indexAvroSchema.References)
 		if err != nil {
 			return "", errors.Wrap(err, 0)
 		}
-		err = i.createValidationPod(dbConnectionEnvVars, indexAvroSchema.AvroSchemaString)
+		err = i.createValidationJob(dbConnectionEnvVars, certificates, indexAvroSchema.AvroSchemaString)
 		if err != nil {
 			return "", errors.Wrap(err, 0)
 		}
@@ -101,208 +105,488 @@ func (i *XJoinIndexValidatorIteration) ReconcileValidationPod() (phase string, e
 		return ValidatorPodRunning, nil
 	}
 
-	//parse the results of the xjoin-validation pod
-	pod := &v1.Pod{}
-	err = i.Client.Get(i.Context, client.ObjectKey{Name: i.ValidationPodName(), Namespace: i.Instance.GetNamespace()}, pod)
-	if err != nil {
-		return "", errors.Wrap(err, 0)
+	if failed := k8s.JobCondition(job, batchv1.JobFailed); failed != nil {
+		i.setValidationResultCondition(metav1.ConditionFalse, "JobFailed",
+			"validation job "+job.Name+" failed: "+failed.Reason+", "+failed.Message)
+		err = k8s.RecordValidationJob(i.Context, i.Client, job)
+		if err != nil {
+			return "", errors.Wrap(err, 0)
+		}
+
+		return ValidatorPodFailed, nil
+	} else if job.Status.Succeeded == 0 {
+		return ValidatorPodRunning, nil
 	}
 
-	if pod.Status.Phase == v1.PodSucceeded {
-		//check output of xjoin-validation pod
-		response, err := i.ParsePodResponse()
+	//parse the results of the xjoin-validation job
+	response, err := k8s.ParseValidationJobResponse(i.Context, i.Client, i.PodLogReader, job)
+	if err != nil {
+		i.Log.Error(err, "Unable to parse the validation result")
+		i.setValidationResultCondition(metav1.ConditionFalse, "MalformedResult", err.Error())
+		err = k8s.RecordValidationJob(i.Context, i.Client, job)
 		if err != nil {
 			return "", errors.Wrap(err, 0)
 		}
 
-		//update xjoinindexpipeline resource based on xjoin-validation pod's output
-		i.Log.Info(response.Message)
+		return ValidatorPodFailed, nil
+	}
+
+	//update xjoinindexpipeline resource based on xjoin-validation job's output
+	i.Log.Info(response.Message)
 
-		indexNamespacedName := types.NamespacedName{
-			Name:      i.Instance.GetOwnerReferences()[0].Name,
-			Namespace: i.Instance.GetNamespace(),
+	indexNamespacedName := types.NamespacedName{
+		Name:      i.Instance.GetOwnerReferences()[0].Name,
+		Namespace: i.Instance.GetNamespace(),
+	}
+	xjoinIndexPipeline, err := k8sUtils.FetchXJoinIndexPipeline(i.Client, indexNamespacedName, i.Context)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	mismatchPercentage, known, err := i.mismatchPercentage(response)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	failed, err := i.validationFailed(response, xjoinIndexPipeline, mismatchPercentage, known)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	var repair *ValidationRepair
+	if known && i.shouldRepair(response, xjoinIndexPipeline, mismatchPercentage) {
+		repair = i.RepairMismatches(indexAvroSchema.References, repairableIds(response.Details))
+		if repair != nil {
+			now := metav1.Now()
+			xjoinIndexPipeline.Status.LastValidationRepair = &now
 		}
-		xjoinIndexPipeline, err := k8sUtils.FetchXJoinIndexPipeline(i.Client, indexNamespacedName, i.Context)
+	}
+	if failed {
+		snapshottingDataSources, err := i.snapshottingDataSources(indexAvroSchema.References)
 		if err != nil {
 			return "", errors.Wrap(err, 0)
 		}
-		xjoinIndexPipeline.Status.ValidationResponse = response
-
-		if err := i.Client.Status().Update(i.Context, xjoinIndexPipeline); err != nil {
-			if k8errors.IsConflict(err) {
-				i.Log.Error(err, "Status conflict")
-				return "", errors.Wrap(err, 0)
-			}
 
-			return "", errors.Wrap(err, 0)
+		//records are expected to mismatch until they are re-emitted, e.g. after the avroSchema evolved
+		if len(snapshottingDataSources) > 0 {
+			i.Log.Info("Not counting the failed validation while datasources are re-emitting their records",
+				"dataSources", snapshottingDataSources)
+		} else {
+			xjoinIndexPipeline.Status.ValidationFailedCount++
 		}
+	} else {
+		xjoinIndexPipeline.Status.ValidationFailedCount = 0
+	}
+	xjoinIndexPipeline.Status.ValidationResponse, err = statusValidationResponse(
+		response, i.Parameters.ValidationReportMaxIds.Int())
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	now := metav1.Now()
+	xjoinIndexPipeline.Status.LastValidationTime = &now
+	xjoinIndexPipeline.Status.ValidationReport, err = i.writeValidationReport(
+		xjoinIndexPipeline, response, failed, repair)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
 
-		err = i.Client.Delete(i.Context, pod)
-		if err != nil {
+	if err := i.Client.Status().Update(i.Context, xjoinIndexPipeline); err != nil {
+		if k8errors.IsConflict(err) {
+			i.Log.Error(err, "Status conflict")
 			return "", errors.Wrap(err, 0)
 		}
 
-		return ValidatorPodSuccess, nil
-	} else if pod.Status.Phase == v1.PodFailed {
-		err = i.Client.Delete(i.Context, pod)
-		if err != nil {
-			return "", errors.Wrap(err, 0)
+		return "", errors.Wrap(err, 0)
+	}
+
+	i.setValidationResultCondition(metav1.ConditionTrue, "ResultParsed", "validation result: "+response.Result)
+	err = k8s.RecordValidationJob(i.Context, i.Client, job)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	return ValidatorPodSuccess, nil
+}
+
+// skipValidation marks the owning XJoinIndexPipeline as valid without running a job. The records of datasources
+// that are read from Kafka topics are produced by other applications, so there is no database to validate against.
+func (i *XJoinIndexValidatorIteration) skipValidation(kafkaTopicDataSources []string) (phase string, err error) {
+	message := "validation is skipped because the index references datasources read from Kafka topics: " +
+		strings.Join(kafkaTopicDataSources, ", ")
+	i.Log.Info(message)
+
+	indexNamespacedName := types.NamespacedName{
+		Name:      i.Instance.GetOwnerReferences()[0].Name,
+		Namespace: i.Instance.GetNamespace(),
+	}
+	xjoinIndexPipeline, err := k8sUtils.FetchXJoinIndexPipeline(i.Client, indexNamespacedName, i.Context)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	xjoinIndexPipeline.Status.ValidationFailedCount = 0
+	xjoinIndexPipeline.Status.ValidationResponse = validation.ValidationResponse{
+		Result:  "valid",
+		Reason:  "skipped",
+		Message: message,
+	}
+	if err := i.Client.Status().Update(i.Context, xjoinIndexPipeline); err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	i.setValidationResultCondition(metav1.ConditionTrue, "Skipped", message)
+	return ValidatorPodSuccess, nil
+}
+
+// mismatchPercentage is the percentage of documents in the Elasticsearch index that were reported as mismatched.
+// known is false when the response does not contain the number of mismatched documents.
+func (i *XJoinIndexValidatorIteration) mismatchPercentage(
+	response validation.ValidationResponse) (percentage float64, known bool, err error) {
+
+	if response.Result == "valid" {
+		return 0, true, nil
+	} else if response.Details.TotalMismatch == 0 {
+		return 0, false, nil
+	}
+
+	genericElasticsearch, err := elasticsearch.NewGenericElasticsearch(elasticsearch.GenericElasticSearchParameters{
+		Url:        i.Parameters.ElasticSearchURL.String(),
+		Username:   i.Parameters.ElasticSearchUsername.String(),
+		Password:   i.Parameters.ElasticSearchPassword.String(),
+		Parameters: config.ParametersToMap(i.Parameters),
+		Context:    i.Context,
+	})
+	if err != nil {
+		return 0, false, errors.Wrap(err, 0)
+	}
+	count, err := genericElasticsearch.CountIndex(i.ElasticsearchIndexName)
+	if err != nil {
+		return 0, false, errors.Wrap(err, 0)
+	}
+	if count == 0 {
+		return 0, false, nil
+	}
+
+	percentage = float64(response.Details.TotalMismatch) / float64(count) * 100
+	i.Log.Info("Validation mismatch", "mismatchCount", response.Details.TotalMismatch,
+		"mismatchPercentage", percentage)
+	return percentage, true, nil
+}
+
+// validationFailed is true when a validation result is not valid and the percentage of mismatched documents exceeds
+// the tolerated percentage. The initial sync threshold applies until the pipeline's version becomes the active version.
+func (i *XJoinIndexValidatorIteration) validationFailed(
+	response validation.ValidationResponse, pipeline *v1alpha1.XJoinIndexPipeline,
+	mismatchPercentage float64, known bool) (failed bool, err error) {
+
+	if response.Result == "valid" {
+		return false, nil
+	} else if !known {
+		return true, nil
+	}
+
+	active, err := i.isActiveVersion(pipeline)
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	threshold := i.Parameters.ValidationInitPercentageThreshold.Int()
+	if active {
+		threshold = i.Parameters.ValidationPercentageThreshold.Int()
+	}
+
+	return mismatchPercentage > float64(threshold), nil
+}
+
+// isActiveVersion is true when the pipeline is the active version of its XJoinIndex
+func (i *XJoinIndexValidatorIteration) isActiveVersion(pipeline *v1alpha1.XJoinIndexPipeline) (bool, error) {
+	for _, owner := range pipeline.GetOwnerReferences() {
+		if owner.Kind != common.IndexGVK.Kind {
+			continue
 		}
 
-		return ValidatorPodFailed, nil
-	} else {
-		return ValidatorPodRunning, nil
+		xjoinIndex, err := k8sUtils.FetchXJoinIndex(
+			i.Client, types.NamespacedName{Name: owner.Name, Namespace: pipeline.Namespace}, i.Context)
+		if k8errors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, errors.Wrap(err, 0)
+		}
+		return xjoinIndex.Status.ActiveVersion == pipeline.Spec.Version, nil
 	}
+
+	return false, nil
+}
+
+func (i *XJoinIndexValidatorIteration) setValidationResultCondition(
+	status metav1.ConditionStatus, reason string, message string) {
+
+	meta.SetStatusCondition(&i.GetInstance().Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ValidationResultConditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
 }
 
 func (i *XJoinIndexValidatorIteration) GetInstance() *v1alpha1.XJoinIndexValidator {
 	return i.Instance.(*v1alpha1.XJoinIndexValidator)
 }
 
+// validationJobLabels select the validation jobs of the validator
+func (i *XJoinIndexValidatorIteration) validationJobLabels() client.MatchingLabels {
+	return client.MatchingLabels{
+		"xjoin.index":               i.Instance.GetName(),
+		common.COMPONENT_NAME_LABEL: "XJoinIndexValidator",
+	}
+}
+
+// ValidationPodName is the prefix of the names of the validation jobs, each job gets a generated suffix
 func (i *XJoinIndexValidatorIteration) ValidationPodName() string {
 	name := "xjoin-validation-" + i.Instance.GetName()
 	name = strings.ReplaceAll(name, ".", "-")
 	return name
 }
 
-func (i *XJoinIndexValidatorIteration) ParsePodResponse() (validation.ValidationResponse, error) {
-	var response validation.ValidationResponse
+// DataSourcePipelineName is the name of the XJoinDataSourcePipeline referenced by an index avro schema reference,
+// i.e. the <datasource>.<version> of its xjoindatasourcepipeline.<datasource>.<version>-value subject
+func DataSourcePipelineName(reference srclient.Reference) (string, error) {
+	prefix := "xjoindatasourcepipeline."
+	if !strings.HasPrefix(reference.Subject, prefix) || !strings.HasSuffix(reference.Subject, "-value") {
+		return "", errors.Wrap(fmt.Errorf(
+			"reference %s does not have a datasource pipeline subject: %s", reference.Name, reference.Subject), 0)
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(reference.Subject, prefix), "-value"), nil
+}
 
-	logString, err := i.PodLogReader.GetLogs(i.ValidationPodName(), i.Instance.GetNamespace())
+// DataSourceName is the name of the XJoinDataSource referenced by an index avro schema reference
+func DataSourceName(reference srclient.Reference) (string, error) {
+	dataSourcePipelineName, err := DataSourcePipelineName(reference)
 	if err != nil {
-		return response, errors.Wrap(err, 0)
+		return "", errors.Wrap(err, 0)
 	}
-	strArray := strings.Split(logString, "\n")
 
-	//parse last line, removing trailing new lines
-	var resultString string
-	if len(strArray) > 1 {
-		resultString = strArray[len(strArray)-2]
-	} else {
-		resultString = strArray[len(strArray)-1]
+	//the version is the last part of the name, datasource names can contain dots
+	versionIndex := strings.LastIndex(dataSourcePipelineName, ".")
+	if versionIndex < 1 {
+		return "", errors.Wrap(fmt.Errorf(
+			"reference %s does not have a datasource version: %s", reference.Name, reference.Subject), 0)
 	}
+	return dataSourcePipelineName[:versionIndex], nil
+}
 
-	err = json.Unmarshal([]byte(resultString), &response)
-	if err != nil {
-		return response, errors.Wrap(err, 0)
+// snapshottingDataSources are the referenced datasources whose records are being re-emitted by an incremental snapshot
+func (i *XJoinIndexValidatorIteration) snapshottingDataSources(
+	references []srclient.Reference) (names []string, err error) {
+
+	for _, reference := range references {
+		dataSourceName, err := DataSourceName(reference)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		dataSource, err := k8sUtils.FetchXJoinDataSource(i.Client, types.NamespacedName{
+			Name:      dataSourceName,
+			Namespace: i.Instance.GetNamespace(),
+		}, i.Context)
+		if err != nil {
+			return nil, errors.Wrap(err, 0)
+		}
+		if dataSource.IncrementalSnapshotInProgress() {
+			names = append(names, dataSource.GetName())
+		}
 	}
-	return response, nil
+	return
 }
 
-func (i *XJoinIndexValidatorIteration) buildDBConnectionEnvVars(references []srclient.Reference) (envVars []v1.EnvVar, err error) {
+func (i *XJoinIndexValidatorIteration) buildDBConnectionEnvVars(references []srclient.Reference) (
+	envVars []v1.EnvVar, certificates components.CertificateVolumes, err error) {
 	//gather db connection info for each datasource
 	//the db connection info is passed to the xjoin-validation pod as environment variables
 	//because the xjoin-validation pod does not know how to connect to the Kubernetes API
 	for _, ref := range references {
 		//Get datasourcepipeline k8s object to get db connection info
 		dataSourcePipeline := &v1alpha1.XJoinDataSourcePipeline{}
-		dataSourcePipelineName := strings.Split(ref.Subject, "xjoindatasourcepipeline.")[1]
-		dataSourcePipelineName = strings.Split(dataSourcePipelineName, "-value")[0]
+		dataSourcePipelineName, err := DataSourcePipelineName(ref)
+		if err != nil {
+			return envVars, certificates, errors.Wrap(err, 0)
+		}
 		err = i.Client.Get(
 			i.Context,
 			client.ObjectKey{Name: dataSourcePipelineName, Namespace: i.GetInstance().Namespace},
 			dataSourcePipeline)
 		if err != nil {
-			return envVars, errors.Wrap(err, 0)
+			return envVars, certificates, errors.Wrap(err, 0)
 		}
 
 		envVarPrefix := strings.Split(dataSourcePipelineName, ".")[0]
 		hostnameEnvVar, err := dataSourcePipeline.Spec.DatabaseHostname.ConvertToEnvVar(envVarPrefix + "_DB_HOSTNAME")
 		if err != nil {
-			return envVars, errors.Wrap(err, 0)
+			return envVars, certificates, errors.Wrap(err, 0)
 		}
 		envVars = append(envVars, hostnameEnvVar)
 
 		usernameEnvVar, err := dataSourcePipeline.Spec.DatabaseUsername.ConvertToEnvVar(envVarPrefix + "_DB_USERNAME")
 		if err != nil {
-			return envVars, errors.Wrap(err, 0)
+			return envVars, certificates, errors.Wrap(err, 0)
 		}
 		envVars = append(envVars, usernameEnvVar)
 
 		passwordEnvVar, err := dataSourcePipeline.Spec.DatabasePassword.ConvertToEnvVar(envVarPrefix + "_DB_PASSWORD")
 		if err != nil {
-			return envVars, errors.Wrap(err, 0)
+			return envVars, certificates, errors.Wrap(err, 0)
 		}
 		envVars = append(envVars, passwordEnvVar)
 
 		nameEnvVar, err := dataSourcePipeline.Spec.DatabaseName.ConvertToEnvVar(envVarPrefix + "_DB_NAME")
 		if err != nil {
-			return envVars, errors.Wrap(err, 0)
+			return envVars, certificates, errors.Wrap(err, 0)
 		}
 		envVars = append(envVars, nameEnvVar)
 
 		portEnvVar, err := dataSourcePipeline.Spec.DatabasePort.ConvertToEnvVar(envVarPrefix + "_DB_PORT")
 		if err != nil {
-			return envVars, errors.Wrap(err, 0)
+			return envVars, certificates, errors.Wrap(err, 0)
 		}
 		envVars = append(envVars, portEnvVar)
 
 		tableEnvVar, err := dataSourcePipeline.Spec.DatabaseTable.ConvertToEnvVar(envVarPrefix + "_DB_TABLE")
 		if err != nil {
-			return envVars, errors.Wrap(err, 0)
+			return envVars, certificates, errors.Wrap(err, 0)
 		}
 		envVars = append(envVars, tableEnvVar)
+
+		databaseType := dataSourcePipeline.Spec.DatabaseType
+		if databaseType == "" {
+			databaseType = v1alpha1.DatabaseTypePostgres
+		}
+		envVars = append(envVars, v1.EnvVar{Name: envVarPrefix + "_DB_TYPE", Value: databaseType})
+
+		if dataSourcePipeline.Spec.DatabaseSSLMode != nil {
+			sslModeEnvVar, err := dataSourcePipeline.Spec.DatabaseSSLMode.ConvertToEnvVar(envVarPrefix + "_DB_SSL_MODE")
+			if err != nil {
+				return envVars, certificates, errors.Wrap(err, 0)
+			}
+			envVars = append(envVars, sslModeEnvVar)
+		}
+
+		sslCertificates := []struct {
+			name  string
+			value *v1alpha1.StringOrSecretParameter
+		}{
+			{name: "_DB_SSL_ROOT_CERT", value: dataSourcePipeline.Spec.DatabaseSSLRootCert},
+			{name: "_DB_SSL_CERT", value: dataSourcePipeline.Spec.DatabaseSSLCert},
+			{name: "_DB_SSL_KEY", value: dataSourcePipeline.Spec.DatabaseSSLKey},
+		}
+		for _, sslCertificate := range sslCertificates {
+			if sslCertificate.value == nil {
+				continue
+			}
+			envVars = append(envVars, certificates.EnvVar(envVarPrefix+sslCertificate.name, sslCertificate.value))
+		}
+
+		if dataSourcePipeline.Spec.RowFilter != nil {
+			envVars = append(envVars, v1.EnvVar{
+				Name:  envVarPrefix + "_DB_ROW_FILTER",
+				Value: dataSourcePipeline.Spec.RowFilter.Where,
+			})
+		}
 	}
 
 	return
 }
 
-func (i *XJoinIndexValidatorIteration) createValidationPod(dbConnectionEnvVars []v1.EnvVar, fullAvroSchema string) error {
-	//run separate xjoin-validation pod
-	err := i.Client.Create(i.Context, &v1.Pod{
+func (i *XJoinIndexValidatorIteration) createValidationJob(
+	dbConnectionEnvVars []v1.EnvVar, certificates components.CertificateVolumes, fullAvroSchema string) error {
+	workload, err := components.ResolveWorkload(
+		components.DefaultValidatorWorkload(), i.Parameters.ValidatorWorkload.String(), i.GetInstance().Spec.Workload)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	var resources v1.ResourceRequirements
+	if workload.Resources != nil {
+		resources = *workload.Resources
+	}
+
+	labels := i.validationJobLabels()
+	controller := true
+	blockOwnerDeletion := true
+	deadline := int64(i.Parameters.ValidationJobDeadline.Int())
+	backoffLimit := int32(i.Parameters.ValidationJobBackoffLimit.Int())
+	ttl := int32(i.Parameters.ValidationJobTTL.Int())
+
+	//run separate xjoin-validation job
+	//the finished jobs are kept until their ttl passes, so each job has a new name
+	err = i.Client.Create(i.Context, &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      i.ValidationPodName(),
-			Namespace: i.Instance.GetNamespace(),
-			Labels: map[string]string{
-				"xjoin.index":               i.Instance.GetName(),
-				common.COMPONENT_NAME_LABEL: "XJoinIndexValidator",
-			},
-			//OwnerReferences: nil,
+			GenerateName: i.ValidationPodName() + "-",
+			Namespace:    i.Instance.GetNamespace(),
+			Labels:       labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         common.IndexValidatorGVK.GroupVersion().String(),
+				Kind:               common.IndexValidatorGVK.Kind,
+				Name:               i.Instance.GetName(),
+				UID:                i.Instance.GetUID(),
+				Controller:         &controller,
+				BlockOwnerDeletion: &blockOwnerDeletion,
+			}},
 		},
-		Spec: v1.PodSpec{
-			RestartPolicy: "Never",
-			Containers: []v1.Container{{
-				Name:  i.ValidationPodName(),
-				Image: "quay.io/cloudservices/xjoin-validation:latest",
-				Env: append(dbConnectionEnvVars, []v1.EnvVar{{
-					Name: "ELASTICSEARCH_HOST_URL",
-					ValueFrom: &v1.EnvVarSource{
-						SecretKeyRef: &v1.SecretKeySelector{
-							LocalObjectReference: v1.LocalObjectReference{
-								Name: "xjoin-elasticsearch",
+		Spec: batchv1.JobSpec{
+			ActiveDeadlineSeconds:   &deadline,
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					RestartPolicy: "Never",
+					NodeSelector:  workload.NodeSelector,
+					Tolerations:   workload.Tolerations,
+					Volumes:       certificates.Volumes,
+					Containers: []v1.Container{{
+						Name:  i.ValidationPodName(),
+						Image: workload.ImageName(),
+						Env: append(append(dbConnectionEnvVars, []v1.EnvVar{{
+							Name: "ELASTICSEARCH_HOST_URL",
+							ValueFrom: &v1.EnvVarSource{
+								SecretKeyRef: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{
+										Name: "xjoin-elasticsearch",
+									},
+									Key: "endpoint",
+								},
 							},
-							Key: "endpoint",
-						},
-					},
-				}, {
-					Name: "ELASTICSEARCH_USERNAME",
-					ValueFrom: &v1.EnvVarSource{
-						SecretKeyRef: &v1.SecretKeySelector{
-							LocalObjectReference: v1.LocalObjectReference{
-								Name: "xjoin-elasticsearch",
+						}, {
+							Name: "ELASTICSEARCH_USERNAME",
+							ValueFrom: &v1.EnvVarSource{
+								SecretKeyRef: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{
+										Name: "xjoin-elasticsearch",
+									},
+									Key: "username",
+								},
 							},
-							Key: "username",
-						},
-					},
-				}, {
-					Name: "ELASTICSEARCH_PASSWORD",
-					ValueFrom: &v1.EnvVarSource{
-						SecretKeyRef: &v1.SecretKeySelector{
-							LocalObjectReference: v1.LocalObjectReference{
-								Name: "xjoin-elasticsearch",
+						}, {
+							Name: "ELASTICSEARCH_PASSWORD",
+							ValueFrom: &v1.EnvVarSource{
+								SecretKeyRef: &v1.SecretKeySelector{
+									LocalObjectReference: v1.LocalObjectReference{
+										Name: "xjoin-elasticsearch",
+									},
+									Key: "password",
+								},
 							},
-							Key: "password",
-						},
-					},
-				}, {
-					Name:  "ELASTICSEARCH_INDEX",
-					Value: i.ElasticsearchIndexName,
-				}, {
-					Name:  "FULL_AVRO_SCHEMA",
-					Value: fullAvroSchema,
-				}}...),
-				ImagePullPolicy: "Always",
-			}},
+						}, {
+							Name:  "ELASTICSEARCH_INDEX",
+							Value: i.ElasticsearchIndexName,
+						}, {
+							Name:  "FULL_AVRO_SCHEMA",
+							Value: fullAvroSchema,
+						}}...), workload.Env...),
+						ImagePullPolicy:          workload.ImagePullPolicy,
+						Resources:                resources,
+						TerminationMessagePolicy: v1.TerminationMessageFallbackToLogsOnError,
+						VolumeMounts:             certificates.VolumeMounts,
+					}},
+				},
+			},
 		},
 	})
 	if err != nil {
@@ -6,10 +6,15 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers"
+	"io"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"net/http"
+	"os"
+	"regexp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -17,9 +22,11 @@ import (
 )
 
 type IndexTestReconciler struct {
-	Namespace string
-	Name      string
-	K8sClient client.Client
+	Namespace      string
+	Name           string
+	ConfigFileName string
+	K8sClient      client.Client
+	DataSources    []DataSource
 }
 
 func (i *IndexTestReconciler) ReconcileNew() v1alpha1.XJoinIndex {
@@ -50,6 +57,186 @@ func (i *IndexTestReconciler) ReconcileNew() v1alpha1.XJoinIndex {
 	return *createdIndex
 }
 
+func (i *IndexTestReconciler) ReconcileUpdated() v1alpha1.XJoinIndex {
+	i.registerNewMocks()
+	result := i.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	updatedIndex := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+
+	Eventually(func() bool {
+		err := i.K8sClient.Get(context.Background(), indexLookupKey, updatedIndex)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+	return *updatedIndex
+}
+
+// SetDataSources records the datasources referenced by the index the same way the XJoinIndexPipeline controller does
+func (i *IndexTestReconciler) SetDataSources(dataSources map[string]v1alpha1.XJoinIndexDataSource) {
+	index := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, index)
+	checkError(err)
+
+	index.Status.DataSourceVersions = dataSources
+	err = i.K8sClient.Status().Update(context.Background(), index)
+	checkError(err)
+}
+
+// SetLegacyDataSources records the datasources' resource versions the way previous releases did
+func (i *IndexTestReconciler) SetLegacyDataSources(dataSources map[string]string) {
+	index := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, index)
+	checkError(err)
+
+	index.Status.DataSources = dataSources
+	index.Status.DataSourceVersions = nil
+	err = i.K8sClient.Status().Update(context.Background(), index)
+	checkError(err)
+}
+
+// SetActiveVersion promotes a version of the index without waiting for it to be validated
+func (i *IndexTestReconciler) SetActiveVersion(version string) {
+	index := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, index)
+	checkError(err)
+
+	index.Status.ActiveVersion = version
+	index.Status.RefreshingVersion = ""
+	err = i.K8sClient.Status().Update(context.Background(), index)
+	checkError(err)
+}
+
+// SetAvroSchema updates the index's avroSchema to the schema in a test data file
+func (i *IndexTestReconciler) SetAvroSchema(configFileName string) {
+	index := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, index)
+	checkError(err)
+
+	index.Spec.AvroSchema = readAvroSchema(configFileName)
+	err = i.K8sClient.Update(context.Background(), index)
+	checkError(err)
+}
+
+// SetPipelineCondition sets a condition on the XJoinIndexPipeline of a version the same way the XJoinIndexPipeline
+// controller does
+func (i *IndexTestReconciler) SetPipelineCondition(version string, condition metav1.Condition) {
+	pipeline := &v1alpha1.XJoinIndexPipeline{}
+	pipelineLookupKey := types.NamespacedName{Name: i.Name + "." + version, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), pipelineLookupKey, pipeline)
+	checkError(err)
+
+	meta.SetStatusCondition(&pipeline.Status.Conditions, condition)
+	err = i.K8sClient.Status().Update(context.Background(), pipeline)
+	checkError(err)
+}
+
+// SetWorkloads updates the index's workloads
+func (i *IndexTestReconciler) SetWorkloads(workloads *v1alpha1.XJoinIndexWorkloads) {
+	index := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, index)
+	checkError(err)
+
+	index.Spec.Workloads = workloads
+	err = i.K8sClient.Update(context.Background(), index)
+	checkError(err)
+}
+
+// SetValidation updates the index's validation thresholds
+func (i *IndexTestReconciler) SetValidation(validation *v1alpha1.XJoinIndexValidation) {
+	index := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, index)
+	checkError(err)
+
+	index.Spec.Validation = validation
+	err = i.K8sClient.Update(context.Background(), index)
+	checkError(err)
+}
+
+// SetPipelineValidation records a validation result on the XJoinIndexPipeline of a version the same way the
+// XJoinIndexValidator does
+func (i *IndexTestReconciler) SetPipelineValidation(version string, result string, failedCount int) {
+	pipeline := &v1alpha1.XJoinIndexPipeline{}
+	pipelineLookupKey := types.NamespacedName{Name: i.Name + "." + version, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), pipelineLookupKey, pipeline)
+	checkError(err)
+
+	pipeline.Status.ValidationResponse.Result = result
+	pipeline.Status.ValidationFailedCount = failedCount
+	pipeline.Status.ValidationReport = "xjoin-validation-report-" + pipeline.Name
+	pipeline.Status.LastValidationTime = validationTime()
+	err = i.K8sClient.Status().Update(context.Background(), pipeline)
+	checkError(err)
+}
+
+// validationTime is the time of a validation result recorded by a test. It is a second in the future because
+// metav1.Time is truncated to seconds, so the result is recorded after anything the test did before.
+func validationTime() *metav1.Time {
+	validationTime := metav1.NewTime(time.Now().Add(time.Second))
+	return &validationTime
+}
+
+// ReconcileWithAnnotation sets an action annotation on the index and reconciles it while the alias points to the
+// active version's Elasticsearch index
+func (i *IndexTestReconciler) ReconcileWithAnnotation(annotation string, value string) v1alpha1.XJoinIndex {
+	index := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, index)
+	checkError(err)
+
+	index.SetAnnotations(map[string]string{annotation: value})
+	err = i.K8sClient.Update(context.Background(), index)
+	checkError(err)
+
+	updatedIndex, _ := i.ReconcileAlias("xjoinindexpipeline." + i.Name + "." + index.Status.ActiveVersion)
+	Expect(updatedIndex.GetAnnotations()).ToNot(HaveKey(annotation))
+	Expect(updatedIndex.Status.LastAction).ToNot(BeNil())
+	Expect(updatedIndex.Status.LastAction.Annotation).To(Equal(annotation))
+	Expect(updatedIndex.Status.LastAction.Value).To(Equal(value))
+
+	return updatedIndex
+}
+
+// ReconcileAlias reconciles the index while its alias points to currentIndex and returns the body of the
+// request that moved the alias
+func (i *IndexTestReconciler) ReconcileAlias(currentIndex string) (v1alpha1.XJoinIndex, string) {
+	i.registerNewMocks()
+
+	alias := "xjoinindex." + i.Name
+	httpmock.RegisterResponder(
+		"GET",
+		"http://localhost:9200/_cat/aliases/"+alias+"?format=JSON",
+		httpmock.NewStringResponder(200, `[{"alias":"`+alias+`","index":"`+currentIndex+`"}]`))
+
+	var updateAliasBody string
+	httpmock.RegisterResponder(
+		"POST",
+		"http://localhost:9200/_aliases",
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			checkError(err)
+			updateAliasBody = string(body)
+			return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+		})
+
+	result := i.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+
+	updatedIndex := &v1alpha1.XJoinIndex{}
+	indexLookupKey := types.NamespacedName{Name: i.Name, Namespace: i.Namespace}
+	err := i.K8sClient.Get(context.Background(), indexLookupKey, updatedIndex)
+	checkError(err)
+
+	return *updatedIndex, updateAliasBody
+}
+
 func (i *IndexTestReconciler) ReconcileDelete() {
 	i.registerDeleteMocks()
 	result := i.reconcile()
@@ -79,8 +266,13 @@ func (i *IndexTestReconciler) createValidIndex() {
 		Image: "test",
 	}}
 
+	avroSchema := "{}"
+	if i.ConfigFileName != "" {
+		avroSchema = readAvroSchema(i.ConfigFileName)
+	}
+
 	indexSpec := v1alpha1.XJoinIndexSpec{
-		AvroSchema:           "{}",
+		AvroSchema:           avroSchema,
 		Pause:                false,
 		CustomSubgraphImages: customSubgraphImage,
 	}
@@ -108,7 +300,7 @@ func (i *IndexTestReconciler) createValidIndex() {
 		return err == nil
 	}, 10*time.Second, 100*time.Millisecond).Should(BeTrue())
 	Expect(createdIndex.Spec.Pause).Should(Equal(false))
-	Expect(createdIndex.Spec.AvroSchema).Should(Equal("{}"))
+	Expect(createdIndex.Spec.AvroSchema).Should(Equal(avroSchema))
 	Expect(createdIndex.Spec.CustomSubgraphImages).Should(Equal(customSubgraphImage))
 }
 
@@ -145,6 +337,26 @@ func (i *IndexTestReconciler) registerNewMocks() {
 		"GET",
 		"http://localhost:9200/_cat/indices/xjoinindex."+i.Name+".%2A?format=JSON&h=index",
 		httpmock.NewStringResponder(200, "[]"))
+
+	for _, dataSource := range i.DataSources {
+		response, err := os.ReadFile("./test/data/apicurio/" + dataSource.ApiCurioResponseFilename + ".json")
+		checkError(err)
+		httpmock.RegisterResponder(
+			"GET",
+			"http://apicurio:1080/apis/ccompat/v6/subjects/xjoindatasourcepipeline."+dataSource.Name+"."+dataSource.Version+"-value/versions/latest",
+			httpmock.NewStringResponder(200, string(response)))
+	}
+
+	httpmock.RegisterRegexpResponder(
+		"POST",
+		regexp.MustCompile(`^http://apicurio:1080/apis/ccompat/v6/compatibility/subjects/xjoinindexpipeline\.`+i.Name+`\..*-value/versions/latest$`),
+		httpmock.NewStringResponder(200, `{"is_compatible":true}`))
+}
+
+func readAvroSchema(configFileName string) string {
+	avroSchema, err := os.ReadFile("./test/data/avro/" + configFileName + ".json")
+	checkError(err)
+	return string(avroSchema)
 }
 
 func (i *IndexTestReconciler) registerDeleteMocks() {
@@ -0,0 +1,146 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/go-errors/errors"
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ValidationRecordedAnnotation marks a finished validation Job whose result has been stored. The Job is kept until
+// its ttlSecondsAfterFinished passes, so failed Jobs can be inspected.
+const ValidationRecordedAnnotation = "xjoin.cloud.redhat.com/validation-recorded"
+
+// PendingValidationJob is the newest validation Job matching labels whose result has not been recorded yet.
+// It is nil when every Job has been recorded.
+func PendingValidationJob(
+	ctx context.Context, c client.Client, namespace string, labels client.MatchingLabels) (*batchv1.Job, error) {
+
+	jobs := &batchv1.JobList{}
+	err := c.List(ctx, jobs, client.InNamespace(namespace), labels)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	var pending *batchv1.Job
+	for index := range jobs.Items {
+		job := &jobs.Items[index]
+		if _, recorded := job.GetAnnotations()[ValidationRecordedAnnotation]; recorded {
+			continue
+		}
+		if pending == nil || pending.CreationTimestamp.Before(&job.CreationTimestamp) {
+			pending = job
+		}
+	}
+	return pending, nil
+}
+
+// RecordValidationJob marks the result of a finished validation Job as stored, so the next validation starts a new Job
+func RecordValidationJob(ctx context.Context, c client.Client, job *batchv1.Job) error {
+	patch := client.MergeFrom(job.DeepCopy())
+	annotations := job.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ValidationRecordedAnnotation] = "true"
+	job.SetAnnotations(annotations)
+
+	err := c.Patch(ctx, job, patch)
+	if err != nil && !k8errors.IsNotFound(err) {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// JobCondition is the job's condition of conditionType when it is true
+func JobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+			return &condition
+		}
+	}
+	return nil
+}
+
+func jobFinished(job *batchv1.Job) bool {
+	return job.Status.Succeeded > 0 || JobCondition(job, batchv1.JobFailed) != nil
+}
+
+// ValidationJobFinishedPredicate only passes the update that finishes an owned validation Job, so the validator
+// reconciles once a result is available instead of polling the Job
+func ValidationJobFinishedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldJob, oldOk := e.ObjectOld.(*batchv1.Job)
+			newJob, newOk := e.ObjectNew.(*batchv1.Job)
+			return oldOk && newOk && !jobFinished(oldJob) && jobFinished(newJob)
+		},
+	}
+}
+
+// ParseValidationJobResponse reads the validation result from the termination message of the validation job's pod.
+// The pod's logs are used when the termination message is empty.
+func ParseValidationJobResponse(ctx context.Context, c client.Client, logReader LogReader, job *batchv1.Job) (
+	response validation.ValidationResponse, err error) {
+
+	labels := client.MatchingLabels{"job-name": job.Name}
+	podList := &v1.PodList{}
+	err = c.List(ctx, podList, client.InNamespace(job.Namespace), labels)
+	if err != nil {
+		return response, errors.Wrap(err, 0)
+	}
+
+	var pod *v1.Pod
+	for index := range podList.Items {
+		if podList.Items[index].Status.Phase == v1.PodSucceeded {
+			pod = &podList.Items[index]
+		}
+	}
+	if pod == nil {
+		return response, errors.Wrap(errors.New("no succeeded pod found for validation job "+job.Name), 0)
+	}
+
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.State.Terminated != nil && strings.TrimSpace(containerStatus.State.Terminated.Message) != "" {
+			return ParseValidationResult(containerStatus.State.Terminated.Message)
+		}
+	}
+
+	logString, err := logReader.GetLogs(pod.Name, job.Namespace)
+	if err != nil {
+		return response, errors.Wrap(err, 0)
+	}
+	return ParseValidationResult(logString)
+}
+
+// ParseValidationResult returns the last line of the validator's output that is a validation response
+func ParseValidationResult(output string) (response validation.ValidationResponse, err error) {
+	lines := strings.Split(output, "\n")
+	for index := len(lines) - 1; index >= 0; index-- {
+		line := strings.TrimSpace(lines[index])
+		if line == "" {
+			continue
+		}
+
+		var lineResponse validation.ValidationResponse
+		if json.Unmarshal([]byte(line), &lineResponse) == nil && lineResponse.Result != "" {
+			return lineResponse, nil
+		}
+	}
+
+	if len(output) > 200 {
+		output = "..." + output[len(output)-200:]
+	}
+	return response, errors.Wrap(errors.New("malformed validation result, no validation response found in: "+output), 0)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/go-errors/errors"
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"net/http"
@@ -15,12 +16,12 @@ import (
 	"time"
 )
 
-func (kafka *GenericKafka) CreateGenericDebeziumConnector(
-	name string, connectorTemplate string, connectorTemplateParameters map[string]interface{}) error {
+func (kafka *GenericKafka) newGenericDebeziumConnectorResource(name string, connectorClass string,
+	connectorTemplate string, connectorTemplateParameters map[string]interface{}) (*unstructured.Unstructured, error) {
 
 	connectorConfig, err := kafka.parseConnectorTemplate(connectorTemplate, connectorTemplateParameters)
 	if err != nil {
-		return errors.Wrap(err, 0)
+		return nil, errors.Wrap(err, 0)
 	}
 	connectorObj := &unstructured.Unstructured{}
 	connectorObj.Object = map[string]interface{}{
@@ -32,7 +33,7 @@ func (kafka *GenericKafka) CreateGenericDebeziumConnector(
 			},
 		},
 		"spec": map[string]interface{}{
-			"class":    "io.debezium.connector.postgresql.PostgresConnector",
+			"class":    connectorClass,
 			"config":   connectorConfig,
 			"pause":    false,
 			"tasksMax": 1,
@@ -40,6 +41,17 @@ func (kafka *GenericKafka) CreateGenericDebeziumConnector(
 	}
 
 	connectorObj.SetGroupVersionKind(connectorGVK)
+	return connectorObj, nil
+}
+
+func (kafka *GenericKafka) CreateGenericDebeziumConnector(name string, connectorClass string,
+	connectorTemplate string, connectorTemplateParameters map[string]interface{}) error {
+
+	connectorObj, err := kafka.newGenericDebeziumConnectorResource(
+		name, connectorClass, connectorTemplate, connectorTemplateParameters)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
 
 	err = kafka.Client.Create(kafka.Context, connectorObj)
 	if err != nil {
@@ -49,12 +61,25 @@ func (kafka *GenericKafka) CreateGenericDebeziumConnector(
 	return nil
 }
 
-func (kafka *GenericKafka) CreateGenericElasticsearchConnector(
-	name string, connectorTemplate string, connectorTemplateParameters map[string]interface{}) error {
+// CheckGenericDebeziumConnectorDeviation compares the existing Debezium connector with the connector that would be created now
+func (kafka *GenericKafka) CheckGenericDebeziumConnectorDeviation(name string, connectorClass string,
+	connectorTemplate string, connectorTemplateParameters map[string]interface{}) (problem error, err error) {
+
+	connectorObj, err := kafka.newGenericDebeziumConnectorResource(
+		name, connectorClass, connectorTemplate, connectorTemplateParameters)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	return kafka.checkGenericConnectorDeviation(connectorObj)
+}
+
+func (kafka *GenericKafka) newGenericElasticsearchConnectorResource(
+	name string, connectorTemplate string, connectorTemplateParameters map[string]interface{}) (*unstructured.Unstructured, error) {
 
 	connectorConfig, err := kafka.parseConnectorTemplate(connectorTemplate, connectorTemplateParameters)
 	if err != nil {
-		return errors.Wrap(err, 0)
+		return nil, errors.Wrap(err, 0)
 	}
 
 	connectorObj := &unstructured.Unstructured{}
@@ -74,6 +99,16 @@ func (kafka *GenericKafka) CreateGenericElasticsearchConnector(
 	}
 
 	connectorObj.SetGroupVersionKind(connectorGVK)
+	return connectorObj, nil
+}
+
+func (kafka *GenericKafka) CreateGenericElasticsearchConnector(
+	name string, connectorTemplate string, connectorTemplateParameters map[string]interface{}) error {
+
+	connectorObj, err := kafka.newGenericElasticsearchConnectorResource(name, connectorTemplate, connectorTemplateParameters)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
 
 	err = kafka.Client.Create(kafka.Context, connectorObj)
 	if err != nil {
@@ -83,6 +118,72 @@ func (kafka *GenericKafka) CreateGenericElasticsearchConnector(
 	return nil
 }
 
+// CheckGenericElasticsearchConnectorDeviation compares the existing Elasticsearch connector with the connector that would be created now
+func (kafka *GenericKafka) CheckGenericElasticsearchConnectorDeviation(
+	name string, connectorTemplate string, connectorTemplateParameters map[string]interface{}) (problem error, err error) {
+
+	connectorObj, err := kafka.newGenericElasticsearchConnectorResource(name, connectorTemplate, connectorTemplateParameters)
+	if err != nil {
+		return nil, errors.Wrap(err, 0)
+	}
+
+	return kafka.checkGenericConnectorDeviation(connectorObj)
+}
+
+func (kafka *GenericKafka) checkGenericConnectorDeviation(newConnector *unstructured.Unstructured) (problem error, err error) {
+	connectorName := newConnector.GetName()
+	connector, err := kafka.GetConnector(connectorName)
+	if err != nil {
+		if k8errors.IsNotFound(err) {
+			return fmt.Errorf("connector %s not found in %s", connectorName, kafka.ConnectNamespace), nil
+		}
+		return nil, errors.Wrap(err, 0)
+	}
+
+	if connector.GetLabels()[LabelStrimziCluster] != kafka.ConnectCluster {
+		return fmt.Errorf(
+			"connector %s connectCluster changed from %s to %s",
+			connectorName,
+			connector.GetLabels()[LabelStrimziCluster],
+			kafka.ConnectCluster), nil
+	}
+
+	currentSpec, _ := connector.UnstructuredContent()["spec"].(map[string]interface{})
+	newSpec, _ := newConnector.UnstructuredContent()["spec"].(map[string]interface{})
+
+	if currentSpec["class"] != newSpec["class"] {
+		return fmt.Errorf(
+			"connector %s class changed from %v to %v", connectorName, currentSpec["class"], newSpec["class"]), nil
+	}
+
+	currentConfig, _ := currentSpec["config"].(map[string]interface{})
+	newConfig, _ := newSpec["config"].(map[string]interface{})
+	changedKeys := k8sUtils.ChangedKeys(currentConfig, newConfig)
+	changedKeys = withoutAddedKeys(changedKeys, currentConfig, unrefreshedConfigKeys)
+	if len(changedKeys) > 0 {
+		//only the keys are reported, the values contain credentials
+		return fmt.Errorf("connector %s configuration has changed: %s",
+			connectorName, strings.Join(changedKeys, ", ")), nil
+	}
+
+	return nil, nil
+}
+
+// unrefreshedConfigKeys were added to the connector templates after connectors were created with them. Connectors
+// without the key are not a deviation, they get it when they are recreated by the next refresh.
+var unrefreshedConfigKeys = []string{"publication.name", "column.include.list", "decimal.handling.mode"}
+
+// withoutAddedKeys removes the keys that are missing from currentConfig, i.e. that were added
+func withoutAddedKeys(changedKeys []string, currentConfig map[string]interface{}, addedKeys []string) (keys []string) {
+	for _, key := range changedKeys {
+		if _, ok := currentConfig[key]; !ok && utils.ContainsString(addedKeys, key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return
+}
+
 func (kafka *GenericKafka) parseConnectorTemplate(connectorTemplate string, connectorTemplateParameters map[string]interface{}) (interface{}, error) {
 	tmpl, err := template.New("configTemplate").Parse(connectorTemplate)
 	if err != nil {
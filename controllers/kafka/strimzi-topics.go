@@ -8,6 +8,7 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/google/go-cmp/cmp"
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -235,7 +236,7 @@ func (t *StrimziTopics) CheckDeviation(pipelineVersion string) (problem error, e
 	return nil, nil
 }
 
-func (t *StrimziTopics) CreateGenericTopic(topicName string, topicParameters TopicParameters) error {
+func (t *StrimziTopics) newGenericTopicResource(topicName string, topicParameters TopicParameters) *unstructured.Unstructured {
 	topic := &unstructured.Unstructured{}
 	topic.Object = map[string]interface{}{
 		"metadata": map[string]interface{}{
@@ -260,7 +261,11 @@ func (t *StrimziTopics) CreateGenericTopic(topicName string, topicParameters Top
 	}
 
 	topic.SetGroupVersionKind(topicGroupVersionKind)
+	return topic
+}
 
+func (t *StrimziTopics) CreateGenericTopic(topicName string, topicParameters TopicParameters) error {
+	topic := t.newGenericTopicResource(topicName, topicParameters)
 	err := t.Client.Create(t.Context, topic)
 	if err != nil {
 		return errors.Wrap(err, 0)
@@ -321,6 +326,50 @@ func (t *StrimziTopics) CreateGenericTopic(topicName string, topicParameters Top
 	return nil
 }
 
+// CheckGenericTopicDeviation compares the existing KafkaTopic with the KafkaTopic that would be created now
+func (t *StrimziTopics) CheckGenericTopicDeviation(topicName string, topicParameters TopicParameters) (problem error, err error) {
+	topic, err := t.GetTopic(topicName)
+	if err != nil {
+		if k8errors.IsNotFound(err) {
+			return fmt.Errorf("topic %s not found in %s", topicName, t.KafkaClusterNamespace), nil
+		}
+		return nil, errors.Wrap(err, 0)
+	}
+	topicObj := topic.(*unstructured.Unstructured)
+
+	if topicObj.GetLabels()[LabelStrimziCluster] != t.KafkaCluster {
+		return fmt.Errorf(
+			"topic %s KafkaCluster changed from %s to %s",
+			topicName,
+			topicObj.GetLabels()[LabelStrimziCluster],
+			t.KafkaCluster), nil
+	}
+
+	newTopic := t.newGenericTopicResource(topicName, topicParameters)
+
+	currentSpec, _ := topicObj.UnstructuredContent()["spec"].(map[string]interface{})
+	newSpec, _ := newTopic.UnstructuredContent()["spec"].(map[string]interface{})
+
+	//the changes of the topic's config are reported per config key
+	var changedFields []string
+	for _, field := range k8sUtils.ChangedKeys(currentSpec, newSpec) {
+		if field != "config" {
+			changedFields = append(changedFields, field)
+			continue
+		}
+		currentConfig, _ := currentSpec["config"].(map[string]interface{})
+		newConfig, _ := newSpec["config"].(map[string]interface{})
+		for _, configKey := range k8sUtils.ChangedKeys(currentConfig, newConfig) {
+			changedFields = append(changedFields, "config."+configKey)
+		}
+	}
+	if len(changedFields) > 0 {
+		return fmt.Errorf("topic %s spec has changed: %s", topicName, strings.Join(changedFields, ", ")), nil
+	}
+
+	return nil, nil
+}
+
 func (t *StrimziTopics) DeleteTopic(topicName string) error {
 	if topicName == "" {
 		return nil
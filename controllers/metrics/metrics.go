@@ -74,6 +74,16 @@ var (
 		Name: "xjoin_stale_resource_count",
 		Help: "The number of stale resources found during each reconcile loop",
 	}, []string{})
+
+	replicationSlotRetainedWAL = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "xjoin_datasource_replication_slot_retained_wal_bytes",
+		Help: "The WAL retained by the replication slot of a datasource's Debezium connector",
+	}, []string{"datasource", "version"})
+
+	replicationSlotConfirmedFlushLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "xjoin_datasource_replication_slot_confirmed_flush_lag_bytes",
+		Help: "The WAL not yet confirmed by the Debezium connector of a datasource",
+	}, []string{"datasource", "version"})
 )
 
 type RefreshReason string
@@ -97,7 +107,9 @@ func Init() {
 		refreshCount,
 		connectorTaskRestartCount,
 		connectRestartCount,
-		staleResourceCount)
+		staleResourceCount,
+		replicationSlotRetainedWAL,
+		replicationSlotConfirmedFlushLag)
 }
 
 func InitLabels() {
@@ -125,6 +137,16 @@ func StaleResourceCount(resources []string) {
 	}
 }
 
+func ReplicationSlotLag(datasource string, version string, retainedWAL int64, confirmedFlushLag int64) {
+	replicationSlotRetainedWAL.WithLabelValues(datasource, version).Set(float64(retainedWAL))
+	replicationSlotConfirmedFlushLag.WithLabelValues(datasource, version).Set(float64(confirmedFlushLag))
+}
+
+func DeleteReplicationSlotLag(datasource string, version string) {
+	replicationSlotRetainedWAL.DeleteLabelValues(datasource, version)
+	replicationSlotConfirmedFlushLag.DeleteLabelValues(datasource, version)
+}
+
 func ConnectRestarted() {
 	connectRestartCount.WithLabelValues().Inc()
 }
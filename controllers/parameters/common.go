@@ -24,6 +24,8 @@ type CommonParameters struct {
 	SchemaRegistryHost           Parameter
 	SchemaRegistryPort           Parameter
 	AvroSchema                   Parameter
+	PreviousVersionRetention     Parameter //period to keep the previous version after a refresh (seconds)
+	ValidatorWorkload            Parameter //JSON WorkloadSpec with the defaults for the xjoin-validation pods
 }
 
 func BuildCommonParameters() CommonParameters {
@@ -145,6 +147,22 @@ func BuildCommonParameters() CommonParameters {
 			SpecKey:      "AvroSchema",
 			DefaultValue: "{}",
 		},
+
+		//refresh
+		PreviousVersionRetention: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "previous.version.retention",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  0,
+		},
+
+		//workloads
+		ValidatorWorkload: Parameter{
+			Type:          reflect.String,
+			ConfigMapKey:  "xjoin.validation.workload",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  "{}",
+		},
 	}
 
 	return p
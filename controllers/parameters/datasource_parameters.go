@@ -7,20 +7,35 @@ import (
 
 type DataSourceParameters struct {
 	CommonParameters
-	DatabaseHostname          Parameter
-	DatabasePort              Parameter
-	DatabaseName              Parameter
-	DatabaseTable             Parameter
-	DatabaseUsername          Parameter
-	DatabasePassword          Parameter
-	DatabaseSSLMode           Parameter
-	DatabaseSSLRootCert       Parameter
-	DebeziumConnectorTemplate Parameter
-	DebeziumTasksMax          Parameter
-	DebeziumMaxBatchSize      Parameter
-	DebeziumQueueSize         Parameter
-	DebeziumPollIntervalMS    Parameter
-	DebeziumErrorsLogEnable   Parameter
+	DatabaseHostname           Parameter
+	DatabasePort               Parameter
+	DatabaseName               Parameter
+	DatabaseTable              Parameter
+	DatabaseType               Parameter
+	DatabaseSignalTable        Parameter
+	DatabaseUsername           Parameter
+	DatabasePassword           Parameter
+	DatabaseSSLMode            Parameter
+	DatabaseSSLRootCert        Parameter
+	DatabaseSSLCert            Parameter
+	DatabaseSSLKey             Parameter
+	DebeziumConnectorTemplate  Parameter //postgres connector
+	DebeziumMySQLTemplate      Parameter
+	DebeziumSQLServerTemplate  Parameter
+	DebeziumTasksMax           Parameter
+	DebeziumMaxBatchSize       Parameter
+	DebeziumQueueSize          Parameter
+	DebeziumPollIntervalMS     Parameter
+	DebeziumErrorsLogEnable    Parameter
+	DebeziumSnapshotChunkSize  Parameter //rows read per chunk of an incremental snapshot
+	IncrementalSnapshotTimeout Parameter //time allowed for an incremental snapshot to complete (seconds)
+	ReplicationLagThreshold    Parameter //retained WAL bytes above which the ReplicationLagHigh condition is set
+	ReplicationLagLimit        Parameter //retained WAL bytes above which the replication slot is dropped, 0 disables it
+	KafkaBootstrapURL          Parameter
+	ValidationInterval         Parameter //period between validation checks (seconds)
+	ValidationJobDeadline      Parameter //activeDeadlineSeconds of the validation Job
+	ValidationJobBackoffLimit  Parameter //number of retries of a failed validation pod
+	ValidationJobTTL           Parameter //ttlSecondsAfterFinished of the validation Job
 }
 
 func BuildDataSourceParameters() *DataSourceParameters {
@@ -47,6 +62,16 @@ func BuildDataSourceParameters() *DataSourceParameters {
 			SpecKey:      "DatabaseTable",
 			DefaultValue: "public.hosts",
 		},
+		DatabaseType: Parameter{
+			Type:         reflect.String,
+			SpecKey:      "DatabaseType",
+			DefaultValue: "postgres",
+		},
+		DatabaseSignalTable: Parameter{
+			Type:         reflect.String,
+			SpecKey:      "DatabaseSignalTable",
+			DefaultValue: "",
+		},
 		DatabaseUsername: Parameter{
 			Type:         reflect.String,
 			SpecKey:      "DatabaseUsername",
@@ -59,16 +84,28 @@ func BuildDataSourceParameters() *DataSourceParameters {
 		},
 		DatabaseSSLMode: Parameter{
 			Type:          reflect.String,
+			SpecKey:       "DatabaseSSLMode",
 			DefaultValue:  "disable",
 			ConfigMapName: "xjoin-generic",
 			ConfigMapKey:  "db.ssl.mode",
 		},
 		DatabaseSSLRootCert: Parameter{
 			Type:          reflect.String,
+			SpecKey:       "DatabaseSSLRootCert",
 			ConfigMapName: "xjoin-generic",
 			ConfigMapKey:  "hbi.db.ssl.root.cert",
 			DefaultValue:  "/opt/kafka/external-configuration/rds-client-ca/rds-cacert",
 		},
+		DatabaseSSLCert: Parameter{
+			Type:         reflect.String,
+			SpecKey:      "DatabaseSSLCert",
+			DefaultValue: "",
+		},
+		DatabaseSSLKey: Parameter{
+			Type:         reflect.String,
+			SpecKey:      "DatabaseSSLKey",
+			DefaultValue: "",
+		},
 
 		//debezium
 		DebeziumConnectorTemplate: Parameter{
@@ -85,14 +122,122 @@ func BuildDataSourceParameters() *DataSourceParameters {
 				"database.server.name": "{{.DatabaseServerName}}",
 				"database.sslmode": "{{.DatabaseSSLMode}}",
 				"database.sslrootcert": "{{.DatabaseSSLRootCert}}",
+				{{if .DatabaseSSLCert}}"database.sslcert": "{{.DatabaseSSLCert}}",{{end}}
+				{{if .DatabaseSSLKey}}"database.sslkey": "{{.DatabaseSSLKey}}",{{end}}
 				"table.whitelist": "{{.DatabaseTable}}",
+				{{if .ColumnIncludeList}}"column.include.list": "{{.ColumnIncludeList}}",{{end}}
+				{{if .DecimalHandlingMode}}"decimal.handling.mode": "{{.DecimalHandlingMode}}",{{end}}
+				{{if .SnapshotSelectStatement}}"snapshot.select.statement.overrides": "{{.DatabaseTable}}",
+				"snapshot.select.statement.overrides.{{.DatabaseTable}}": "{{.SnapshotSelectStatement}}",{{end}}
+				{{if .DatabaseSignalTable}}"signal.data.collection": "{{.DatabaseSignalTable}}",
+				"incremental.snapshot.chunk.size": {{.DebeziumSnapshotChunkSize}},{{end}}
 				"plugin.name": "pgoutput",
-				"transforms": "unwrap, reroute",
+				"transforms": "unwrap{{if .RowFilterCondition}}, filter{{end}}, reroute",
 				"transforms.unwrap.type": "io.debezium.transforms.ExtractNewRecordState",
 				"transforms.unwrap.delete.handling.mode": "rewrite",
+				{{if .RowFilterCondition}}"transforms.filter.type": "io.debezium.transforms.Filter",
+				"transforms.filter.language": "jsr223.groovy",
+				"transforms.filter.condition": "{{.RowFilterCondition}}",{{end}}
 				"errors.log.enable": {{.DebeziumErrorsLogEnable}},
 				"errors.log.include.messages": true,
 				"slot.name": "{{.ReplicationSlotName}}",
+				"publication.name": "{{.PublicationName}}",
+				"max.queue.size": {{.DebeziumQueueSize}},
+				"max.batch.size": {{.DebeziumMaxBatchSize}},
+				"poll.interval.ms": {{.DebeziumPollIntervalMS}},
+				"key.converter": "io.apicurio.registry.utils.converter.AvroConverter",
+				"key.converter.apicurio.registry.url": "{{.SchemaRegistryProtocol}}://{{.SchemaRegistryHost}}:{{.SchemaRegistryPort}}/apis/registry/v2",
+				"key.converter.apicurio.registry.auto-register": "true",
+				"value.converter": "io.apicurio.registry.utils.converter.AvroConverter",
+				"value.converter.apicurio.registry.url": "{{.SchemaRegistryProtocol}}://{{.SchemaRegistryHost}}:{{.SchemaRegistryPort}}/apis/registry/v2",
+				"value.converter.apicurio.registry.auto-register": "false",
+				"value.converter.apicurio.registry.find-latest": "true",
+				"value.converter.enhanced.avro.schema.support": "true",
+				"transforms.reroute.type": "io.debezium.transforms.ByLogicalTableRouter",
+				"transforms.reroute.topic.regex": ".*",
+				"transforms.reroute.topic.replacement": "{{.TopicName}}"
+			}`,
+		},
+		DebeziumMySQLTemplate: Parameter{
+			Type:          reflect.String,
+			ConfigMapName: "xjoin-generic",
+			ConfigMapKey:  "debezium.connector.mysql.template",
+			DefaultValue: `{
+				"tasks.max": "{{.DebeziumTasksMax}}",
+				"database.hostname": "{{.DatabaseHostname}}",
+				"database.port": "{{.DatabasePort}}",
+				"database.user": "{{.DatabaseUsername}}",
+				"database.password": "{{.DatabasePassword}}",
+				"database.server.id": "{{.DatabaseServerId}}",
+				"database.server.name": "{{.DatabaseServerName}}",
+				"database.include.list": "{{.DatabaseName}}",
+				"database.ssl.mode": "{{.DatabaseSSLMode}}",
+				"database.history.kafka.bootstrap.servers": "{{.KafkaBootstrapURL}}",
+				"database.history.kafka.topic": "{{.SchemaHistoryTopicName}}",
+				"include.schema.changes": false,
+				"table.include.list": "{{.DatabaseTable}}",
+				{{if .ColumnIncludeList}}"column.include.list": "{{.ColumnIncludeList}}",{{end}}
+				{{if .DecimalHandlingMode}}"decimal.handling.mode": "{{.DecimalHandlingMode}}",{{end}}
+				{{if .SnapshotSelectStatement}}"snapshot.select.statement.overrides": "{{.DatabaseTable}}",
+				"snapshot.select.statement.overrides.{{.DatabaseTable}}": "{{.SnapshotSelectStatement}}",{{end}}
+				{{if .DatabaseSignalTable}}"signal.data.collection": "{{.DatabaseSignalTable}}",
+				"incremental.snapshot.chunk.size": {{.DebeziumSnapshotChunkSize}},{{end}}
+				"transforms": "unwrap{{if .RowFilterCondition}}, filter{{end}}, reroute",
+				"transforms.unwrap.type": "io.debezium.transforms.ExtractNewRecordState",
+				"transforms.unwrap.delete.handling.mode": "rewrite",
+				{{if .RowFilterCondition}}"transforms.filter.type": "io.debezium.transforms.Filter",
+				"transforms.filter.language": "jsr223.groovy",
+				"transforms.filter.condition": "{{.RowFilterCondition}}",{{end}}
+				"errors.log.enable": {{.DebeziumErrorsLogEnable}},
+				"errors.log.include.messages": true,
+				"max.queue.size": {{.DebeziumQueueSize}},
+				"max.batch.size": {{.DebeziumMaxBatchSize}},
+				"poll.interval.ms": {{.DebeziumPollIntervalMS}},
+				"key.converter": "io.apicurio.registry.utils.converter.AvroConverter",
+				"key.converter.apicurio.registry.url": "{{.SchemaRegistryProtocol}}://{{.SchemaRegistryHost}}:{{.SchemaRegistryPort}}/apis/registry/v2",
+				"key.converter.apicurio.registry.auto-register": "true",
+				"value.converter": "io.apicurio.registry.utils.converter.AvroConverter",
+				"value.converter.apicurio.registry.url": "{{.SchemaRegistryProtocol}}://{{.SchemaRegistryHost}}:{{.SchemaRegistryPort}}/apis/registry/v2",
+				"value.converter.apicurio.registry.auto-register": "false",
+				"value.converter.apicurio.registry.find-latest": "true",
+				"value.converter.enhanced.avro.schema.support": "true",
+				"transforms.reroute.type": "io.debezium.transforms.ByLogicalTableRouter",
+				"transforms.reroute.topic.regex": ".*",
+				"transforms.reroute.topic.replacement": "{{.TopicName}}"
+			}`,
+		},
+		DebeziumSQLServerTemplate: Parameter{
+			Type:          reflect.String,
+			ConfigMapName: "xjoin-generic",
+			ConfigMapKey:  "debezium.connector.sqlserver.template",
+			DefaultValue: `{
+				"tasks.max": "{{.DebeziumTasksMax}}",
+				"database.hostname": "{{.DatabaseHostname}}",
+				"database.port": "{{.DatabasePort}}",
+				"database.user": "{{.DatabaseUsername}}",
+				"database.password": "{{.DatabasePassword}}",
+				"database.dbname": "{{.DatabaseName}}",
+				"database.server.name": "{{.DatabaseServerName}}",
+				"database.encrypt": "{{if eq .DatabaseSSLMode "disable"}}false{{else}}true{{end}}",
+				"database.trustServerCertificate": "{{if eq .DatabaseSSLMode "verify-full"}}false{{else}}true{{end}}",
+				"database.history.kafka.bootstrap.servers": "{{.KafkaBootstrapURL}}",
+				"database.history.kafka.topic": "{{.SchemaHistoryTopicName}}",
+				"include.schema.changes": false,
+				"table.include.list": "{{.DatabaseTable}}",
+				{{if .ColumnIncludeList}}"column.include.list": "{{.ColumnIncludeList}}",{{end}}
+				{{if .DecimalHandlingMode}}"decimal.handling.mode": "{{.DecimalHandlingMode}}",{{end}}
+				{{if .SnapshotSelectStatement}}"snapshot.select.statement.overrides": "{{.DatabaseTable}}",
+				"snapshot.select.statement.overrides.{{.DatabaseTable}}": "{{.SnapshotSelectStatement}}",{{end}}
+				{{if .DatabaseSignalTable}}"signal.data.collection": "{{.DatabaseSignalTable}}",
+				"incremental.snapshot.chunk.size": {{.DebeziumSnapshotChunkSize}},{{end}}
+				"transforms": "unwrap{{if .RowFilterCondition}}, filter{{end}}, reroute",
+				"transforms.unwrap.type": "io.debezium.transforms.ExtractNewRecordState",
+				"transforms.unwrap.delete.handling.mode": "rewrite",
+				{{if .RowFilterCondition}}"transforms.filter.type": "io.debezium.transforms.Filter",
+				"transforms.filter.language": "jsr223.groovy",
+				"transforms.filter.condition": "{{.RowFilterCondition}}",{{end}}
+				"errors.log.enable": {{.DebeziumErrorsLogEnable}},
+				"errors.log.include.messages": true,
 				"max.queue.size": {{.DebeziumQueueSize}},
 				"max.batch.size": {{.DebeziumMaxBatchSize}},
 				"poll.interval.ms": {{.DebeziumPollIntervalMS}},
@@ -139,6 +284,60 @@ func BuildDataSourceParameters() *DataSourceParameters {
 			ConfigMapName: "xjoin-generic",
 			DefaultValue:  true,
 		},
+		DebeziumSnapshotChunkSize: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "debezium.connector.incremental.snapshot.chunk.size",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  1024,
+		},
+		IncrementalSnapshotTimeout: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "incremental.snapshot.timeout",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  6 * 60 * 60,
+		},
+		ReplicationLagThreshold: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "replication.lag.threshold",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  1024 * 1024 * 1024,
+		},
+		ReplicationLagLimit: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "replication.lag.limit",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  0,
+		},
+		KafkaBootstrapURL: Parameter{
+			Type:          reflect.String,
+			ConfigMapKey:  "kafka.bootstrap.url",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  "localhost:9092",
+		},
+		ValidationInterval: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.interval",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  1 * 60,
+		},
+		ValidationJobDeadline: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.job.deadline",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  30 * 60,
+		},
+		ValidationJobBackoffLimit: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.job.backoff.limit",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  0,
+		},
+		ValidationJobTTL: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.job.ttl",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  60 * 60,
+		},
 	}
 
 	p.CommonParameters = BuildCommonParameters()
@@ -7,28 +7,42 @@ import (
 
 type IndexParameters struct {
 	CommonParameters
-	ElasticSearchConnectorTemplate   Parameter
-	ElasticSearchURL                 Parameter
-	ElasticSearchUsername            Parameter
-	ElasticSearchPassword            Parameter
-	ElasticSearchTasksMax            Parameter
-	ElasticSearchMaxInFlightRequests Parameter
-	ElasticSearchErrorsLogEnable     Parameter
-	ElasticSearchMaxRetries          Parameter
-	ElasticSearchRetryBackoffMS      Parameter
-	ElasticSearchBatchSize           Parameter
-	ElasticSearchMaxBufferedRecords  Parameter
-	ElasticSearchLingerMS            Parameter
-	ElasticSearchNamespace           Parameter
-	ElasticSearchSecretVersion       Parameter
-	ElasticSearchPipelineTemplate    Parameter
-	ElasticSearchIndexReplicas       Parameter
-	ElasticSearchIndexShards         Parameter
-	ElasticSearchIndexTemplate       Parameter
-	KafkaBootstrapURL                Parameter
-	CustomSubgraphImages             Parameter
-	ValidationInterval               Parameter //period between validation checks (seconds)
-	ValidationPodStatusInterval      Parameter //period between checking the status of the validation pod (seconds)
+	ElasticSearchConnectorTemplate     Parameter
+	ElasticSearchURL                   Parameter
+	ElasticSearchUsername              Parameter
+	ElasticSearchPassword              Parameter
+	ElasticSearchTasksMax              Parameter
+	ElasticSearchMaxInFlightRequests   Parameter
+	ElasticSearchErrorsLogEnable       Parameter
+	ElasticSearchMaxRetries            Parameter
+	ElasticSearchRetryBackoffMS        Parameter
+	ElasticSearchBatchSize             Parameter
+	ElasticSearchMaxBufferedRecords    Parameter
+	ElasticSearchLingerMS              Parameter
+	ElasticSearchNamespace             Parameter
+	ElasticSearchSecretVersion         Parameter
+	ElasticSearchPipelineTemplate      Parameter
+	ElasticSearchIndexReplicas         Parameter
+	ElasticSearchIndexShards           Parameter
+	ElasticSearchIndexTemplate         Parameter
+	KafkaBootstrapURL                  Parameter
+	CustomSubgraphImages               Parameter
+	ValidationInterval                 Parameter //period between validation checks (seconds)
+	ValidationJobDeadline              Parameter //activeDeadlineSeconds of the validation Job
+	ValidationJobBackoffLimit          Parameter //number of retries of a failed validation pod
+	ValidationJobTTL                   Parameter //ttlSecondsAfterFinished of the validation Job
+	ValidationAttemptsThreshold        Parameter //consecutive failed validations before the active version is invalid
+	ValidationPercentageThreshold      Parameter //percentage of mismatched documents a validation tolerates
+	ValidationRefreshAttemptsThreshold Parameter //consecutive failed validations before a refresh is restarted (0 = never)
+	ValidationInitPercentageThreshold  Parameter //percentage of mismatched documents tolerated before a version is active
+	ValidationReportHistorySize        Parameter //number of validation runs kept in the validation report ConfigMap
+	ValidationReportMaxIds             Parameter //maximum number of mismatched record ids stored per validation run
+	ValidationRepairThreshold          Parameter //maximum percentage of mismatched documents that are repaired (0 = disabled)
+	ValidationRepairMaxIds             Parameter //maximum number of mismatched records that are repaired at once
+	ValidationRepairInterval           Parameter //minimum period between repairs of a pipeline (seconds)
+	ValidationRepairIdColumn           Parameter //database column matched against the mismatched record ids
+	CoreWorkload                       Parameter //JSON WorkloadSpec with the defaults for the xjoin-core deployments
+	SubgraphWorkload                   Parameter //JSON WorkloadSpec with the defaults for the xjoin-api-subgraph deployments
 }
 
 func BuildIndexParameters() *IndexParameters {
@@ -173,12 +187,100 @@ func BuildIndexParameters() *IndexParameters {
 			ConfigMapName: "xjoin-generic",
 			DefaultValue:  1 * 60,
 		},
-		ValidationPodStatusInterval: Parameter{
+		ValidationJobDeadline: Parameter{
 			Type:          reflect.Int,
-			ConfigMapKey:  "validation.pod.status.interval",
+			ConfigMapKey:  "validation.job.deadline",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  30 * 60,
+		},
+		ValidationJobBackoffLimit: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.job.backoff.limit",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  0,
+		},
+		ValidationJobTTL: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.job.ttl",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  60 * 60,
+		},
+		ValidationAttemptsThreshold: Parameter{
+			Type:          reflect.Int,
+			SpecKey:       "Validation.AttemptsThreshold",
+			ConfigMapKey:  "validation.attempts.threshold",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  3,
+		},
+		ValidationPercentageThreshold: Parameter{
+			Type:          reflect.Int,
+			SpecKey:       "Validation.PercentageThreshold",
+			ConfigMapKey:  "validation.percentage.threshold",
 			ConfigMapName: "xjoin-generic",
 			DefaultValue:  5,
 		},
+		ValidationRefreshAttemptsThreshold: Parameter{
+			Type:          reflect.Int,
+			SpecKey:       "Validation.RefreshAttemptsThreshold",
+			ConfigMapKey:  "validation.refresh.attempts.threshold",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  0,
+		},
+		ValidationInitPercentageThreshold: Parameter{
+			Type:          reflect.Int,
+			SpecKey:       "Validation.InitPercentageThreshold",
+			ConfigMapKey:  "init.validation.percentage.threshold",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  5,
+		},
+		ValidationReportHistorySize: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.report.history.size",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  10,
+		},
+		ValidationReportMaxIds: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.report.ids.max",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  50,
+		},
+		ValidationRepairThreshold: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.repair.percentage.threshold",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  0,
+		},
+		ValidationRepairMaxIds: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.repair.ids.max",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  100,
+		},
+		ValidationRepairInterval: Parameter{
+			Type:          reflect.Int,
+			ConfigMapKey:  "validation.repair.interval",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  10 * 60,
+		},
+		ValidationRepairIdColumn: Parameter{
+			Type:          reflect.String,
+			ConfigMapKey:  "validation.repair.id.column",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  "id",
+		},
+		CoreWorkload: Parameter{
+			Type:          reflect.String,
+			ConfigMapKey:  "xjoin.core.workload",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  "{}",
+		},
+		SubgraphWorkload: Parameter{
+			Type:          reflect.String,
+			ConfigMapKey:  "xjoin.api.subgraph.workload",
+			ConfigMapName: "xjoin-generic",
+			DefaultValue:  "{}",
+		},
 	}
 
 	p.CommonParameters = BuildCommonParameters()
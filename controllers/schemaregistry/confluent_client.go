@@ -39,6 +39,14 @@ func (sr *ConfluentClient) RegisterAvroSchema(name string, schemaDefinition stri
 	return schema.ID(), nil
 }
 
+func (sr *ConfluentClient) IsSchemaCompatible(name string, schemaDefinition string) (compatible bool, err error) {
+	compatible, err = sr.Client.IsSchemaCompatible(name, schemaDefinition, "latest", srclient.Avro)
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	return
+}
+
 func (sr *ConfluentClient) CheckIfSchemaVersionExists(name string, version int) (exists bool, err error) {
 	_, err = sr.Client.GetSchemaByVersion(name, version)
 	errorCode := -1
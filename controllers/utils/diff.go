@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	"sort"
+)
+
+// ChangedKeys are the sorted keys whose values differ between current and expected, including added and removed keys.
+// Deviations report only these keys because the values can contain credentials or be too long for a condition.
+func ChangedKeys(current map[string]interface{}, expected map[string]interface{}) (keys []string) {
+	for key, value := range expected {
+		currentValue, ok := current[key]
+		if !ok || !cmp.Equal(currentValue, value, utils.NumberNormalizer) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range current {
+		if _, ok := expected[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return
+}
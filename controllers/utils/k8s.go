@@ -13,6 +13,7 @@ import (
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -30,12 +31,45 @@ func FetchXJoinDataSourcePipeline(c client.Client, namespacedName types.Namespac
 	return instance, err
 }
 
+func FetchXJoinDataSourceValidator(c client.Client, namespacedName types.NamespacedName, ctx context.Context) (*xjoin.XJoinDataSourceValidator, error) {
+	instance := &xjoin.XJoinDataSourceValidator{}
+	err := c.Get(ctx, namespacedName, instance)
+	return instance, err
+}
+
 func FetchXJoinDataSource(c client.Client, namespacedName types.NamespacedName, ctx context.Context) (*xjoin.XJoinDataSource, error) {
 	instance := &xjoin.XJoinDataSource{}
 	err := c.Get(ctx, namespacedName, instance)
 	return instance, err
 }
 
+// SignalTableSnapshot is the name of an XJoinDataSource other than exclude whose incremental snapshot is in progress
+// on the same database's signalTable, empty when there is none. Debezium's watermark rows in a signal table do not
+// name their snapshot, so only one snapshot at a time sends signals to a signal table.
+func SignalTableSnapshot(c client.Client, ctx context.Context, namespace string, exclude string,
+	hostname *xjoin.StringOrSecretParameter, databaseName *xjoin.StringOrSecretParameter, signalTable string) (
+	string, error) {
+
+	dataSources := &xjoin.XJoinDataSourceList{}
+	err := c.List(ctx, dataSources, client.InNamespace(namespace))
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+
+	for index := range dataSources.Items {
+		dataSource := &dataSources.Items[index]
+		if dataSource.Name == exclude || dataSource.Spec.DatabaseSignalTable != signalTable ||
+			!dataSource.IncrementalSnapshotInProgress() {
+			continue
+		}
+		if reflect.DeepEqual(dataSource.Spec.DatabaseHostname, hostname) &&
+			reflect.DeepEqual(dataSource.Spec.DatabaseName, databaseName) {
+			return dataSource.Name, nil
+		}
+	}
+	return "", nil
+}
+
 func FetchXJoinIndex(c client.Client, namespacedName types.NamespacedName, ctx context.Context) (*xjoin.XJoinIndex, error) {
 	instance := &xjoin.XJoinIndex{}
 	err := c.Get(ctx, namespacedName, instance)
@@ -12,7 +12,9 @@ import (
 	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
 	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -86,10 +88,6 @@ func (r *XJoinDataSourceReconciler) Reconcile(ctx context.Context, request ctrl.
 
 	p := parameters.BuildDataSourceParameters()
 
-	if p.Pause.Bool() {
-		return
-	}
-
 	configManager, err := config.NewManager(config.ManagerOptions{
 		Client:         r.Client,
 		Parameters:     p,
@@ -126,9 +124,79 @@ func (r *XJoinDataSourceReconciler) Reconcile(ctx context.Context, request ctrl.
 		return reconcile.Result{}, errors.Wrap(err, 0)
 	}
 
+	if p.Pause.Bool() && instance.GetDeletionTimestamp() == nil {
+		reqLogger.Info("Reconciliation is paused")
+		common.SetPausedCondition(instance, true)
+		return i.UpdateStatusAndRequeue(0)
+	}
+	common.SetPausedCondition(instance, false)
+
+	//check status of active and refreshing DataSourcePipelines, update instance.Status accordingly
+	var activeDataSourcePipeline *xjoin.XJoinDataSourcePipeline
+	if instance.Status.ActiveVersion != "" {
+		dataSourcePipelineNamespacedName := types.NamespacedName{
+			Name:      i.Instance.GetName() + "." + instance.Status.ActiveVersion,
+			Namespace: i.Instance.GetNamespace(),
+		}
+
+		activeDataSourcePipeline, err = k8sUtils.FetchXJoinDataSourcePipeline(i.Client, dataSourcePipelineNamespacedName, i.Context)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, 0)
+		}
+
+		if common.ValidatedSinceSkipped(instance.Status.ActiveVersionValidationSkipped,
+			instance.Status.ActiveVersionValidationSkippedTime, activeDataSourcePipeline.Status.LastValidationTime) {
+			instance.SetActiveVersionValidationSkipped(false)
+		}
+		instance.Status.ActiveVersionIsValid = (activeDataSourcePipeline.Status.ValidationResponse.Result == "valid" ||
+			instance.Status.ActiveVersionValidationSkipped) &&
+			common.ComponentsAreHealthy(activeDataSourcePipeline.Status.Conditions)
+
+		replicationLag := meta.FindStatusCondition(
+			activeDataSourcePipeline.Status.Conditions, xjoin.ReplicationLagHighConditionType)
+		if replicationLag != nil {
+			replicationLag.ObservedGeneration = instance.Generation
+			instance.SetCondition(*replicationLag)
+		} else {
+			meta.RemoveStatusCondition(&instance.Status.Conditions, xjoin.ReplicationLagHighConditionType)
+		}
+	}
+
+	if instance.Status.RefreshingVersion != "" {
+		dataSourcePipelineNamespacedName := types.NamespacedName{
+			Name:      i.Instance.GetName() + "." + instance.Status.RefreshingVersion,
+			Namespace: i.Instance.GetNamespace(),
+		}
+
+		refreshingDataSourcePipeline, err := k8sUtils.FetchXJoinDataSourcePipeline(i.Client, dataSourcePipelineNamespacedName, i.Context)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, 0)
+		}
+
+		instance.Status.RefreshingVersionIsValid = refreshingDataSourcePipeline.Status.ValidationResponse.Result == "valid" &&
+			common.ComponentsAreHealthy(refreshingDataSourcePipeline.Status.Conditions)
+	}
+
 	dataSourceReconciler := NewReconcileMethods(i, common.DataSourceGVK)
-	reconciler := common.NewReconciler(dataSourceReconciler, instance, reqLogger)
-	err = reconciler.Reconcile(false)
+	reconciler := common.NewReconciler(dataSourceReconciler, instance, reqLogger, r.Recorder)
+	snapshotPending, err := i.UpdateIncrementalSnapshot(activeDataSourcePipeline)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+	if snapshotPending {
+		reconciler.DeferRefresh()
+	}
+	handledAnnotation, refreshReason, err := reconciler.HandleAction()
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	refreshReason, err = i.ReconcileIncrementalSnapshot(refreshReason, activeDataSourcePipeline)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	err = reconciler.Reconcile(refreshReason)
 	if err != nil {
 		return result, errors.Wrap(err, 0)
 	}
@@ -138,16 +206,20 @@ func (r *XJoinDataSourceReconciler) Reconcile(ctx context.Context, request ctrl.
 		return reconcile.Result{}, nil
 	}
 
-	instance.Status.SpecHash, err = k8sUtils.SpecHash(instance.Spec)
+	instance.Status.SpecHash, err = k8sUtils.SpecHash(instance.GetSpec())
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	result, err = i.UpdateStatusAndRequeue(time.Second * 30)
 	if err != nil {
 		return result, errors.Wrap(err, 0)
 	}
 
-	//TODO actually validate
-	if originalInstance.Status.RefreshingVersion != "" {
-		instance.Status.ActiveVersionIsValid = true
-		instance.Status.ActiveVersion = instance.Status.RefreshingVersion
+	err = i.RemoveAnnotation(handledAnnotation)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
 	}
 
-	return i.UpdateStatusAndRequeue(time.Second * 30)
+	return result, nil
 }
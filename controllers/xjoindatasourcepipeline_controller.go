@@ -7,6 +7,7 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/go-logr/logr"
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
 	xjoin "github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	"github.com/redhatinsights/xjoin-operator/controllers/components"
@@ -14,6 +15,7 @@ import (
 	. "github.com/redhatinsights/xjoin-operator/controllers/datasource"
 	"github.com/redhatinsights/xjoin-operator/controllers/kafka"
 	xjoinlogger "github.com/redhatinsights/xjoin-operator/controllers/log"
+	"github.com/redhatinsights/xjoin-operator/controllers/metrics"
 	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
 	"github.com/redhatinsights/xjoin-operator/controllers/schemaregistry"
 	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
@@ -153,6 +155,10 @@ func (r *XJoinDataSourcePipelineReconciler) Reconcile(ctx context.Context, reque
 
 	registry.Init()
 
+	if instance.KafkaTopicSource() != nil {
+		return r.reconcileKafkaTopicSource(i, registry)
+	}
+
 	componentManager := components.NewComponentManager(common.DataSourcePipelineGVK.Kind+"."+instance.Spec.Name, p.Version.String())
 	componentManager.AddComponent(components.NewAvroSchema(components.AvroSchemaParameters{
 		Schema:   p.AvroSchema.String(),
@@ -177,7 +183,7 @@ func (r *XJoinDataSourcePipelineReconciler) Reconcile(ctx context.Context, reque
 		Context:               ctx,
 		//ResourceNamePrefix:  this is not needed for generic topics
 	}
-	componentManager.AddComponent(&components.KafkaTopic{
+	kafkaTopicComponent := &components.KafkaTopic{
 		TopicParameters: kafka.TopicParameters{
 			Replicas:           p.KafkaTopicReplicas.Int(),
 			Partitions:         p.KafkaTopicPartitions.Int(),
@@ -189,12 +195,64 @@ func (r *XJoinDataSourcePipelineReconciler) Reconcile(ctx context.Context, reque
 			CreationTimeout:    p.KafkaTopicCreationTimeout.Int(),
 		},
 		KafkaTopics: kafkaTopics,
-	})
+	}
+	componentManager.AddComponent(kafkaTopicComponent)
+
+	databaseType, databaseProblem := i.DatabaseType()
+
+	templateParameters := databaseType.ConnectorTemplateParameters(*p)
+	err = i.FilterTemplateParameters(templateParameters)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, 0)
+	}
+	i.CertificateTemplateParameters(templateParameters)
 
-	componentManager.AddComponent(&components.DebeziumConnector{
-		TemplateParameters: config.ParametersToMap(*p),
+	debeziumConnector := &components.DebeziumConnector{
+		TemplateParameters: templateParameters,
 		KafkaClient:        kafkaClient,
-		Template:           p.DebeziumConnectorTemplate.String(),
+		Class:              databaseType.ConnectorClass,
+		Template:           databaseType.ConnectorTemplate(*p),
+	}
+	componentManager.AddComponent(debeziumConnector)
+
+	if databaseType.SchemaHistory {
+		//the connector reads the whole schema history when it restarts, so it is never deleted from the topic
+		componentManager.AddComponent(&components.KafkaTopic{
+			Suffix: components.SchemaHistoryTopicSuffix,
+			TopicParameters: kafka.TopicParameters{
+				Replicas:           p.KafkaTopicReplicas.Int(),
+				Partitions:         1,
+				CleanupPolicy:      "delete",
+				MinCompactionLagMS: p.KafkaTopicMinCompactionLagMS.String(),
+				RetentionBytes:     "-1",
+				RetentionMS:        "-1",
+				MessageBytes:       p.KafkaTopicMessageBytes.String(),
+				CreationTimeout:    p.KafkaTopicCreationTimeout.Int(),
+			},
+			KafkaTopics: kafkaTopics,
+		})
+	}
+
+	componentManager.AddComponent(&components.XJoinDataSourceValidator{
+		Client:              i.Client,
+		Context:             i.Context,
+		Namespace:           i.Instance.GetNamespace(),
+		Schema:              p.AvroSchema.String(),
+		Pause:               p.Pause.Bool(),
+		ParentInstance:      i.Instance,
+		TopicName:           kafkaTopicComponent.Name(),
+		DatabaseHostname:    instance.Spec.DatabaseHostname,
+		DatabasePort:        instance.Spec.DatabasePort,
+		DatabaseUsername:    instance.Spec.DatabaseUsername,
+		DatabasePassword:    instance.Spec.DatabasePassword,
+		DatabaseName:        instance.Spec.DatabaseName,
+		DatabaseTable:       instance.Spec.DatabaseTable,
+		DatabaseSSLMode:     instance.Spec.DatabaseSSLMode,
+		DatabaseSSLRootCert: instance.Spec.DatabaseSSLRootCert,
+		DatabaseSSLCert:     instance.Spec.DatabaseSSLCert,
+		DatabaseSSLKey:      instance.Spec.DatabaseSSLKey,
+		DatabaseType:        databaseType.Name,
+		RowFilter:           instance.Spec.RowFilter,
 	})
 
 	if instance.GetDeletionTimestamp() != nil {
@@ -205,6 +263,18 @@ func (r *XJoinDataSourcePipelineReconciler) Reconcile(ctx context.Context, reque
 			return
 		}
 
+		//the connector's replication slot would otherwise retain WAL until the datasource's custodian removes it
+		if databaseType.ReplicationSlots {
+			replicationSlot := &components.ReplicationSlot{DBParams: i.DBParams()}
+			replicationSlot.SetName(common.DataSourcePipelineGVK.Kind + "." + instance.Spec.Name)
+			replicationSlot.SetVersion(instance.Spec.Version)
+			err = replicationSlot.Delete()
+			if err != nil {
+				reqLogger.Error(err, "Unable to remove the replication slot", "slot", replicationSlot.Name())
+			}
+		}
+		metrics.DeleteReplicationSlotLag(instance.Spec.Name, instance.Spec.Version)
+
 		controllerutil.RemoveFinalizer(instance, xjoindatasourcepipelineFinalizer)
 		ctx, cancel := utils.DefaultContext()
 		defer cancel()
@@ -217,6 +287,12 @@ func (r *XJoinDataSourcePipelineReconciler) Reconcile(ctx context.Context, reque
 		return reconcile.Result{}, nil
 	}
 
+	if databaseProblem != nil {
+		reqLogger.Info("Invalid database parameters", "problem", databaseProblem)
+		common.SetComponentsHealthyCondition(&instance.Status.Conditions, []error{databaseProblem})
+		return i.UpdateStatusAndRequeue(time.Second * 30)
+	}
+
 	err = componentManager.CreateAll()
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, 0)
@@ -227,9 +303,90 @@ func (r *XJoinDataSourcePipelineReconciler) Reconcile(ctx context.Context, reque
 		return reconcile.Result{}, errors.Wrap(err, 0)
 	}
 
+	if databaseType.ReplicationSlots {
+		replicationSlotProblem := i.ReconcileReplicationSlot(debeziumConnector.ReplicationSlotName())
+		if replicationSlotProblem != nil {
+			problems = append(problems, replicationSlotProblem)
+		}
+	}
+
 	if len(problems) > 0 {
-		//TODO: set instance status to invalid, add problems to status
-		reqLogger.Info("TODO: Set Instance status to invalid, add", "problems", len(problems))
+		reqLogger.Info("Component deviations found", "problems", problems)
+	}
+	common.SetComponentsHealthyCondition(&instance.Status.Conditions, problems)
+
+	return i.UpdateStatusAndRequeue(time.Second * 30)
+}
+
+// reconcileKafkaTopicSource registers the schema of an existing Kafka topic under the pipeline's subject so the topic
+// can be referenced by XJoinIndexes. The topic is not managed by the operator and there is no connector to validate.
+func (r *XJoinDataSourcePipelineReconciler) reconcileKafkaTopicSource(
+	i XJoinDataSourcePipelineIteration, registry *schemaregistry.ConfluentClient) (result ctrl.Result, err error) {
+
+	instance := i.GetInstance()
+
+	var schema string
+	var schemaProblem error
+	if instance.GetDeletionTimestamp() == nil {
+		schema, schemaProblem, err = i.KafkaTopicSourceSchema(registry)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, 0)
+		}
+	}
+
+	componentManager := components.NewComponentManager(
+		common.DataSourcePipelineGVK.Kind+"."+instance.Spec.Name, i.Parameters.Version.String())
+	componentManager.AddComponent(components.NewAvroSchema(components.AvroSchemaParameters{
+		Schema:   schema,
+		Registry: registry,
+	}))
+
+	if instance.GetDeletionTimestamp() != nil {
+		i.Log.Info("Starting finalizer")
+		err = componentManager.DeleteAll()
+		if err != nil {
+			i.Log.Error(err, "error deleting components during finalizer")
+			return
+		}
+
+		controllerutil.RemoveFinalizer(instance, xjoindatasourcepipelineFinalizer)
+		ctx, cancel := utils.DefaultContext()
+		defer cancel()
+		err = r.Client.Update(ctx, instance)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, 0)
+		}
+
+		i.Log.Info("Successfully finalized")
+		return reconcile.Result{}, nil
+	}
+
+	if schemaProblem != nil {
+		i.Log.Info("Unable to read the schema of the kafkaTopic source", "problem", schemaProblem)
+		common.SetComponentsHealthyCondition(&instance.Status.Conditions, []error{schemaProblem})
+		return i.UpdateStatusAndRequeue(time.Second * 30)
+	}
+
+	err = componentManager.CreateAll()
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, 0)
+	}
+
+	problems, err := componentManager.CheckForDeviations()
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, 0)
+	}
+
+	if len(problems) > 0 {
+		i.Log.Info("Component deviations found", "problems", problems)
+	}
+	common.SetComponentsHealthyCondition(&instance.Status.Conditions, problems)
+
+	//the records are produced by another application, so there is no database to validate the topic against
+	instance.Status.ValidationResponse = validation.ValidationResponse{
+		Result:  "valid",
+		Reason:  "skipped",
+		Message: "validation is skipped for datasources read from a Kafka topic",
 	}
 
 	return i.UpdateStatusAndRequeue(time.Second * 30)
@@ -4,13 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -79,6 +85,307 @@ var _ = Describe("XJoinDataSourcePipeline", func() {
 			Expect(actualDebeziumConfig).To(Equal(expectedDebeziumConfig))
 		})
 
+		It("Configures the Debezium signal table", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:           namespace,
+				Name:                "test-data-source-pipeline",
+				K8sClient:           k8sClient,
+				DatabaseSignalTable: "public.debezium_signal",
+			}
+			reconciler.ReconcileNew()
+
+			debeziumConnectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			debeziumConnector := &v1beta2.KafkaConnector{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), debeziumConnectorLookupKey, debeziumConnector)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			var debeziumConfig map[string]interface{}
+			err := json.Unmarshal(debeziumConnector.Spec.Config.Raw, &debeziumConfig)
+			checkError(err)
+			Expect(debeziumConfig["signal.data.collection"]).To(Equal("public.debezium_signal"))
+			Expect(debeziumConfig["incremental.snapshot.chunk.size"]).To(Equal(float64(1024)))
+		})
+
+		It("Configures TLS for the database connection", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:       namespace,
+				Name:            "test-data-source-pipeline",
+				K8sClient:       k8sClient,
+				DatabaseSSLMode: &v1alpha1.StringOrSecretParameter{Value: "verify-full"},
+				DatabaseSSLCert: &v1alpha1.StringOrSecretParameter{Value: "/opt/kafka/external-configuration/db/tls.crt"},
+			}
+			createdDataSourcePipeline := reconciler.ReconcileNew()
+
+			debeziumConnectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			debeziumConnector := &v1beta2.KafkaConnector{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), debeziumConnectorLookupKey, debeziumConnector)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			var debeziumConfig map[string]interface{}
+			err := json.Unmarshal(debeziumConnector.Spec.Config.Raw, &debeziumConfig)
+			checkError(err)
+			Expect(debeziumConfig["database.sslmode"]).To(Equal("verify-full"))
+			Expect(debeziumConfig["database.sslcert"]).To(Equal("/opt/kafka/external-configuration/db/tls.crt"))
+			Expect(debeziumConfig).ToNot(HaveKey("database.sslkey"))
+
+			validatorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			validator := &v1alpha1.XJoinDataSourceValidator{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), validatorLookupKey, validator)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+			Expect(validator.Spec.DatabaseSSLMode).To(Equal(createdDataSourcePipeline.Spec.DatabaseSSLMode))
+			Expect(validator.Spec.DatabaseSSLCert).To(Equal(createdDataSourcePipeline.Spec.DatabaseSSLCert))
+			Expect(validator.Spec.DatabaseSSLKey).To(BeNil())
+		})
+
+		It("Reads the certificates stored in secrets from the Kafka Connect externalConfiguration volumes", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:       namespace,
+				Name:            "test-data-source-pipeline",
+				K8sClient:       k8sClient,
+				DatabaseSSLMode: &v1alpha1.StringOrSecretParameter{Value: "verify-full"},
+				DatabaseSSLKey:  CreateCertificateSecret(namespace),
+			}
+			reconciler.ReconcileNew()
+
+			debeziumConnectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			debeziumConnector := &v1beta2.KafkaConnector{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), debeziumConnectorLookupKey, debeziumConnector)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			var debeziumConfig map[string]interface{}
+			err := json.Unmarshal(debeziumConnector.Spec.Config.Raw, &debeziumConfig)
+			checkError(err)
+			Expect(debeziumConfig["database.sslkey"]).To(Equal("/opt/kafka/external-configuration/db-client-cert/tls.key"))
+			Expect(debeziumConfig["database.sslrootcert"]).To(Equal(
+				"/opt/kafka/external-configuration/rds-client-ca/rds-cacert"))
+		})
+
+		It("Filters the connector's columns by the Avro schema and its rows by the rowFilter", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:          namespace,
+				Name:               "test-data-source-pipeline",
+				K8sClient:          k8sClient,
+				AvroSchemaFileName: "xjoindatasource-single-field",
+				DatabaseTable:      "public.hosts",
+				RowFilter: &v1alpha1.RowFilter{
+					Where:     "deleted_at IS NULL",
+					Condition: `value.deleted_at == null`,
+					Columns:   []string{"deleted_at"},
+				},
+			}
+			reconciler.ReconcileNew()
+
+			debeziumConnectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			debeziumConnector := &v1beta2.KafkaConnector{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), debeziumConnectorLookupKey, debeziumConnector)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			var debeziumConfig map[string]interface{}
+			err := json.Unmarshal(debeziumConnector.Spec.Config.Raw, &debeziumConfig)
+			checkError(err)
+			Expect(debeziumConfig["column.include.list"]).To(Equal("public.hosts.id,public.hosts.deleted_at"))
+			Expect(debeziumConfig["snapshot.select.statement.overrides"]).To(Equal("public.hosts"))
+			Expect(debeziumConfig["snapshot.select.statement.overrides.public.hosts"]).To(
+				Equal("SELECT * FROM public.hosts WHERE deleted_at IS NULL"))
+			Expect(debeziumConfig["transforms"]).To(Equal("unwrap, filter, reroute"))
+			Expect(debeziumConfig["transforms.filter.type"]).To(Equal("io.debezium.transforms.Filter"))
+			Expect(debeziumConfig["transforms.filter.condition"]).To(
+				Equal("value.__deleted == 'true' || (value.deleted_at == null) || value.put('__deleted', 'true') != null"))
+
+			validatorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			validator := &v1alpha1.XJoinDataSourceValidator{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), validatorLookupKey, validator)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+			Expect(validator.Spec.RowFilter).To(Equal(reconciler.RowFilter))
+		})
+
+		It("Captures every column when the rowFilter's condition reads columns that are not in the Avro schema", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:          namespace,
+				Name:               "test-data-source-pipeline",
+				K8sClient:          k8sClient,
+				AvroSchemaFileName: "xjoindatasource-single-field",
+				DatabaseTable:      "public.hosts",
+				RowFilter: &v1alpha1.RowFilter{
+					Where:     "deleted_at IS NULL",
+					Condition: `value.deleted_at == null`,
+				},
+			}
+			reconciler.ReconcileNew()
+
+			debeziumConnectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			debeziumConnector := &v1beta2.KafkaConnector{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), debeziumConnectorLookupKey, debeziumConnector)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			var debeziumConfig map[string]interface{}
+			err := json.Unmarshal(debeziumConnector.Spec.Config.Raw, &debeziumConfig)
+			checkError(err)
+			Expect(debeziumConfig).ToNot(HaveKey("column.include.list"))
+			Expect(debeziumConfig["transforms.filter.condition"]).To(
+				Equal("value.__deleted == 'true' || (value.deleted_at == null) || value.put('__deleted', 'true') != null"))
+		})
+
+		It("Emits decimals as doubles when the Avro schema has a double decimal field", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:          namespace,
+				Name:               "test-data-source-pipeline",
+				K8sClient:          k8sClient,
+				AvroSchemaFileName: "xjoindatasource-with-decimal-field",
+				DatabaseTable:      "public.hosts",
+			}
+			reconciler.ReconcileNew()
+
+			debeziumConnectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			debeziumConnector := &v1beta2.KafkaConnector{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), debeziumConnectorLookupKey, debeziumConnector)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			var debeziumConfig map[string]interface{}
+			err := json.Unmarshal(debeziumConnector.Spec.Config.Raw, &debeziumConfig)
+			checkError(err)
+			Expect(debeziumConfig["decimal.handling.mode"]).To(Equal("double"))
+			Expect(debeziumConfig["column.include.list"]).To(Equal("public.hosts.id,public.hosts.price"))
+		})
+
+		It("Creates a MySQL Debezium connector and its schema history topic", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:     namespace,
+				Name:          "test-data-source-pipeline",
+				K8sClient:     k8sClient,
+				DatabaseType:  "mysql",
+				DatabaseTable: "dbName.dbTable",
+			}
+			createdDataSourcePipeline := reconciler.ReconcileNew()
+			Expect(common.ComponentsAreHealthy(createdDataSourcePipeline.Status.Conditions)).To(BeTrue())
+			Expect(meta.FindStatusCondition(
+				createdDataSourcePipeline.Status.Conditions, v1alpha1.ReplicationLagHighConditionType)).To(BeNil())
+
+			debeziumConnectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			debeziumConnector := &v1beta2.KafkaConnector{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), debeziumConnectorLookupKey, debeziumConnector)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+			Expect(*debeziumConnector.Spec.Class).To(Equal("io.debezium.connector.mysql.MySqlConnector"))
+
+			var debeziumConfig map[string]interface{}
+			err := json.Unmarshal(debeziumConnector.Spec.Config.Raw, &debeziumConfig)
+			checkError(err)
+			Expect(debeziumConfig["database.include.list"]).To(Equal("dbName"))
+			Expect(debeziumConfig["table.include.list"]).To(Equal("dbName.dbTable"))
+			Expect(debeziumConfig["database.ssl.mode"]).To(Equal("disabled"))
+			Expect(debeziumConfig).ToNot(HaveKey("decimal.handling.mode"))
+			Expect(debeziumConfig["database.server.id"]).ToNot(BeEmpty())
+			Expect(debeziumConfig["database.history.kafka.topic"]).To(
+				Equal("xjoindatasourcepipeline.test-data-source-pipeline.1234.schema-history"))
+			Expect(debeziumConfig).ToNot(HaveKey("slot.name"))
+
+			schemaHistoryTopicLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234.schema-history", Namespace: namespace}
+			schemaHistoryTopic := &v1beta2.KafkaTopic{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), schemaHistoryTopicLookupKey, schemaHistoryTopic)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			var topicConfig map[string]interface{}
+			err = json.Unmarshal(schemaHistoryTopic.Spec.Config.Raw, &topicConfig)
+			checkError(err)
+			Expect(topicConfig["retention.ms"]).To(Equal("-1"))
+			Expect(topicConfig["cleanup.policy"]).To(Equal("delete"))
+
+			validatorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			validator := &v1alpha1.XJoinDataSourceValidator{}
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), validatorLookupKey, validator)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+			Expect(validator.Spec.DatabaseType).To(Equal("mysql"))
+		})
+
+		It("Does not create components when the database parameters are invalid for the databaseType", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-data-source-pipeline",
+				K8sClient:    k8sClient,
+				DatabaseType: "mysql",
+			}
+			createdDataSourcePipeline := reconciler.ReconcileNew()
+
+			condition := meta.FindStatusCondition(
+				createdDataSourcePipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("databaseTable dbTable of databaseType mysql"))
+
+			connectors := &v1beta2.KafkaConnectorList{}
+			err := k8sClient.List(context.Background(), connectors, client.InNamespace(namespace))
+			checkError(err)
+			Expect(connectors.Items).To(BeEmpty())
+		})
+
+		It("Registers the schema of a kafkaTopic source without creating a connector or topic", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source-pipeline",
+				K8sClient: k8sClient,
+				Source: &v1alpha1.DataSourceSource{
+					KafkaTopic: &v1alpha1.KafkaTopicSource{Topic: "app.records"},
+				},
+			}
+			createdDataSourcePipeline := reconciler.ReconcileNew()
+
+			info := httpmock.GetCallCountInfo()
+			count := info["GET http://apicurio:1080/apis/ccompat/v6/subjects/app.records-value/versions/latest"]
+			Expect(count).To(Equal(1))
+			count = info["POST http://apicurio:1080/apis/ccompat/v6/subjects/xjoindatasourcepipeline.test-data-source-pipeline.1234-value/versions"]
+			Expect(count).To(Equal(1))
+
+			connectors := &v1beta2.KafkaConnectorList{}
+			err := k8sClient.List(context.Background(), connectors, client.InNamespace(namespace))
+			checkError(err)
+			Expect(connectors.Items).To(BeEmpty())
+
+			topics := &v1beta2.KafkaTopicList{}
+			err = k8sClient.List(context.Background(), topics, client.InNamespace(namespace))
+			checkError(err)
+			Expect(topics.Items).To(BeEmpty())
+
+			validators := &v1alpha1.XJoinDataSourceValidatorList{}
+			err = k8sClient.List(context.Background(), validators, client.InNamespace(namespace))
+			checkError(err)
+			Expect(validators.Items).To(BeEmpty())
+
+			Expect(createdDataSourcePipeline.Status.ValidationResponse.Result).To(Equal("valid"))
+		})
+
 		It("Creates an Avro Schema", func() {
 			reconciler := DatasourcePipelineTestReconciler{
 				Namespace: namespace,
@@ -139,6 +446,199 @@ var _ = Describe("XJoinDataSourcePipeline", func() {
 
 			Expect(actualKafkaTopicConfig).To(Equal(expectedKafkaTopicConfig))
 		})
+
+		It("Creates an XJoinDataSourceValidator", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source-pipeline",
+				K8sClient: k8sClient,
+			}
+			createdDataSourcePipeline := reconciler.ReconcileNew()
+
+			validatorName := "xjoindatasourcepipeline.test-data-source-pipeline.1234"
+			validatorLookupKey := types.NamespacedName{Name: validatorName, Namespace: namespace}
+			validator := &v1alpha1.XJoinDataSourceValidator{}
+
+			Eventually(func() bool {
+				err := k8sClient.Get(context.Background(), validatorLookupKey, validator)
+				return err == nil
+			}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+			Expect(validator.GetLabels()).To(Equal(map[string]string{
+				"app":                  "xjoin-validator",
+				"xjoin.component.name": "xjoindatasourcepipeline.test-data-source-pipeline",
+			}))
+			Expect(validator.OwnerReferences).To(HaveLen(1))
+			Expect(validator.OwnerReferences[0].Kind).To(Equal("XJoinDataSourcePipeline"))
+			Expect(validator.OwnerReferences[0].Name).To(Equal("test-data-source-pipeline"))
+			Expect(validator.Spec.Version).To(Equal("1234"))
+			Expect(validator.Spec.TopicName).To(Equal("xjoindatasourcepipeline.test-data-source-pipeline.1234"))
+			Expect(validator.Spec.AvroSchema).To(Equal(createdDataSourcePipeline.Spec.AvroSchema))
+			Expect(validator.Spec.DatabaseHostname).To(Equal(createdDataSourcePipeline.Spec.DatabaseHostname))
+			Expect(validator.Spec.DatabaseTable).To(Equal(createdDataSourcePipeline.Spec.DatabaseTable))
+		})
+
+		It("Sets the ComponentsHealthy condition", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source-pipeline",
+				K8sClient: k8sClient,
+			}
+			createdDataSourcePipeline := reconciler.ReconcileNew()
+
+			condition := meta.FindStatusCondition(
+				createdDataSourcePipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("NoDeviations"))
+		})
+
+		It("Keeps the ComponentsHealthy condition message within the API's limit", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source-pipeline",
+				K8sClient: k8sClient,
+			}
+			dataSourcePipeline := reconciler.ReconcileNew()
+
+			var problems []error
+			for index := 0; index < 1000; index++ {
+				problems = append(problems, fmt.Errorf("kafkatopic.%d has a deviation: %s",
+					index, strings.Repeat("x", 100)))
+			}
+			common.SetComponentsHealthyCondition(&dataSourcePipeline.Status.Conditions, problems)
+			Expect(k8sClient.Status().Update(context.Background(), &dataSourcePipeline)).Should(Succeed())
+
+			condition := meta.FindStatusCondition(
+				dataSourcePipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(len(condition.Message)).To(BeNumerically("<=", common.MaxConditionMessageLength))
+			Expect(condition.Message).To(HavePrefix("kafkatopic.0 has a deviation"))
+			Expect(condition.Message).To(MatchRegexp(`; and \d+ more deviations$`))
+		})
+
+		It("Reports only the changed fields of a modified Kafka Topic", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source-pipeline",
+				K8sClient: k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			kafkaTopicLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			kafkaTopic := &v1beta2.KafkaTopic{}
+			err := k8sClient.Get(context.Background(), kafkaTopicLookupKey, kafkaTopic)
+			checkError(err)
+			var topicConfig map[string]interface{}
+			err = json.Unmarshal(kafkaTopic.Spec.Config.Raw, &topicConfig)
+			checkError(err)
+			topicConfig["retention.ms"] = "1"
+			kafkaTopic.Spec.Config.Raw, err = json.Marshal(topicConfig)
+			checkError(err)
+			partitions := int32(3)
+			kafkaTopic.Spec.Partitions = &partitions
+			err = k8sClient.Update(context.Background(), kafkaTopic)
+			checkError(err)
+
+			dataSourcePipeline := reconciler.ReconcileExisting()
+			condition := meta.FindStatusCondition(
+				dataSourcePipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring(
+				"topic xjoindatasourcepipeline.test-data-source-pipeline.1234 spec has changed: config.retention.ms, partitions"))
+		})
+
+		It("Does not refresh a connector created without a publication.name", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source-pipeline",
+				K8sClient: k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			connectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			connector := &v1beta2.KafkaConnector{}
+			err := k8sClient.Get(context.Background(), connectorLookupKey, connector)
+			checkError(err)
+			var config map[string]interface{}
+			err = json.Unmarshal(connector.Spec.Config.Raw, &config)
+			checkError(err)
+			Expect(config).To(HaveKey("publication.name"))
+			delete(config, "publication.name")
+			connector.Spec.Config.Raw, err = json.Marshal(config)
+			checkError(err)
+			err = k8sClient.Update(context.Background(), connector)
+			checkError(err)
+
+			dataSourcePipeline := reconciler.ReconcileExisting()
+			Expect(common.ComponentsAreHealthy(dataSourcePipeline.Status.Conditions)).To(BeTrue())
+
+			config["publication.name"] = "dbz_publication"
+			connector = &v1beta2.KafkaConnector{}
+			err = k8sClient.Get(context.Background(), connectorLookupKey, connector)
+			checkError(err)
+			connector.Spec.Config.Raw, err = json.Marshal(config)
+			checkError(err)
+			err = k8sClient.Update(context.Background(), connector)
+			checkError(err)
+
+			dataSourcePipeline = reconciler.ReconcileExisting()
+			condition := meta.FindStatusCondition(
+				dataSourcePipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring("configuration has changed: publication.name"))
+		})
+
+		It("Does not refresh a connector created without a column.include.list", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace:          namespace,
+				Name:               "test-data-source-pipeline",
+				K8sClient:          k8sClient,
+				AvroSchemaFileName: "xjoindatasource-single-field",
+				DatabaseTable:      "public.hosts",
+			}
+			reconciler.ReconcileNew()
+
+			connectorLookupKey := types.NamespacedName{
+				Name: "xjoindatasourcepipeline.test-data-source-pipeline.1234", Namespace: namespace}
+			connector := &v1beta2.KafkaConnector{}
+			err := k8sClient.Get(context.Background(), connectorLookupKey, connector)
+			checkError(err)
+			var config map[string]interface{}
+			err = json.Unmarshal(connector.Spec.Config.Raw, &config)
+			checkError(err)
+			Expect(config).To(HaveKey("column.include.list"))
+			delete(config, "column.include.list")
+			connector.Spec.Config.Raw, err = json.Marshal(config)
+			checkError(err)
+			err = k8sClient.Update(context.Background(), connector)
+			checkError(err)
+
+			dataSourcePipeline := reconciler.ReconcileExisting()
+			condition := meta.FindStatusCondition(
+				dataSourcePipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("column.include.list"))
+		})
+
+		It("Sets the ReplicationLagHigh condition to unknown when the database is unreachable", func() {
+			reconciler := DatasourcePipelineTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source-pipeline",
+				K8sClient: k8sClient,
+			}
+			createdDataSourcePipeline := reconciler.ReconcileNew()
+
+			condition := meta.FindStatusCondition(
+				createdDataSourcePipeline.Status.Conditions, v1alpha1.ReplicationLagHighConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionUnknown))
+			Expect(condition.Reason).To(Equal("QueryFailed"))
+			Expect(common.ComponentsAreHealthy(createdDataSourcePipeline.Status.Conditions)).To(BeTrue())
+		})
 	})
 
 	Context("Reconcile Deletion", func() {
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"context"
+	"github.com/go-errors/errors"
+	"github.com/go-logr/logr"
+	xjoin "github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/config"
+	. "github.com/redhatinsights/xjoin-operator/controllers/datasource"
+	"github.com/redhatinsights/xjoin-operator/controllers/k8s"
+	xjoinlogger "github.com/redhatinsights/xjoin-operator/controllers/log"
+	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
+	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	batchv1 "k8s.io/api/batch/v1"
+	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
+)
+
+const xjoindatasourceValidatorFinalizer = "finalizer.xjoin.datasourcevalidator.cloud.redhat.com"
+
+type XJoinDataSourceValidatorReconciler struct {
+	Client       client.Client
+	Log          logr.Logger
+	Scheme       *runtime.Scheme
+	Recorder     record.EventRecorder
+	Namespace    string
+	Test         bool
+	ClientSet    kubernetes.Interface
+	PodLogReader k8s.LogReader
+}
+
+func NewXJoinDataSourceValidatorReconciler(
+	client client.Client,
+	scheme *runtime.Scheme,
+	clientset kubernetes.Interface,
+	log logr.Logger,
+	recorder record.EventRecorder,
+	namespace string,
+	isTest bool,
+	podLogReader k8s.LogReader) *XJoinDataSourceValidatorReconciler {
+
+	return &XJoinDataSourceValidatorReconciler{
+		Client:       client,
+		Log:          log,
+		Scheme:       scheme,
+		Recorder:     recorder,
+		Namespace:    namespace,
+		Test:         isTest,
+		ClientSet:    clientset,
+		PodLogReader: podLogReader,
+	}
+}
+
+func (r *XJoinDataSourceValidatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	logConstructor := func(r *reconcile.Request) logr.Logger {
+		return mgr.GetLogger()
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("xjoin-datasourcevalidator-controller").
+		For(&xjoin.XJoinDataSourceValidator{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&batchv1.Job{}, builder.WithPredicates(k8s.ValidationJobFinishedPredicate())).
+		WithLogConstructor(logConstructor).
+		WithOptions(controller.Options{
+			LogConstructor: logConstructor,
+			RateLimiter:    workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 1*time.Minute),
+		}).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=xjoin.cloud.redhat.com,resources=xjoindatasourcevalidators;xjoindatasourcevalidators/status;xjoindatasourcevalidators/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps;pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+func (r *XJoinDataSourceValidatorReconciler) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
+	reqLogger := xjoinlogger.NewLogger("controller_xjoindatasourcevalidator", "DataSourceValidator", request.Name, "Namespace", request.Namespace)
+	reqLogger.Info("Reconciling XJoinDataSourceValidator")
+
+	instance, err := k8sUtils.FetchXJoinDataSourceValidator(r.Client, request.NamespacedName, ctx)
+	if err != nil {
+		if k8errors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return result, nil
+		}
+		// Error reading the object - requeue the request.
+		return
+	}
+
+	p := parameters.BuildDataSourceParameters()
+
+	configManager, err := config.NewManager(config.ManagerOptions{
+		Client:         r.Client,
+		Parameters:     p,
+		ConfigMapNames: []string{"xjoin-generic"},
+		SecretNames:    nil,
+		Namespace:      instance.Namespace,
+		Spec:           instance.Spec,
+		Context:        ctx,
+	})
+	if err != nil {
+		return
+	}
+	err = configManager.Parse()
+	if err != nil {
+		return
+	}
+
+	if p.Pause.Bool() {
+		return
+	}
+
+	i := XJoinDataSourceValidatorIteration{
+		Parameters: *p,
+		Iteration: common.Iteration{
+			Context:          ctx,
+			Instance:         instance,
+			OriginalInstance: instance.DeepCopy(),
+			Client:           r.Client,
+			Log:              reqLogger,
+		},
+		ClientSet:    r.ClientSet,
+		PodLogReader: r.PodLogReader,
+	}
+
+	if err = i.AddFinalizer(xjoindatasourceValidatorFinalizer); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, 0)
+	}
+
+	if instance.GetDeletionTimestamp() != nil {
+		err = i.Finalize()
+		if err != nil {
+			return result, errors.Wrap(err, 0)
+		}
+		return result, nil
+	}
+
+	phase, err := i.ReconcileValidationJob()
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+	instance.Status.ValidationPodPhase = phase
+	if phase == ValidatorPodRunning {
+		//the finished job triggers the next reconcile
+		return i.UpdateStatusAndRequeue(0)
+	} else {
+		return i.UpdateStatusAndRequeue(time.Second * time.Duration(p.ValidationInterval.Int()))
+	}
+}
@@ -0,0 +1,281 @@
+package controllers_test
+
+import (
+	"github.com/jarcoal/httpmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/datasource"
+	"github.com/redhatinsights/xjoin-operator/controllers/k8s"
+	"github.com/redhatinsights/xjoin-operator/controllers/k8s/mocks"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	"os"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
+	//+kubebuilder:scaffold:imports
+)
+
+var _ = Describe("XJoinDataSourceValidator", func() {
+	var namespace string
+
+	BeforeEach(func() {
+		httpmock.Activate()
+		httpmock.RegisterNoResponder(httpmock.InitialTransport.RoundTrip) //disable mocks for unregistered http requests
+
+		var err error
+		namespace, err = NewNamespace()
+		checkError(err)
+	})
+
+	AfterEach(func() {
+		httpmock.DeactivateAndReset()
+	})
+
+	Context("Reconcile Creation", func() {
+		It("Should add a finalizer to the XJoinDataSourceValidator", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			createdValidator, _ := reconciler.ReconcileCreate()
+			Expect(createdValidator.Finalizers).To(HaveLen(1))
+			Expect(createdValidator.Finalizers).To(ContainElement(datasource.XJoinDataSourceValidatorFinalizer))
+		})
+
+		It("Should create an xjoin-validation pod", func() {
+			name := "test-datasource-validator"
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         name,
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			reconciler.ReconcileCreate()
+
+			jobs := reconciler.ListValidatorJobs()
+			Expect(len(jobs.Items)).To(Equal(1))
+			job := jobs.Items[0]
+
+			Expect(job.Name).To(HavePrefix("xjoin-validation-" + name + "-"))
+			Expect(job.ObjectMeta.Labels).To(Equal(map[string]string{
+				common.COMPONENT_NAME_LABEL: "XJoinDataSourceValidator",
+				"xjoin.datasource":          name,
+			}))
+			Expect(job.OwnerReferences).To(HaveLen(1))
+			Expect(job.OwnerReferences[0].Kind).To(Equal("XJoinDataSourceValidator"))
+			Expect(*job.Spec.BackoffLimit).To(Equal(int32(0)))
+			Expect(*job.Spec.ActiveDeadlineSeconds).To(Equal(int64(1800)))
+			Expect(*job.Spec.TTLSecondsAfterFinished).To(Equal(int32(3600)))
+
+			pod := job.Spec.Template
+			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			Expect(pod.Spec.Containers[0].Image).To(Equal("quay.io/cloudservices/xjoin-validation:latest"))
+
+			env := pod.Spec.Containers[0].Env
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "VALIDATION_TYPE", Value: "datasource"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "DATASOURCE_NAME", Value: "testdatasource"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "KAFKA_BOOTSTRAP_URL", Value: "localhost:9092"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "KAFKA_TOPIC", Value: "xjoindatasourcepipeline.testdatasource.1234"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "SCHEMA_REGISTRY_URL", Value: "http://apicurio:1080"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "FULL_AVRO_SCHEMA", Value: "{}"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_HOSTNAME", Value: "dbHost"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_PORT", Value: "8080"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_USERNAME", Value: "dbUsername"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_PASSWORD", Value: "dbPassword"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_NAME", Value: "dbName"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_TABLE", Value: "dbTable"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_TYPE", Value: "postgres"}))
+			for _, envVar := range env {
+				Expect(envVar.Name).ToNot(HavePrefix("testdatasource_DB_SSL"))
+			}
+		})
+
+		It("Should pass the database TLS options to the xjoin-validation pod", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:       namespace,
+				Name:            "test-datasource-validator",
+				K8sClient:       k8sClient,
+				PodLogReader:    &mocks.LogReader{},
+				DatabaseSSLMode: &v1alpha1.StringOrSecretParameter{Value: "verify-full"},
+			}
+			reconciler.ReconcileCreate()
+
+			jobs := reconciler.ListValidatorJobs()
+			Expect(len(jobs.Items)).To(Equal(1))
+			env := jobs.Items[0].Spec.Template.Spec.Containers[0].Env
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_SSL_MODE", Value: "verify-full"}))
+		})
+
+		It("Should mount the database certificates stored in secrets into the xjoin-validation pod", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:           namespace,
+				Name:                "test-datasource-validator",
+				K8sClient:           k8sClient,
+				PodLogReader:        &mocks.LogReader{},
+				DatabaseSSLRootCert: &v1alpha1.StringOrSecretParameter{Value: "/etc/pki/ca.crt"},
+				DatabaseSSLKey:      CreateCertificateSecret(namespace),
+			}
+			reconciler.ReconcileCreate()
+
+			jobs := reconciler.ListValidatorJobs()
+			Expect(len(jobs.Items)).To(Equal(1))
+			pod := jobs.Items[0].Spec.Template
+			env := pod.Spec.Containers[0].Env
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_SSL_ROOT_CERT", Value: "/etc/pki/ca.crt"}))
+			Expect(env).To(ContainElement(corev1.EnvVar{
+				Name: "testdatasource_DB_SSL_KEY", Value: "/etc/xjoin/db-ssl/db-ssl-0/tls.key"}))
+
+			Expect(pod.Spec.Volumes).To(HaveLen(1))
+			Expect(pod.Spec.Volumes[0].Name).To(Equal("db-ssl-0"))
+			Expect(pod.Spec.Volumes[0].Secret).ToNot(BeNil())
+			Expect(pod.Spec.Volumes[0].Secret.SecretName).To(Equal("db-client-cert"))
+			Expect(pod.Spec.Volumes[0].Secret.Items).To(Equal([]corev1.KeyToPath{{Key: "tls.key", Path: "tls.key"}}))
+			Expect(pod.Spec.Containers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{{
+				Name:      "db-ssl-0",
+				MountPath: "/etc/xjoin/db-ssl/db-ssl-0",
+				ReadOnly:  true,
+			}}))
+		})
+
+		It("Should pass the rowFilter to the xjoin-validation pod", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+				RowFilter:    &v1alpha1.RowFilter{Where: "deleted_at IS NULL"},
+			}
+			reconciler.ReconcileCreate()
+
+			jobs := reconciler.ListValidatorJobs()
+			Expect(len(jobs.Items)).To(Equal(1))
+			env := jobs.Items[0].Spec.Template.Spec.Containers[0].Env
+			Expect(env).To(ContainElement(corev1.EnvVar{Name: "testdatasource_DB_ROW_FILTER", Value: "deleted_at IS NULL"}))
+		})
+	})
+
+	Context("Reconcile Pod Running", func() {
+		It("Should wait for the validation job to finish", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			validator, result := reconciler.ReconcileRunning()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(validator.Status.ValidationPodPhase).To(Equal(datasource.ValidatorPodRunning))
+			Expect(reconciler.ListValidatorJobs().Items).To(HaveLen(1))
+		})
+	})
+
+	Context("Reconcile Pod Success", func() {
+		It("Should write the validation response to the XJoinDataSourcePipeline", func() {
+			name := "test-datasource-validator"
+
+			logBytes, err := os.ReadFile("./test/data/validator/success.log.txt")
+			checkError(err)
+
+			podLogReader := mocks.LogReader{}
+			podLogReader.
+				On("GetLogs", mock.Anything, namespace).Return(string(logBytes), err)
+
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         name,
+				K8sClient:    k8sClient,
+				PodLogReader: &podLogReader,
+			}
+			validator, result := reconciler.ReconcileSuccess("")
+			Expect(validator.Status.ValidationPodPhase).To(Equal(datasource.ValidatorPodSuccess))
+			Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 60 * time.Second}))
+
+			pipeline := reconciler.GetDataSourcePipeline()
+			Expect(pipeline.Status.ValidationResponse.Result).To(Equal("success"))
+		})
+
+		It("Should read the validation response from the termination message", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			validator, _ := reconciler.ReconcileSuccess(
+				`{"result":"valid","reason":"","message":"all rows match","details":{}}`)
+			Expect(validator.Status.ValidationPodPhase).To(Equal(datasource.ValidatorPodSuccess))
+
+			pipeline := reconciler.GetDataSourcePipeline()
+			Expect(pipeline.Status.ValidationResponse.Result).To(Equal("valid"))
+			Expect(pipeline.Status.ValidationResponse.Message).To(Equal("all rows match"))
+		})
+
+		It("Should keep the recorded validator job until its TTL passes", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			reconciler.ReconcileSuccess(`{"result":"valid","reason":"","message":"","details":{}}`)
+
+			jobs := reconciler.ListValidatorJobs()
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Annotations).To(HaveKeyWithValue(k8s.ValidationRecordedAnnotation, "true"))
+		})
+
+		It("Should not update the XJoinDataSourcePipeline when the validation result is malformed", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			validator, result := reconciler.ReconcileSuccess("panic: unable to connect to the database")
+			Expect(validator.Status.ValidationPodPhase).To(Equal(datasource.ValidatorPodFailed))
+			Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 60 * time.Second}))
+
+			pipeline := reconciler.GetDataSourcePipeline()
+			Expect(pipeline.Status.ValidationResponse.Result).To(Equal(""))
+			Expect(reconciler.ListValidatorJobs().Items[0].Annotations).To(
+				HaveKeyWithValue(k8s.ValidationRecordedAnnotation, "true"))
+		})
+	})
+
+	Context("Reconcile Pod Failure", func() {
+		It("Should record the failed validation job", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			validator, result := reconciler.ReconcileFailure()
+			Expect(validator.Status.ValidationPodPhase).To(Equal(datasource.ValidatorPodFailed))
+			Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 60 * time.Second}))
+
+			jobs := reconciler.ListValidatorJobs()
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Annotations).To(HaveKeyWithValue(k8s.ValidationRecordedAnnotation, "true"))
+		})
+	})
+
+	Context("Reconcile Deletion", func() {
+		It("Should not start a validation job for a deleted validator", func() {
+			reconciler := XJoinDataSourceValidatorTestReconciler{
+				Namespace:    namespace,
+				Name:         "test-datasource-validator",
+				K8sClient:    k8sClient,
+				PodLogReader: &mocks.LogReader{},
+			}
+			result := reconciler.ReconcileDelete()
+			Expect(result).To(Equal(reconcile.Result{}))
+			Expect(reconciler.ListValidatorJobs().Items).To(HaveLen(1))
+		})
+	})
+})
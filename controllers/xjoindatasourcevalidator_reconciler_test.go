@@ -0,0 +1,249 @@
+package controllers_test
+
+import (
+	"context"
+	. "github.com/onsi/gomega"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"github.com/redhatinsights/xjoin-operator/controllers"
+	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/k8s"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const dataSourceValidatorPipelineName = "test-data-source-pipeline"
+
+type XJoinDataSourceValidatorTestReconciler struct {
+	Namespace                  string
+	Name                       string
+	K8sClient                  client.Client
+	createdDataSourceValidator v1alpha1.XJoinDataSourceValidator
+	PodLogReader               k8s.LogReader
+	DatabaseSSLMode            *v1alpha1.StringOrSecretParameter
+	DatabaseSSLRootCert        *v1alpha1.StringOrSecretParameter
+	DatabaseSSLKey             *v1alpha1.StringOrSecretParameter
+	RowFilter                  *v1alpha1.RowFilter
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) ReconcileCreate() (v1alpha1.XJoinDataSourceValidator, reconcile.Result) {
+	x.createDataSourceValidator()
+	result := x.reconcile()
+	x.getDataSourceValidator(&x.createdDataSourceValidator)
+	return x.createdDataSourceValidator, result
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) ReconcileRunning() (v1alpha1.XJoinDataSourceValidator, reconcile.Result) {
+	x.createDataSourceValidator()
+	x.reconcile()
+	result := x.reconcile()
+	x.getDataSourceValidator(&x.createdDataSourceValidator)
+	return x.createdDataSourceValidator, result
+}
+
+// ReconcileSuccess completes the validation job with a pod whose container terminated with terminationMessage
+func (x *XJoinDataSourceValidatorTestReconciler) ReconcileSuccess(
+	terminationMessage string) (validator v1alpha1.XJoinDataSourceValidator, result reconcile.Result) {
+
+	x.createDataSourceValidator()
+	x.reconcile()
+
+	job := x.pendingValidatorJob()
+	x.createValidatorJobPod(job, terminationMessage)
+
+	job.Status.Succeeded = 1
+	err := x.K8sClient.Status().Update(context.Background(), &job)
+	checkError(err)
+
+	result = x.reconcile()
+	x.getDataSourceValidator(&validator)
+	return
+}
+
+// ReconcileFailure marks the validation job as failed
+func (x *XJoinDataSourceValidatorTestReconciler) ReconcileFailure() (validator v1alpha1.XJoinDataSourceValidator, result reconcile.Result) {
+	x.createDataSourceValidator()
+	x.reconcile()
+
+	job := x.pendingValidatorJob()
+	job.Status.Failed = 1
+	job.Status.Conditions = []batchv1.JobCondition{{
+		Type:    batchv1.JobFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  "DeadlineExceeded",
+		Message: "Job was active longer than specified deadline",
+	}}
+	err := x.K8sClient.Status().Update(context.Background(), &job)
+	checkError(err)
+
+	result = x.reconcile()
+	x.getDataSourceValidator(&validator)
+	return
+}
+
+// ReconcileDelete deletes the validator and reconciles its finalizer
+func (x *XJoinDataSourceValidatorTestReconciler) ReconcileDelete() reconcile.Result {
+	x.createDataSourceValidator()
+	x.reconcile()
+
+	Expect(x.K8sClient.Delete(context.Background(), &x.createdDataSourceValidator)).Should(Succeed())
+	return x.reconcile()
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) GetDataSourcePipeline() v1alpha1.XJoinDataSourcePipeline {
+	pipeline := v1alpha1.XJoinDataSourcePipeline{}
+	pipelineLookupKey := types.NamespacedName{Name: dataSourceValidatorPipelineName, Namespace: x.Namespace}
+	err := x.K8sClient.Get(context.Background(), pipelineLookupKey, &pipeline)
+	checkError(err)
+	return pipeline
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) ListValidatorJobs() *batchv1.JobList {
+	labels := client.MatchingLabels{}
+	labels["xjoin.datasource"] = x.Name
+	labels[common.COMPONENT_NAME_LABEL] = "XJoinDataSourceValidator"
+
+	jobs := &batchv1.JobList{}
+	err := k8sClient.List(context.Background(), jobs, client.InNamespace(x.Namespace), labels)
+	checkError(err)
+	return jobs
+}
+
+// pendingValidatorJob is the validation job whose result has not been recorded yet
+func (x *XJoinDataSourceValidatorTestReconciler) pendingValidatorJob() batchv1.Job {
+	var pending []batchv1.Job
+	for _, job := range x.ListValidatorJobs().Items {
+		if _, recorded := job.Annotations[k8s.ValidationRecordedAnnotation]; !recorded {
+			pending = append(pending, job)
+		}
+	}
+	Expect(pending).To(HaveLen(1))
+	return pending[0]
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) createValidatorJobPod(job batchv1.Job, terminationMessage string) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name,
+			Namespace: x.Namespace,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Spec: job.Spec.Template.Spec,
+	}
+	Expect(x.K8sClient.Create(context.Background(), pod)).Should(Succeed())
+
+	pod.Status.Phase = corev1.PodSucceeded
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  job.Spec.Template.Spec.Containers[0].Name,
+		Image: job.Spec.Template.Spec.Containers[0].Image,
+		State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 0,
+				Reason:   "Completed",
+				Message:  terminationMessage,
+			},
+		},
+	}}
+	Expect(x.K8sClient.Status().Update(context.Background(), pod)).Should(Succeed())
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) getDataSourceValidator(validator *v1alpha1.XJoinDataSourceValidator) {
+	validatorLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
+	Eventually(func() bool {
+		err := x.K8sClient.Get(context.Background(), validatorLookupKey, validator)
+		return err == nil
+	}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) createDataSourceValidator() {
+	ctx := context.Background()
+
+	//XJoinDataSourceValidator requires an XJoinDataSourcePipeline owner. Create one here
+	dataSourcePipeline := &v1alpha1.XJoinDataSourcePipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dataSourceValidatorPipelineName,
+			Namespace: x.Namespace,
+		},
+		Spec: v1alpha1.XJoinDataSourcePipelineSpec{
+			Name:       "testdatasource",
+			Version:    "1234",
+			AvroSchema: "{}",
+		},
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "xjoin.cloud.redhat.com/v1alpha1",
+			Kind:       "XJoinDataSourcePipeline",
+		},
+	}
+	Expect(x.K8sClient.Create(ctx, dataSourcePipeline)).Should(Succeed())
+
+	blockOwnerDeletion := true
+	controller := true
+	dataSourceValidator := &v1alpha1.XJoinDataSourceValidator{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      x.Name,
+			Namespace: x.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         common.DataSourcePipelineGVK.Version,
+					Kind:               common.DataSourcePipelineGVK.Kind,
+					Name:               dataSourceValidatorPipelineName,
+					Controller:         &controller,
+					BlockOwnerDeletion: &blockOwnerDeletion,
+					UID:                "a6778b9b-dfed-4d41-af53-5ebbcddb7535",
+				},
+			},
+		},
+		Spec: v1alpha1.XJoinDataSourceValidatorSpec{
+			Name:                "xjoindatasourcepipeline.testdatasource",
+			Version:             "1234",
+			AvroSchema:          "{}",
+			TopicName:           "xjoindatasourcepipeline.testdatasource.1234",
+			DatabaseHostname:    &v1alpha1.StringOrSecretParameter{Value: "dbHost"},
+			DatabasePort:        &v1alpha1.StringOrSecretParameter{Value: "8080"},
+			DatabaseUsername:    &v1alpha1.StringOrSecretParameter{Value: "dbUsername"},
+			DatabasePassword:    &v1alpha1.StringOrSecretParameter{Value: "dbPassword"},
+			DatabaseName:        &v1alpha1.StringOrSecretParameter{Value: "dbName"},
+			DatabaseTable:       &v1alpha1.StringOrSecretParameter{Value: "dbTable"},
+			DatabaseSSLMode:     x.DatabaseSSLMode,
+			DatabaseSSLRootCert: x.DatabaseSSLRootCert,
+			DatabaseSSLKey:      x.DatabaseSSLKey,
+			RowFilter:           x.RowFilter,
+			Pause:               false,
+		},
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "xjoin.cloud.redhat.com/v1alpha1",
+			Kind:       "XJoinDataSourceValidator",
+		},
+	}
+
+	Expect(x.K8sClient.Create(ctx, dataSourceValidator)).Should(Succeed())
+	x.getDataSourceValidator(&x.createdDataSourceValidator)
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) newXJoinDataSourceValidatorReconciler() *controllers.XJoinDataSourceValidatorReconciler {
+	return controllers.NewXJoinDataSourceValidatorReconciler(
+		x.K8sClient,
+		scheme.Scheme,
+		fake.NewSimpleClientset(),
+		testLogger,
+		record.NewFakeRecorder(10),
+		x.Namespace,
+		true,
+		x.PodLogReader)
+}
+
+func (x *XJoinDataSourceValidatorTestReconciler) reconcile() reconcile.Result {
+	ctx := context.Background()
+	xjoinDataSourceValidatorReconciler := x.newXJoinDataSourceValidatorReconciler()
+	validatorLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
+	result, err := xjoinDataSourceValidatorReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: validatorLookupKey})
+	checkError(err)
+	return result
+}
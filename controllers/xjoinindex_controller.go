@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-errors/errors"
 	"github.com/go-logr/logr"
 	"github.com/redhatinsights/xjoin-go-lib/pkg/utils"
@@ -12,7 +13,10 @@ import (
 	xjoinlogger "github.com/redhatinsights/xjoin-operator/controllers/log"
 	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
 	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	corev1 "k8s.io/api/core/v1"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -117,10 +121,6 @@ func (r *XJoinIndexReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 
 	p := parameters.BuildIndexParameters()
 
-	if p.Pause.Bool() {
-		return
-	}
-
 	configManager, err := config.NewManager(config.ManagerOptions{
 		Client:         r.Client,
 		Parameters:     p,
@@ -155,7 +155,16 @@ func (r *XJoinIndexReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 		return reconcile.Result{}, errors.Wrap(err, 0)
 	}
 
+	if p.Pause.Bool() && instance.GetDeletionTimestamp() == nil {
+		reqLogger.Info("Reconciliation is paused")
+		common.SetPausedCondition(instance, true)
+		return i.UpdateStatusAndRequeue(0)
+	}
+	common.SetPausedCondition(instance, false)
+
 	//check status of active and refreshing IndexPipelines, update instance.Status accordingly
+	var schemaEvolutionFailure string
+	var avroSchemaParsed *metav1.Condition
 	if instance.Status.ActiveVersion != "" {
 		indexPipelineNamespacedName := types.NamespacedName{
 			Name:      i.Instance.GetName() + "." + instance.Status.ActiveVersion,
@@ -167,7 +176,26 @@ func (r *XJoinIndexReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 			return reconcile.Result{}, errors.Wrap(err, 0)
 		}
 
-		instance.Status.ActiveVersionIsValid = activeIndexPipeline.Status.ValidationResponse.Result == "valid"
+		instance.Status.ActiveVersionValidationFailures = activeIndexPipeline.Status.ValidationFailedCount
+		instance.Status.ActiveVersionValidationReport = activeIndexPipeline.Status.ValidationReport
+		if common.ValidatedSinceSkipped(instance.Status.ActiveVersionValidationSkipped,
+			instance.Status.ActiveVersionValidationSkippedTime, activeIndexPipeline.Status.LastValidationTime) {
+			instance.SetActiveVersionValidationSkipped(false)
+		}
+		instance.Status.ActiveVersionIsValid = (ValidationIsWithinBudget(
+			activeIndexPipeline.Status, p.ValidationAttemptsThreshold.Int()) ||
+			instance.Status.ActiveVersionValidationSkipped) &&
+			common.ComponentsAreHealthy(activeIndexPipeline.Status.Conditions)
+
+		if condition := meta.FindStatusCondition(activeIndexPipeline.Status.Conditions,
+			xjoin.SchemaEvolvedConditionType); condition != nil && condition.Status == metav1.ConditionFalse {
+			schemaEvolutionFailure = condition.Message
+		}
+		avroSchemaParsed = meta.FindStatusCondition(
+			activeIndexPipeline.Status.Conditions, xjoin.AvroSchemaParsedConditionType)
+	} else {
+		instance.Status.ActiveVersionValidationFailures = 0
+		instance.Status.ActiveVersionValidationReport = ""
 	}
 
 	if instance.Status.RefreshingVersion != "" {
@@ -181,45 +209,135 @@ func (r *XJoinIndexReconciler) Reconcile(ctx context.Context, request ctrl.Reque
 			return reconcile.Result{}, errors.Wrap(err, 0)
 		}
 
-		instance.Status.RefreshingVersionIsValid = refreshingIndexPipeline.Status.ValidationResponse.Result == "valid"
+		//a refreshing version is only promoted once its latest validation is within the mismatch threshold
+		instance.Status.RefreshingVersionValidationFailures = refreshingIndexPipeline.Status.ValidationFailedCount
+		instance.Status.RefreshingVersionValidationReport = refreshingIndexPipeline.Status.ValidationReport
+		instance.Status.RefreshingVersionIsValid = ValidationIsWithinBudget(refreshingIndexPipeline.Status, 1) &&
+			common.ComponentsAreHealthy(refreshingIndexPipeline.Status.Conditions)
+
+		//the refreshing version parses the latest avroSchema
+		if condition := meta.FindStatusCondition(refreshingIndexPipeline.Status.Conditions,
+			xjoin.AvroSchemaParsedConditionType); condition != nil {
+			avroSchemaParsed = condition
+		}
+	} else {
+		instance.Status.RefreshingVersionValidationFailures = 0
+		instance.Status.RefreshingVersionValidationReport = ""
+	}
+
+	if avroSchemaParsed != nil {
+		condition := *avroSchemaParsed
+		condition.ObservedGeneration = instance.Generation
+		instance.SetCondition(condition)
+	} else {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, xjoin.AvroSchemaParsedConditionType)
 	}
 
-	//force refresh if datasource is updated
-	forceRefresh := false
-	for dataSourceName, dataSourceVersion := range instance.Status.DataSources {
-		dataSourceNamespacedName := types.NamespacedName{
-			Name:      dataSourceName,
-			Namespace: instance.GetNamespace(),
+	//previous releases recorded the datasources' resource versions, their current state is recorded without a refresh
+	if len(instance.Status.DataSources) > 0 {
+		if instance.Status.DataSourceVersions == nil {
+			instance.Status.DataSourceVersions = map[string]xjoin.XJoinIndexDataSource{}
+		}
+		for name := range instance.Status.DataSources {
+			if _, ok := instance.Status.DataSourceVersions[name]; !ok {
+				instance.Status.DataSourceVersions[name] = xjoin.XJoinIndexDataSource{}
+			}
 		}
-		dataSource, err := k8sUtils.FetchXJoinDataSource(i.Client, dataSourceNamespacedName, i.Context)
+		instance.Status.DataSources = nil
+	}
+
+	//force refresh when a datasource's active version or schema changes
+	dataSources, dataSourceRefreshReason, err := i.CheckDataSources()
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, 0)
+	}
+
+	indexReconcileMethods := NewReconcileMethods(i, common.IndexGVK)
+	reconciler := common.NewReconciler(indexReconcileMethods, instance, reqLogger, r.Recorder)
+	handledAnnotation, refreshReason, err := reconciler.HandleAction()
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+	if refreshReason == "" {
+		refreshReason = dataSourceRefreshReason
+	}
+	initAttemptsThreshold := p.ValidationRefreshAttemptsThreshold.Int()
+	if refreshReason == "" && initAttemptsThreshold > 0 &&
+		instance.Status.RefreshingVersionValidationFailures >= initAttemptsThreshold {
+		refreshReason = fmt.Sprintf("refreshing version %s failed validation %d times",
+			instance.Status.RefreshingVersion, instance.Status.RefreshingVersionValidationFailures)
+	}
+
+	if refreshReason == "" && schemaEvolutionFailure != "" {
+		refreshReason = "avroSchema evolution of active version " + instance.Status.ActiveVersion + " failed: " +
+			schemaEvolutionFailure
+	}
+
+	//an additive avroSchema change is applied to the active version instead of refreshing the index
+	if refreshReason == "" && instance.GetDeletionTimestamp() == nil {
+		var evolved bool
+		evolved, refreshReason, err = i.EvolveSchema()
 		if err != nil {
-			return reconcile.Result{}, errors.Wrap(err, 0)
+			return result, errors.Wrap(err, 0)
 		}
-		if dataSource.ResourceVersion != dataSourceVersion {
-			forceRefresh = true
+		if evolved {
+			instance.Status.SpecHash, err = k8sUtils.SpecHash(instance.GetSpec())
+			if err != nil {
+				return result, errors.Wrap(err, 0)
+			}
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "SchemaEvolved",
+				"avroSchema change applied to active version "+instance.Status.ActiveVersion)
 		}
 	}
 
-	indexReconcileMethods := NewReconcileMethods(i, common.IndexGVK)
-	reconciler := common.NewReconciler(indexReconcileMethods, instance, reqLogger)
-	err = reconciler.Reconcile(forceRefresh)
+	refreshingVersion := instance.Status.RefreshingVersion
+	err = reconciler.Reconcile(refreshReason)
 	if err != nil {
 		return result, errors.Wrap(err, 0)
 	}
 
+	//only record the new datasource state once a refresh has started so the refresh is retried otherwise
+	refreshStarted := instance.Status.RefreshingVersion != "" && instance.Status.RefreshingVersion != refreshingVersion
+	for name, dataSource := range dataSources {
+		if dataSourceRefreshReason == "" || refreshStarted {
+			instance.Status.DataSourceVersions[name] = dataSource
+		}
+	}
+
 	if instance.GetDeletionTimestamp() != nil {
 		//actual finalizer code is called via reconciler
 		return reconcile.Result{}, nil
 	}
 
-	instance.Status.SpecHash, err = k8sUtils.SpecHash(instance.Spec)
+	//move the alias after a refresh completes or the active version is rolled back
+	err = i.ReconcileAlias()
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	err = i.ReconcileWorkloads()
 	if err != nil {
 		return result, errors.Wrap(err, 0)
 	}
 
-	if i.GetInstance().Status.DataSources == nil {
-		i.GetInstance().Status.DataSources = map[string]string{}
+	instance.Status.SpecHash, err = k8sUtils.SpecHash(instance.GetSpec())
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	if i.GetInstance().Status.DataSourceVersions == nil {
+		i.GetInstance().Status.DataSourceVersions = map[string]xjoin.XJoinIndexDataSource{}
+	}
+
+	result, err = i.UpdateStatusAndRequeue(time.Second * 30)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	err = i.RemoveAnnotation(handledAnnotation)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
 	}
 
-	return i.UpdateStatusAndRequeue(time.Second * 30)
+	return result, nil
 }
@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -64,6 +65,523 @@ var _ = Describe("XJoinIndex", func() {
 		})
 	})
 
+	Context("Reconcile DataSource Changes", func() {
+		It("Should not refresh when a datasource is updated without changing its active version or schema", func() {
+			dataSourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source",
+				K8sClient: k8sClient,
+			}
+			dataSourceReconciler.ReconcileNew()
+			validDataSource := dataSourceReconciler.ReconcileValid()
+
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetDataSources(map[string]v1alpha1.XJoinIndexDataSource{validDataSource.Name: {}})
+			updatedIndex := reconciler.ReconcileUpdated()
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(createdIndex.Status.RefreshingVersion))
+			Expect(updatedIndex.Status.DataSourceVersions[validDataSource.Name].ActiveVersion).To(
+				Equal(validDataSource.Status.ActiveVersion))
+			Expect(updatedIndex.Status.DataSourceVersions[validDataSource.Name].AvroSchemaHash).ToNot(Equal(""))
+
+			validDataSource.SetLabels(map[string]string{"test": "updated"})
+			err := k8sClient.Update(context.Background(), &validDataSource)
+			checkError(err)
+
+			updatedIndex = reconciler.ReconcileUpdated()
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(createdIndex.Status.RefreshingVersion))
+			Expect(updatedIndex.Status.LastRefreshReason).ToNot(ContainSubstring(validDataSource.Name))
+		})
+
+		It("Should convert the datasource resource versions recorded by previous releases without refreshing", func() {
+			dataSourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source",
+				K8sClient: k8sClient,
+			}
+			dataSourceReconciler.ReconcileNew()
+			validDataSource := dataSourceReconciler.ReconcileValid()
+
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetLegacyDataSources(map[string]string{validDataSource.Name: validDataSource.ResourceVersion})
+
+			updatedIndex := reconciler.ReconcileUpdated()
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(createdIndex.Status.RefreshingVersion))
+			Expect(updatedIndex.Status.DataSources).To(BeEmpty())
+			Expect(updatedIndex.Status.DataSourceVersions[validDataSource.Name].ActiveVersion).To(
+				Equal(validDataSource.Status.ActiveVersion))
+			Expect(updatedIndex.Status.DataSourceVersions[validDataSource.Name].AvroSchemaHash).ToNot(Equal(""))
+		})
+
+		It("Should refresh when a datasource's active version changes", func() {
+			dataSourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      "test-data-source",
+				K8sClient: k8sClient,
+			}
+			dataSourceReconciler.ReconcileNew()
+			validDataSource := dataSourceReconciler.ReconcileValid()
+
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetDataSources(map[string]v1alpha1.XJoinIndexDataSource{validDataSource.Name: {}})
+			reconciler.ReconcileUpdated()
+
+			dataSourceReconciler.ReconcileActiveComponentsUnhealthy()
+			refreshedDataSource := dataSourceReconciler.ReconcileValid()
+			Expect(refreshedDataSource.Status.ActiveVersion).ToNot(Equal(validDataSource.Status.ActiveVersion))
+
+			updatedIndex := reconciler.ReconcileUpdated()
+			Expect(updatedIndex.Status.RefreshingVersion).ToNot(Equal(createdIndex.Status.RefreshingVersion))
+			Expect(updatedIndex.Status.LastRefreshReason).To(Equal(
+				"datasource " + validDataSource.Name + " active version changed from " +
+					validDataSource.Status.ActiveVersion + " to " + refreshedDataSource.Status.ActiveVersion))
+			Expect(updatedIndex.Status.DataSourceVersions[validDataSource.Name].ActiveVersion).To(
+				Equal(refreshedDataSource.Status.ActiveVersion))
+		})
+	})
+
+	Context("Reconcile Alias", func() {
+		It("Should move the alias to the active version's Elasticsearch index", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetActiveVersion(createdIndex.Status.RefreshingVersion)
+
+			updatedIndex, updateAliasBody := reconciler.ReconcileAlias("xjoinindexpipeline.test-index.1")
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(createdIndex.Status.RefreshingVersion))
+			Expect(updatedIndex.Status.ElasticsearchAlias).To(Equal("xjoinindex.test-index"))
+			Expect(updateAliasBody).To(MatchJSON(`{"actions":[` +
+				`{"remove":{"index":"xjoinindexpipeline.test-index.1","alias":"xjoinindex.test-index"}},` +
+				`{"add":{"index":"xjoinindexpipeline.test-index.` + createdIndex.Status.RefreshingVersion +
+				`","alias":"xjoinindex.test-index"}}]}`))
+		})
+
+		It("Should not move the alias when it already points to the active version's Elasticsearch index", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetActiveVersion(createdIndex.Status.RefreshingVersion)
+
+			updatedIndex, updateAliasBody := reconciler.ReconcileAlias(
+				"xjoinindexpipeline.test-index." + createdIndex.Status.RefreshingVersion)
+			Expect(updatedIndex.Status.ElasticsearchAlias).To(Equal("xjoinindex.test-index"))
+			Expect(updateAliasBody).To(Equal(""))
+		})
+	})
+
+	Context("Reconcile Validation Thresholds", func() {
+		It("Should keep the active version valid until the validation attempts threshold is reached", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			currentIndex := "xjoinindexpipeline.test-index." + version
+
+			reconciler.SetPipelineValidation(version, "invalid", 2)
+			updatedIndex, _ := reconciler.ReconcileAlias(currentIndex)
+			Expect(updatedIndex.Status.ActiveVersionIsValid).To(BeTrue())
+			Expect(updatedIndex.Status.ActiveVersionValidationFailures).To(Equal(2))
+			Expect(updatedIndex.Status.ActiveVersionValidationReport).To(
+				Equal("xjoin-validation-report-test-index." + version))
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(""))
+
+			reconciler.SetPipelineValidation(version, "invalid", 3)
+			updatedIndex, _ = reconciler.ReconcileAlias(currentIndex)
+			Expect(updatedIndex.Status.ActiveVersionIsValid).To(BeFalse())
+			Expect(updatedIndex.Status.ActiveVersionValidationFailures).To(Equal(3))
+			Expect(updatedIndex.Status.RefreshingVersion).ToNot(Equal(""))
+			Expect(updatedIndex.Status.LastRefreshReason).To(Equal("active version " + version + " is invalid"))
+		})
+
+		It("Should not promote a refreshing version whose latest validation failed", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetPipelineValidation(createdIndex.Status.RefreshingVersion, "invalid", 1)
+
+			updatedIndex := reconciler.ReconcileUpdated()
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(""))
+			Expect(updatedIndex.Status.RefreshingVersionIsValid).To(BeFalse())
+			Expect(updatedIndex.Status.RefreshingVersionValidationFailures).To(Equal(1))
+		})
+
+		It("Should restart the refresh once the initial sync validation attempts threshold is reached", func() {
+			SetGenericConfigValue(namespace, "validation.refresh.attempts.threshold", "2")
+
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+
+			reconciler.SetPipelineValidation(version, "invalid", 1)
+			updatedIndex := reconciler.ReconcileUpdated()
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(version))
+
+			reconciler.SetPipelineValidation(version, "invalid", 2)
+			updatedIndex = reconciler.ReconcileUpdated()
+			Expect(updatedIndex.Status.RefreshingVersion).ToNot(Equal(version))
+			Expect(updatedIndex.Status.LastRefreshReason).To(Equal(
+				"refreshing version " + version + " failed validation 2 times"))
+		})
+
+		It("Should use the validation attempts threshold of the spec over the ConfigMap", func() {
+			SetGenericConfigValue(namespace, "validation.attempts.threshold", "5")
+
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			currentIndex := "xjoinindexpipeline.test-index." + version
+
+			attemptsThreshold := 1
+			reconciler.SetValidation(&v1alpha1.XJoinIndexValidation{AttemptsThreshold: &attemptsThreshold})
+			reconciler.SetPipelineValidation(version, "invalid", 1)
+			updatedIndex, _ := reconciler.ReconcileAlias(currentIndex)
+			Expect(updatedIndex.Status.ActiveVersionIsValid).To(BeFalse())
+			Expect(updatedIndex.Status.LastRefreshReason).To(Equal("active version " + version + " is invalid"))
+		})
+	})
+
+	Context("Reconcile Schema Evolution", func() {
+		newValidIndex := func(configFileName string, refreshStrategy string) (IndexTestReconciler, string) {
+			dataSourceReconciler := DatasourceTestReconciler{
+				Namespace:           namespace,
+				Name:                "testdatasource",
+				K8sClient:           k8sClient,
+				RefreshStrategy:     refreshStrategy,
+				DatabaseSignalTable: "public.debezium_signal",
+			}
+			dataSourceReconciler.ReconcileNew()
+			validDataSource := dataSourceReconciler.ReconcileValid()
+
+			reconciler := IndexTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index",
+				ConfigFileName: configFileName,
+				K8sClient:      k8sClient,
+				DataSources: []DataSource{{
+					Name:                     validDataSource.Name,
+					Version:                  validDataSource.Status.ActiveVersion,
+					ApiCurioResponseFilename: "datasource-latest-version",
+				}},
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			reconciler.SetPipelineValidation(version, "valid", 0)
+			validIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(validIndex.Status.ActiveVersionIsValid).To(BeTrue())
+			return reconciler, version
+		}
+
+		It("Should update the active XJoinIndexPipeline in place when the avroSchema only adds fields", func() {
+			reconciler, version := newValidIndex(
+				"xjoinindex-with-referenced-field", v1alpha1.RefreshStrategyIncrementalSnapshot)
+
+			reconciler.SetAvroSchema("xjoinindex-with-added-field")
+			updatedIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(updatedIndex.Status.ActiveVersionIsValid).To(BeTrue())
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(""))
+
+			activeIndexPipeline := &v1alpha1.XJoinIndexPipeline{}
+			k8sGet(types.NamespacedName{Name: "test-index." + version, Namespace: namespace}, activeIndexPipeline)
+			Expect(activeIndexPipeline.Spec.AvroSchema).To(Equal(updatedIndex.Spec.AvroSchema))
+
+			//the existing documents get the added field when the datasource re-emits its records
+			dataSource := &v1alpha1.XJoinDataSource{}
+			k8sGet(types.NamespacedName{Name: "testdatasource", Namespace: namespace}, dataSource)
+			Expect(dataSource.GetAnnotations()).To(HaveKey(v1alpha1.RefreshAnnotation))
+
+			info := httpmock.GetCallCountInfo()
+			count := info["POST =~^http://apicurio:1080/apis/ccompat/v6/compatibility/subjects/xjoinindexpipeline\\.test-index\\..*-value/versions/latest$"]
+			Expect(count).To(Equal(1))
+
+			//the evolved spec is not refreshed by the next reconciliation
+			updatedIndex, _ = reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(""))
+		})
+
+		It("Should refresh when the avroSchema change removes fields", func() {
+			reconciler, version := newValidIndex(
+				"xjoinindex-with-added-field", v1alpha1.RefreshStrategyIncrementalSnapshot)
+
+			reconciler.SetAvroSchema("xjoinindex-with-referenced-field")
+			updatedIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(updatedIndex.Status.RefreshingVersion).ToNot(Equal(""))
+			Expect(updatedIndex.Status.LastRefreshReason).To(Equal(
+				"avroSchema change is not additive: testdatasource.ids is missing"))
+
+			activeIndexPipeline := &v1alpha1.XJoinIndexPipeline{}
+			k8sGet(types.NamespacedName{Name: "test-index." + version, Namespace: namespace}, activeIndexPipeline)
+			Expect(activeIndexPipeline.Spec.AvroSchema).ToNot(Equal(updatedIndex.Spec.AvroSchema))
+		})
+
+		It("Should refresh when a datasource cannot re-emit its records for the added fields", func() {
+			reconciler, version := newValidIndex("xjoinindex-with-referenced-field", v1alpha1.RefreshStrategyRecreate)
+
+			reconciler.SetAvroSchema("xjoinindex-with-added-field")
+			updatedIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(updatedIndex.Status.RefreshingVersion).ToNot(Equal(""))
+			Expect(updatedIndex.Status.LastRefreshReason).To(Equal(
+				"avroSchema change requires the records of datasource testdatasource to be re-emitted, " +
+					"which needs an IncrementalSnapshot refreshPolicy and a databaseSignalTable"))
+
+			activeIndexPipeline := &v1alpha1.XJoinIndexPipeline{}
+			k8sGet(types.NamespacedName{Name: "test-index." + version, Namespace: namespace}, activeIndexPipeline)
+			Expect(activeIndexPipeline.Spec.AvroSchema).ToNot(Equal(updatedIndex.Spec.AvroSchema))
+
+			dataSource := &v1alpha1.XJoinDataSource{}
+			k8sGet(types.NamespacedName{Name: "testdatasource", Namespace: namespace}, dataSource)
+			Expect(dataSource.GetAnnotations()).ToNot(HaveKey(v1alpha1.RefreshAnnotation))
+		})
+
+		It("Should refresh when the active XJoinIndexPipeline fails to evolve its components", func() {
+			reconciler, version := newValidIndex(
+				"xjoinindex-with-referenced-field", v1alpha1.RefreshStrategyIncrementalSnapshot)
+
+			activeIndexPipeline := &v1alpha1.XJoinIndexPipeline{}
+			k8sGet(types.NamespacedName{Name: "test-index." + version, Namespace: namespace}, activeIndexPipeline)
+			meta.SetStatusCondition(&activeIndexPipeline.Status.Conditions, metav1.Condition{
+				Type:    v1alpha1.SchemaEvolvedConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "EvolutionFailed",
+				Message: "unable to update the Elasticsearch mapping",
+			})
+			err := k8sClient.Status().Update(context.Background(), activeIndexPipeline)
+			checkError(err)
+
+			updatedIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(updatedIndex.Status.RefreshingVersion).ToNot(Equal(""))
+			Expect(updatedIndex.Status.LastRefreshReason).To(Equal(
+				"avroSchema evolution of active version " + version +
+					" failed: unable to update the Elasticsearch mapping"))
+		})
+	})
+
+	Context("Reconcile AvroSchema Errors", func() {
+		It("Should copy the AvroSchemaParsed condition of the refreshing XJoinIndexPipeline", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetPipelineCondition(createdIndex.Status.RefreshingVersion, metav1.Condition{
+				Type:    v1alpha1.AvroSchemaParsedConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  "ParseFailed",
+				Message: "unable to map field location: type geo_point has no Elasticsearch mapping",
+			})
+
+			updatedIndex := reconciler.ReconcileUpdated()
+			condition := meta.FindStatusCondition(updatedIndex.Status.Conditions, v1alpha1.AvroSchemaParsedConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(Equal("unable to map field location: type geo_point has no Elasticsearch mapping"))
+			Expect(condition.ObservedGeneration).To(Equal(updatedIndex.Generation))
+		})
+	})
+
+	Context("Reconcile Workloads", func() {
+		It("Should update the workloads of the existing XJoinIndexPipelines without refreshing", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			reconciler.SetPipelineValidation(version, "valid", 0)
+			reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+
+			replicas := int32(2)
+			workloads := &v1alpha1.XJoinIndexWorkloads{
+				Core: &v1alpha1.WorkloadSpec{Replicas: &replicas},
+			}
+			reconciler.SetWorkloads(workloads)
+			updatedIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(""))
+
+			pipeline := &v1alpha1.XJoinIndexPipeline{}
+			k8sGet(types.NamespacedName{Name: "test-index." + version, Namespace: namespace}, pipeline)
+			Expect(pipeline.Spec.Workloads).To(Equal(workloads))
+		})
+
+		It("Should update the validation thresholds of the existing XJoinIndexPipelines without refreshing", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			reconciler.SetPipelineValidation(version, "valid", 0)
+			reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+
+			percentageThreshold := 10
+			validation := &v1alpha1.XJoinIndexValidation{PercentageThreshold: &percentageThreshold}
+			reconciler.SetValidation(validation)
+			updatedIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(updatedIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(updatedIndex.Status.RefreshingVersion).To(Equal(""))
+
+			pipeline := &v1alpha1.XJoinIndexPipeline{}
+			k8sGet(types.NamespacedName{Name: "test-index." + version, Namespace: namespace}, pipeline)
+			Expect(pipeline.Spec.Validation).To(Equal(validation))
+		})
+	})
+
+	Context("Reconcile Annotations", func() {
+		It("Should start a refresh when the refresh annotation is set", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			reconciler.SetPipelineValidation(version, "valid", 0)
+
+			refreshingIndex := reconciler.ReconcileWithAnnotation(v1alpha1.RefreshAnnotation, "abc")
+			Expect(refreshingIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(refreshingIndex.Status.RefreshingVersion).ToNot(Equal(""))
+			Expect(refreshingIndex.Status.RefreshingVersion).ToNot(Equal(version))
+			Expect(refreshingIndex.Status.LastRefreshReason).To(ContainSubstring(v1alpha1.RefreshAnnotation))
+		})
+
+		It("Should promote the refreshing version without validation when the promote-refreshing annotation is set", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			reconciler.SetPipelineValidation(createdIndex.Status.RefreshingVersion, "invalid", 1)
+
+			promotedIndex := reconciler.ReconcileWithAnnotation(v1alpha1.PromoteRefreshingAnnotation, "true")
+			Expect(promotedIndex.Status.ActiveVersion).To(Equal(createdIndex.Status.RefreshingVersion))
+			Expect(promotedIndex.Status.RefreshingVersion).To(Equal(""))
+			Expect(promotedIndex.Status.ActiveVersionValidationSkipped).To(BeTrue())
+			Expect(promotedIndex.Status.ActiveVersionValidationSkippedTime).ToNot(BeNil())
+			Expect(promotedIndex.Status.ActiveVersionIsValid).To(BeTrue())
+		})
+
+		It("Should stop skipping the validation once the promoted version is validated", func() {
+			SetGenericConfigValue(namespace, "validation.attempts.threshold", "1")
+
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.ReconcileWithAnnotation(v1alpha1.PromoteRefreshingAnnotation, "true")
+
+			reconciler.SetPipelineValidation(version, "invalid", 1)
+			invalidIndex, _ := reconciler.ReconcileAlias("xjoinindexpipeline.test-index." + version)
+			Expect(invalidIndex.Status.ActiveVersionValidationSkipped).To(BeFalse())
+			Expect(invalidIndex.Status.ActiveVersionIsValid).To(BeFalse())
+			Expect(invalidIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(invalidIndex.Status.RefreshingVersion).ToNot(Equal(""))
+			Expect(invalidIndex.Status.LastRefreshReason).To(Equal("active version " + version + " is invalid"))
+		})
+
+		It("Should discard the refreshing version when the rollback annotation is set", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			reconciler.SetPipelineValidation(version, "valid", 0)
+			reconciler.ReconcileWithAnnotation(v1alpha1.RefreshAnnotation, "abc")
+
+			rolledBackIndex := reconciler.ReconcileWithAnnotation(v1alpha1.RollbackAnnotation, "true")
+			Expect(rolledBackIndex.Status.ActiveVersion).To(Equal(version))
+			Expect(rolledBackIndex.Status.RefreshingVersion).To(Equal(""))
+		})
+
+		It("Should ignore the promote-refreshing annotation when there is no refreshing version", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+			version := createdIndex.Status.RefreshingVersion
+			reconciler.SetActiveVersion(version)
+			reconciler.SetPipelineValidation(version, "valid", 0)
+
+			index := reconciler.ReconcileWithAnnotation(v1alpha1.PromoteRefreshingAnnotation, "true")
+			Expect(index.Status.ActiveVersion).To(Equal(version))
+			Expect(index.Status.ActiveVersionValidationSkipped).To(BeFalse())
+			Expect(index.Status.LastAction.Message).To(Equal("ignored, there is no refreshing version"))
+		})
+	})
+
+	Context("Reconcile Status", func() {
+		It("Should set the phase and conditions during the initial sync", func() {
+			reconciler := IndexTestReconciler{
+				Namespace: namespace,
+				Name:      "test-index",
+				K8sClient: k8sClient,
+			}
+			createdIndex := reconciler.ReconcileNew()
+
+			Expect(createdIndex.Status.Phase).To(Equal("INITIAL_SYNC"))
+			Expect(createdIndex.Status.ObservedGeneration).To(Equal(createdIndex.Generation))
+			Expect(meta.IsStatusConditionFalse(createdIndex.Status.Conditions, v1alpha1.ReadyConditionType)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(createdIndex.Status.Conditions, v1alpha1.RefreshingConditionType)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(createdIndex.Status.Conditions, v1alpha1.DegradedConditionType)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(createdIndex.Status.Conditions, v1alpha1.PausedConditionType)).To(BeTrue())
+		})
+	})
+
 	Context("Reconcile Delete", func() {
 		It("Should delete a XJoinIndexPipeline", func() {
 			reconciler := IndexTestReconciler{
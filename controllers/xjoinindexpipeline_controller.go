@@ -28,11 +28,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"strings"
 	"time"
 )
 
 const xjoinindexpipelineFinalizer = "finalizer.xjoin.indexpipeline.cloud.redhat.com"
+const elasticSearchSecretName = "xjoin-elasticsearch"
 
 type XJoinIndexPipelineReconciler struct {
 	Client    client.Client
@@ -114,7 +114,7 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		Client:         r.Client,
 		Parameters:     p,
 		ConfigMapNames: []string{"xjoin-generic"},
-		SecretNames:    []string{"xjoin-elasticsearch"},
+		SecretNames:    []string{elasticSearchSecretName},
 		Namespace:      instance.Namespace,
 		Spec:           instance.Spec,
 		Context:        ctx,
@@ -221,9 +221,55 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		SchemaNamespace: i.Instance.GetName(),
 	}
 	indexAvroSchema, err := indexAvroSchemaParser.Parse()
+	common.SetAvroSchemaParsedCondition(&instance.Status.Conditions, err)
+	if err != nil {
+		//the error is recorded on the status so it is visible on the XJoinIndexPipeline and its XJoinIndex
+		if _, updateErr := i.UpdateStatusAndRequeue(0); updateErr != nil {
+			i.Log.Error(updateErr, "Unable to record the avroSchema parse failure")
+		}
+		return result, errors.Wrap(err, 0)
+	}
+
+	elasticSearchSecret, err := k8sUtils.FetchSecret(i.Client, instance.Namespace, elasticSearchSecretName, ctx)
 	if err != nil {
 		return result, errors.Wrap(err, 0)
 	}
+	elasticSearchSecretChecksum, err := k8sUtils.SecretHash(elasticSearchSecret)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	var coreWorkloadSpec, subgraphWorkloadSpec, validatorWorkloadSpec *xjoin.WorkloadSpec
+	if instance.Spec.Workloads != nil {
+		coreWorkloadSpec = instance.Spec.Workloads.Core
+		subgraphWorkloadSpec = instance.Spec.Workloads.Subgraph
+		validatorWorkloadSpec = instance.Spec.Workloads.Validator
+	}
+	coreWorkload, err := components.ResolveWorkload(
+		components.DefaultCoreWorkload(), p.CoreWorkload.String(), coreWorkloadSpec)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+	subgraphWorkload, err := components.ResolveWorkload(
+		components.DefaultSubgraphWorkload(), p.SubgraphWorkload.String(), subgraphWorkloadSpec)
+	if err != nil {
+		return result, errors.Wrap(err, 0)
+	}
+
+	//the owner is already gone when the pipeline is finalized after its XJoinIndex is deleted
+	usesAlias := false
+	if len(instance.OwnerReferences) > 0 {
+		parentIndex, err := k8sUtils.FetchXJoinIndex(i.Client, types.NamespacedName{
+			Name:      instance.OwnerReferences[0].Name,
+			Namespace: instance.GetNamespace(),
+		}, ctx)
+		if err != nil && !k8errors.IsNotFound(err) {
+			return result, errors.Wrap(err, 0)
+		} else if err == nil {
+			usesAlias = parentIndex.Status.ActiveVersion == instance.Spec.Version ||
+				parentIndex.Status.PreviousVersion == instance.Spec.Version
+		}
+	}
 
 	componentManager := components.NewComponentManager(common.IndexPipelineGVK.Kind+"."+instance.Spec.Name, p.Version.String())
 
@@ -252,6 +298,13 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		Schema:   indexAvroSchema.AvroSchemaString,
 		Registry: confluentClient,
 	}))
+	//a refreshing version's subgraphs serve its own index. They are switched to the alias once the version becomes
+	//active and keep it as the previous version, so a swap or a rollback only rolls out the newly active version.
+	subgraphIndex := elasticSearchIndexComponent.Name()
+	if usesAlias {
+		subgraphIndex = ElasticsearchAliasName(instance.Spec.Name)
+	}
+
 	graphqlSchemaComponent := components.NewGraphQLSchema(components.GraphQLSchemaParameters{
 		Registry: registryRestClient,
 	})
@@ -265,6 +318,7 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		SchemaRegistryURL: p.SchemaRegistryProtocol.String() + "://" + p.SchemaRegistryHost.String() + ":" + p.SchemaRegistryPort.String(),
 		Namespace:         i.Instance.GetNamespace(),
 		Schema:            indexAvroSchema.AvroSchemaString,
+		Workload:          coreWorkload,
 	})
 	componentManager.AddComponent(&components.XJoinAPISubGraph{
 		Client:                i.Client,
@@ -273,10 +327,10 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		AvroSchema:            indexAvroSchema.AvroSchemaString,
 		Registry:              confluentClient,
 		ElasticSearchURL:      p.ElasticSearchURL.String(),
-		ElasticSearchUsername: p.ElasticSearchUsername.String(),
-		ElasticSearchPassword: p.ElasticSearchPassword.String(),
-		ElasticSearchIndex:    elasticSearchIndexComponent.Name(),
-		Image:                 "quay.io/cloudservices/xjoin-api-subgraph:latest", //TODO
+		ElasticSearchSecret:   elasticSearchSecretName,
+		ElasticSearchChecksum: elasticSearchSecretChecksum,
+		ElasticSearchIndex:    subgraphIndex,
+		Workload:              subgraphWorkload,
 		GraphQLSchemaName:     graphqlSchemaComponent.Name(),
 	})
 	componentManager.AddComponent(&components.XJoinIndexValidator{
@@ -287,6 +341,8 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		Pause:                  i.Parameters.Pause.Bool(),
 		ParentInstance:         i.Instance,
 		ElasticsearchIndexName: elasticSearchIndexComponent.Name(),
+		Workload:               validatorWorkloadSpec,
+		Validation:             instance.Spec.Validation,
 	})
 
 	for _, customSubgraphImage := range instance.Spec.CustomSubgraphImages {
@@ -295,6 +351,11 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 			Suffix:   customSubgraphImage.Name,
 		})
 		componentManager.AddComponent(customSubgraphGraphQLSchemaComponent)
+
+		//the image of a custom subgraph already includes its tag
+		customSubgraphWorkload := subgraphWorkload
+		customSubgraphWorkload.Image = customSubgraphImage.Image
+		customSubgraphWorkload.Tag = ""
 		componentManager.AddComponent(&components.XJoinAPISubGraph{
 			Client:                i.Client,
 			Context:               i.Context,
@@ -302,10 +363,10 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 			AvroSchema:            indexAvroSchema.AvroSchemaString,
 			Registry:              confluentClient,
 			ElasticSearchURL:      p.ElasticSearchURL.String(),
-			ElasticSearchUsername: p.ElasticSearchUsername.String(),
-			ElasticSearchPassword: p.ElasticSearchPassword.String(),
-			ElasticSearchIndex:    elasticSearchIndexComponent.Name(),
-			Image:                 customSubgraphImage.Image,
+			ElasticSearchSecret:   elasticSearchSecretName,
+			ElasticSearchChecksum: elasticSearchSecretChecksum,
+			ElasticSearchIndex:    subgraphIndex,
+			Workload:              customSubgraphWorkload,
 			Suffix:                customSubgraphImage.Name,
 			GraphQLSchemaName:     customSubgraphGraphQLSchemaComponent.Name(),
 		})
@@ -336,31 +397,56 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		return reconcile.Result{}, errors.Wrap(err, 0)
 	}
 
+	//the XJoinIndex only updates the avroSchema of an existing pipeline when the change is additive
+	avroSchemaHash, err := k8sUtils.SpecHash(instance.Spec.AvroSchema)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, 0)
+	}
+	if instance.Status.AvroSchemaHash != "" && instance.Status.AvroSchemaHash != avroSchemaHash {
+		reqLogger.Info("Evolving components to the updated avroSchema")
+		err = componentManager.EvolveSchemaAll()
+		if err != nil {
+			//the evolution is retried until the XJoinIndex replaces this version with a refresh
+			reqLogger.Error(err, "Unable to evolve the components to the updated avroSchema")
+		} else {
+			instance.Status.AvroSchemaHash = avroSchemaHash
+		}
+		common.SetSchemaEvolvedCondition(&instance.Status.Conditions, err)
+	} else {
+		instance.Status.AvroSchemaHash = avroSchemaHash
+	}
+
+	err = componentManager.RolloutAll()
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, 0)
+	}
+
 	problems, err := componentManager.CheckForDeviations()
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, 0)
 	}
 
 	if len(problems) > 0 {
-		//TODO: set instance status to invalid, add problems to status
-		reqLogger.Info("TODO: Set Instance status to invalid, add", "problems", len(problems))
+		reqLogger.Info("Component deviations found", "problems", problems)
 	}
+	common.SetComponentsHealthyCondition(&instance.Status.Conditions, problems)
 
-	//build list of datasources
-	dataSources := make(map[string]string)
+	//build list of datasources, the XJoinIndex records the state of each one when it starts a refresh
+	var dataSourceNames []string
 	for _, ref := range indexAvroSchema.References {
-		//get each datasource name and resource version
-		name := strings.Split(ref.Name, "xjoindatasourcepipeline.")[1]
-		name = strings.Split(name, ".Value")[0]
+		name, err := DataSourceName(ref)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, 0)
+		}
 		datasourceNamespacedName := types.NamespacedName{
 			Name:      name,
 			Namespace: i.Instance.GetNamespace(),
 		}
-		datasource, err := k8sUtils.FetchXJoinDataSource(i.Client, datasourceNamespacedName, ctx)
+		_, err = k8sUtils.FetchXJoinDataSource(i.Client, datasourceNamespacedName, ctx)
 		if err != nil {
 			return reconcile.Result{}, errors.Wrap(err, 0)
 		}
-		dataSources[name] = datasource.ResourceVersion
+		dataSourceNames = append(dataSourceNames, name)
 	}
 
 	//update parent status
@@ -373,8 +459,13 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		return reconcile.Result{}, errors.Wrap(err, 0)
 	}
 
-	if !reflect.DeepEqual(xjoinIndex.Status.DataSources, dataSources) {
-		xjoinIndex.Status.DataSources = dataSources
+	dataSources := make(map[string]xjoin.XJoinIndexDataSource)
+	for _, name := range dataSourceNames {
+		dataSources[name] = xjoinIndex.Status.DataSourceVersions[name]
+	}
+
+	if !reflect.DeepEqual(xjoinIndex.Status.DataSourceVersions, dataSources) {
+		xjoinIndex.Status.DataSourceVersions = dataSources
 
 		if err := i.Client.Status().Update(ctx, xjoinIndex); err != nil {
 			if k8errors.IsConflict(err) {
@@ -386,5 +477,5 @@ func (r *XJoinIndexPipelineReconciler) Reconcile(ctx context.Context, request ct
 		}
 	}
 
-	return reconcile.Result{RequeueAfter: time.Second * 30}, nil
+	return i.UpdateStatusAndRequeue(time.Second * 30)
 }
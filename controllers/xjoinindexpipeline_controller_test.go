@@ -12,6 +12,8 @@ import (
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -295,6 +297,7 @@ var _ = Describe("XJoinIndexPipeline", func() {
 			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
 			Expect(deployment.Spec.Template.Spec.Containers[0].Name).To(Equal("xjoinindexpipeline-test-index-pipeline-1234"))
 			Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("quay.io/cloudservices/xjoin-api-subgraph:latest"))
+			optional := true
 			Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(HaveLen(9))
 			Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElements([]corev1.EnvVar{
 				{
@@ -323,14 +326,24 @@ var _ = Describe("XJoinIndexPipeline", func() {
 					ValueFrom: nil,
 				},
 				{
-					Name:      "ELASTIC_SEARCH_USERNAME",
-					Value:     "xjoin",
-					ValueFrom: nil,
+					Name: "ELASTIC_SEARCH_USERNAME",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "xjoin-elasticsearch"},
+							Key:                  "username",
+							Optional:             &optional,
+						},
+					},
 				},
 				{
-					Name:      "ELASTIC_SEARCH_PASSWORD",
-					Value:     "xjoin1337",
-					ValueFrom: nil,
+					Name: "ELASTIC_SEARCH_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "xjoin-elasticsearch"},
+							Key:                  "password",
+							Optional:             &optional,
+						},
+					},
 				},
 				{
 					Name:      "ELASTIC_SEARCH_INDEX",
@@ -432,6 +445,7 @@ var _ = Describe("XJoinIndexPipeline", func() {
 			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
 			Expect(deployment.Spec.Template.Spec.Containers[0].Name).To(Equal(deploymentName))
 			Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("quay.io/cloudservices/host-inventory-subgraph:latest"))
+			optional := true
 			Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(HaveLen(9))
 			Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElements([]corev1.EnvVar{
 				{
@@ -460,14 +474,24 @@ var _ = Describe("XJoinIndexPipeline", func() {
 					ValueFrom: nil,
 				},
 				{
-					Name:      "ELASTIC_SEARCH_USERNAME",
-					Value:     "xjoin",
-					ValueFrom: nil,
+					Name: "ELASTIC_SEARCH_USERNAME",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "xjoin-elasticsearch"},
+							Key:                  "username",
+							Optional:             &optional,
+						},
+					},
 				},
 				{
-					Name:      "ELASTIC_SEARCH_PASSWORD",
-					Value:     "xjoin1337",
-					ValueFrom: nil,
+					Name: "ELASTIC_SEARCH_PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "xjoin-elasticsearch"},
+							Key:                  "password",
+							Optional:             &optional,
+						},
+					},
 				},
 				{
 					Name:      "ELASTIC_SEARCH_INDEX",
@@ -529,13 +553,135 @@ var _ = Describe("XJoinIndexPipeline", func() {
 			count := info["GET http://apicurio:1080/apis/ccompat/v6/subjects/xjoindatasourcepipeline.testdatasource."+createdDataSource.Status.ActiveVersion+"-value/versions/latest"]
 			Expect(count).To(Equal(1))
 
+			//once to check if it exists, once to check for deviations
 			count = info["GET http://localhost:9200/_ingest/pipeline/xjoinindexpipeline.test-index-pipeline.1234"]
-			Expect(count).To(Equal(1))
+			Expect(count).To(Equal(2))
 
 			count = info["PUT http://localhost:9200/_ingest/pipeline/xjoinindexpipeline.test-index-pipeline.1234"]
 			Expect(count).To(Equal(1))
 		})
 
+		It("Should map the Avro types of the fields to Elasticsearch types", func() {
+			SetGenericConfigValue(namespace, "elasticsearch.index.template",
+				`{"mappings":{"properties":{{.ElasticSearchProperties}}}}`)
+
+			dataSourceName := "testdatasource"
+			datasourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      dataSourceName,
+				K8sClient: k8sClient,
+			}
+			datasourceReconciler.ReconcileNew()
+			createdDataSource := datasourceReconciler.ReconcileValid()
+
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex-with-referenced-field",
+				K8sClient:      k8sClient,
+				DataSources: []DataSource{{
+					Name:                     dataSourceName,
+					Version:                  createdDataSource.Status.ActiveVersion,
+					ApiCurioResponseFilename: "datasource-with-typed-fields",
+				}},
+			}
+			reconciler.ReconcileNew()
+
+			Expect(reconciler.ElasticsearchIndex()).To(MatchJSON(`{"mappings":{"properties":{"testdatasource":{` +
+				`"type":"object","properties":{` +
+				`"id":{"type":"keyword"},` +
+				`"count":{"type":"integer"},` +
+				`"size":{"type":"long"},` +
+				`"ratio":{"type":"float"},` +
+				`"score":{"type":"double"},` +
+				`"created_at":{"type":"date","format":"epoch_millis"},` +
+				`"updated_at":{"type":"long"},` +
+				`"birthday":{"type":"integer"},` +
+				`"price":{"type":"double"},` +
+				`"state":{"type":"keyword"},` +
+				`"labels":{"type":"flattened"},` +
+				`"blob":{"type":"binary"},` +
+				`"tags":{"type":"keyword"}}}}}}`))
+		})
+
+		It("Should map the Avro logicalTypes and connect.name of fields without xjoin.type", func() {
+			SetGenericConfigValue(namespace, "elasticsearch.index.template",
+				`{"mappings":{"properties":{{.ElasticSearchProperties}}}}`)
+
+			dataSourceName := "testdatasource"
+			datasourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      dataSourceName,
+				K8sClient: k8sClient,
+			}
+			datasourceReconciler.ReconcileNew()
+			createdDataSource := datasourceReconciler.ReconcileValid()
+
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex-with-referenced-field",
+				K8sClient:      k8sClient,
+				DataSources: []DataSource{{
+					Name:                     dataSourceName,
+					Version:                  createdDataSource.Status.ActiveVersion,
+					ApiCurioResponseFilename: "datasource-with-logical-types",
+				}},
+			}
+			reconciler.ReconcileNew()
+
+			Expect(reconciler.ElasticsearchIndex()).To(MatchJSON(`{"mappings":{"properties":{"testdatasource":{` +
+				`"type":"object","properties":{` +
+				`"id":{"type":"keyword"},` +
+				`"facts":{"type":"object"},` +
+				`"created_at":{"type":"date","format":"epoch_millis"},` +
+				`"updated_at":{"type":"long"},` +
+				`"birthday":{"type":"integer"},` +
+				`"price":{"type":"double"},` +
+				`"owner_id":{"type":"keyword"},` +
+				`"opened_at":{"type":"integer"},` +
+				`"visits":{"type":"date","format":"epoch_millis"}}}}}}`))
+
+			//the json field is parsed from its string by the ingest pipeline
+			info := httpmock.GetCallCountInfo()
+			count := info["PUT http://localhost:9200/_ingest/pipeline/xjoinindexpipeline.test-index-pipeline.1234"]
+			Expect(count).To(Equal(1))
+		})
+
+		It("Should fail to reconcile when a field's type has no Elasticsearch mapping", func() {
+			dataSourceName := "testdatasource"
+			datasourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      dataSourceName,
+				K8sClient: k8sClient,
+			}
+			datasourceReconciler.ReconcileNew()
+			createdDataSource := datasourceReconciler.ReconcileValid()
+
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex-with-referenced-field",
+				K8sClient:      k8sClient,
+				DataSources: []DataSource{{
+					Name:                     dataSourceName,
+					Version:                  createdDataSource.Status.ActiveVersion,
+					ApiCurioResponseFilename: "datasource-with-unknown-type",
+				}},
+			}
+			err := reconciler.ReconcileNewWithError()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("unable to map field location: type geo_point has no Elasticsearch mapping"))
+
+			indexPipeline := &v1alpha1.XJoinIndexPipeline{}
+			k8sGet(types.NamespacedName{Name: "test-index-pipeline", Namespace: namespace}, indexPipeline)
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.AvroSchemaParsedConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("ParseFailed"))
+			Expect(condition.Message).To(Equal(err.Error()))
+		})
+
 		It("Should create an XJoinIndexValidation resource", func() {
 			configFileName := "xjoinindex"
 			reconciler := XJoinIndexPipelineTestReconciler{
@@ -586,6 +732,516 @@ var _ = Describe("XJoinIndexPipeline", func() {
 		})
 	})
 
+	Context("Reconcile Workloads", func() {
+		It("Should configure the xjoin-core deployment from the spec and the xjoin-generic ConfigMap", func() {
+			SetGenericConfigValue(namespace, "xjoin.core.workload",
+				`{"tag":"v1.2.3","nodeSelector":{"pool":"xjoin"},"env":[{"name":"JAVA_OPTS","value":"-Xmx1g"}]}`)
+
+			coreReplicas := int32(3)
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				Workloads: &v1alpha1.XJoinIndexWorkloads{
+					Core: &v1alpha1.WorkloadSpec{
+						Replicas: &coreReplicas,
+						Resources: &corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("2Gi"),
+							},
+						},
+						Tolerations: []corev1.Toleration{{
+							Key:      "dedicated",
+							Operator: corev1.TolerationOpEqual,
+							Value:    "xjoin",
+							Effect:   corev1.TaintEffectNoSchedule,
+						}},
+					},
+				},
+			}
+			reconciler.ReconcileNew()
+
+			deploymentName := "xjoin-core-xjoinindexpipeline-test-index-pipeline-1234"
+			deploymentLookupKey := types.NamespacedName{Name: deploymentName, Namespace: namespace}
+			deployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), deploymentLookupKey, deployment)
+			checkError(err)
+
+			Expect(deployment.Spec.Replicas).To(Equal(&coreReplicas))
+			Expect(deployment.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"pool": "xjoin"}))
+			Expect(deployment.Spec.Template.Spec.Tolerations).To(Equal(reconciler.Workloads.Core.Tolerations))
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("quay.io/cloudservices/xjoin-core:v1.2.3"))
+			Expect(container.ImagePullPolicy).To(Equal(corev1.PullAlways))
+			Expect(container.Resources.Limits.Memory().String()).To(Equal("2Gi"))
+			Expect(container.Resources.Limits.Cpu().String()).To(Equal("250m"))
+			Expect(container.Resources.Requests.Memory().String()).To(Equal("64Mi"))
+			Expect(container.Resources.Requests.Cpu().String()).To(Equal("100m"))
+			Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "JAVA_OPTS", Value: "-Xmx1g"}))
+			Expect(container.Env).To(ContainElement(HaveField("Name", "SOURCE_TOPICS")))
+
+			indexPipeline := reconciler.ReconcileExisting()
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment " + deploymentName))
+		})
+
+		It("Should pin the xjoin-api-subgraph image to a digest and pass the validator workload to the XJoinIndexValidator", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				Workloads: &v1alpha1.XJoinIndexWorkloads{
+					Subgraph: &v1alpha1.WorkloadSpec{
+						Tag:             "sha256:0123456789abcdef",
+						ImagePullPolicy: corev1.PullIfNotPresent,
+					},
+					Validator: &v1alpha1.WorkloadSpec{
+						Tag: "v2",
+					},
+				},
+			}
+			reconciler.ReconcileNew()
+
+			deploymentLookupKey := types.NamespacedName{
+				Name: "xjoinindexpipeline-test-index-pipeline-1234", Namespace: namespace}
+			deployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), deploymentLookupKey, deployment)
+			checkError(err)
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("quay.io/cloudservices/xjoin-api-subgraph@sha256:0123456789abcdef"))
+			Expect(container.ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+			Expect(container.Resources.Limits.Memory().String()).To(Equal("512Mi"))
+
+			validatorLookupKey := types.NamespacedName{
+				Name: "xjoinindexpipeline.test-index-pipeline.1234", Namespace: namespace}
+			validator := &v1alpha1.XJoinIndexValidator{}
+			err = k8sClient.Get(context.Background(), validatorLookupKey, validator)
+			checkError(err)
+			Expect(validator.Spec.Workload).To(Equal(reconciler.Workloads.Validator))
+		})
+
+		It("Should roll out changed workloads to the existing deployments and XJoinIndexValidator", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			coreReplicas := int32(2)
+			workloads := &v1alpha1.XJoinIndexWorkloads{
+				Core: &v1alpha1.WorkloadSpec{
+					Replicas: &coreReplicas,
+					Env:      []corev1.EnvVar{{Name: "JAVA_OPTS", Value: "-Xmx2g"}},
+				},
+				Subgraph: &v1alpha1.WorkloadSpec{
+					Tag: "v2",
+					Resources: &corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+				Validator: &v1alpha1.WorkloadSpec{
+					Tag: "v3",
+				},
+			}
+			indexPipeline := reconciler.ReconcileWorkloads(workloads)
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment "))
+
+			coreDeployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), types.NamespacedName{
+				Name: "xjoin-core-xjoinindexpipeline-test-index-pipeline-1234", Namespace: namespace}, coreDeployment)
+			checkError(err)
+			Expect(coreDeployment.Spec.Replicas).To(Equal(&coreReplicas))
+			Expect(coreDeployment.Spec.Template.Spec.Containers[0].Env).To(
+				ContainElement(corev1.EnvVar{Name: "JAVA_OPTS", Value: "-Xmx2g"}))
+
+			subgraphDeployment := &v1.Deployment{}
+			err = k8sClient.Get(context.Background(), types.NamespacedName{
+				Name: "xjoinindexpipeline-test-index-pipeline-1234", Namespace: namespace}, subgraphDeployment)
+			checkError(err)
+			container := subgraphDeployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).To(Equal("quay.io/cloudservices/xjoin-api-subgraph:v2"))
+			Expect(container.Resources.Limits.Memory().String()).To(Equal("1Gi"))
+
+			validator := &v1alpha1.XJoinIndexValidator{}
+			err = k8sClient.Get(context.Background(), types.NamespacedName{
+				Name: "xjoinindexpipeline.test-index-pipeline.1234", Namespace: namespace}, validator)
+			checkError(err)
+			Expect(validator.Spec.Workload).To(Equal(workloads.Validator))
+		})
+
+		It("Should remove an env var that was removed from the workload without a deviation", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				Workloads: &v1alpha1.XJoinIndexWorkloads{
+					Core: &v1alpha1.WorkloadSpec{
+						Env: []corev1.EnvVar{{Name: "JAVA_OPTS", Value: "-Xmx1g"}},
+					},
+				},
+			}
+			reconciler.ReconcileNew()
+
+			indexPipeline := reconciler.ReconcileWorkloads(&v1alpha1.XJoinIndexWorkloads{})
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment "))
+
+			coreDeployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), types.NamespacedName{
+				Name: "xjoin-core-xjoinindexpipeline-test-index-pipeline-1234", Namespace: namespace}, coreDeployment)
+			checkError(err)
+			Expect(coreDeployment.Spec.Template.Spec.Containers[0].Env).ToNot(
+				ContainElement(HaveField("Name", "JAVA_OPTS")))
+			Expect(coreDeployment.Spec.Template.Spec.Containers[0].Env).To(
+				ContainElement(HaveField("Name", "SOURCE_TOPICS")))
+		})
+
+		It("Should not report an env var added to a deployment outside of the operator as a deviation", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			deploymentName := "xjoin-core-xjoinindexpipeline-test-index-pipeline-1234"
+			coreDeployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), types.NamespacedName{
+				Name: deploymentName, Namespace: namespace}, coreDeployment)
+			checkError(err)
+			coreDeployment.Spec.Template.Spec.Containers[0].Env = append(
+				coreDeployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "DEBUG", Value: "true"})
+			err = k8sClient.Update(context.Background(), coreDeployment)
+			checkError(err)
+
+			indexPipeline := reconciler.ReconcileExisting()
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment " + deploymentName))
+		})
+	})
+
+	Context("Reconcile Secret Rotation", func() {
+		It("Should roll out the xjoin-api-subgraph deployment when the Elasticsearch secret changes", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			deploymentName := "xjoinindexpipeline-test-index-pipeline-1234"
+			deploymentLookupKey := types.NamespacedName{Name: deploymentName, Namespace: namespace}
+			deployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), deploymentLookupKey, deployment)
+			checkError(err)
+			checksum := deployment.Spec.Template.Annotations["xjoin.cloud.redhat.com/secret-checksum"]
+			Expect(checksum).ToNot(BeEmpty())
+
+			secret := &corev1.Secret{}
+			err = k8sClient.Get(context.Background(), client.ObjectKey{Name: "xjoin-elasticsearch", Namespace: namespace}, secret)
+			checkError(err)
+			secret.Data["password"] = []byte("rotated")
+			err = k8sClient.Update(context.Background(), secret)
+			checkError(err)
+
+			indexPipeline := reconciler.ReconcileExisting()
+			err = k8sClient.Get(context.Background(), deploymentLookupKey, deployment)
+			checkError(err)
+			Expect(deployment.Spec.Template.Annotations["xjoin.cloud.redhat.com/secret-checksum"]).ToNot(Equal(checksum))
+
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment " + deploymentName))
+		})
+
+		It("Should point the xjoin-api-subgraph deployment at the XJoinIndex's alias once the version is active", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			elasticsearchIndexEnv := func() string {
+				deployment := &v1.Deployment{}
+				err := k8sClient.Get(context.Background(), types.NamespacedName{
+					Name: "xjoinindexpipeline-test-index-pipeline-1234", Namespace: namespace}, deployment)
+				checkError(err)
+				for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+					if env.Name == "ELASTIC_SEARCH_INDEX" {
+						return env.Value
+					}
+				}
+				return ""
+			}
+			Expect(elasticsearchIndexEnv()).To(Equal("xjoinindexpipeline.test-index-pipeline.1234"))
+
+			xjoinIndex := &v1alpha1.XJoinIndex{}
+			err := k8sClient.Get(context.Background(),
+				types.NamespacedName{Name: "test-xjoin-index", Namespace: namespace}, xjoinIndex)
+			checkError(err)
+			xjoinIndex.Status.ActiveVersion = "1234"
+			err = k8sClient.Status().Update(context.Background(), xjoinIndex)
+			checkError(err)
+
+			indexPipeline := reconciler.ReconcileExisting()
+			Expect(elasticsearchIndexEnv()).To(Equal("xjoinindex.test-index-pipeline"))
+
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment xjoinindexpipeline-test-index-pipeline-1234"))
+		})
+
+		It("Should not roll out the xjoin-api-subgraph deployment when the version becomes the previous version", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			xjoinIndex := &v1alpha1.XJoinIndex{}
+			indexLookupKey := types.NamespacedName{Name: "test-xjoin-index", Namespace: namespace}
+			err := k8sClient.Get(context.Background(), indexLookupKey, xjoinIndex)
+			checkError(err)
+			xjoinIndex.Status.ActiveVersion = "1234"
+			err = k8sClient.Status().Update(context.Background(), xjoinIndex)
+			checkError(err)
+			reconciler.ReconcileExisting()
+
+			deploymentLookupKey := types.NamespacedName{
+				Name: "xjoinindexpipeline-test-index-pipeline-1234", Namespace: namespace}
+			deployment := &v1.Deployment{}
+			err = k8sClient.Get(context.Background(), deploymentLookupKey, deployment)
+			checkError(err)
+			activeGeneration := deployment.Generation
+
+			err = k8sClient.Get(context.Background(), indexLookupKey, xjoinIndex)
+			checkError(err)
+			xjoinIndex.Status.ActiveVersion = "5678"
+			xjoinIndex.Status.PreviousVersion = "1234"
+			err = k8sClient.Status().Update(context.Background(), xjoinIndex)
+			checkError(err)
+			indexPipeline := reconciler.ReconcileExisting()
+
+			err = k8sClient.Get(context.Background(), deploymentLookupKey, deployment)
+			checkError(err)
+			Expect(deployment.Generation).To(Equal(activeGeneration))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name: "ELASTIC_SEARCH_INDEX", Value: "xjoinindex.test-index-pipeline"}))
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment xjoinindexpipeline-test-index-pipeline-1234"))
+		})
+	})
+
+	Context("Reconcile Schema Evolution", func() {
+		It("Should update the existing components in place when fields are added to the avroSchema", func() {
+			dataSourceName := "testdatasource"
+			datasourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      dataSourceName,
+				K8sClient: k8sClient,
+			}
+			datasourceReconciler.ReconcileNew()
+			createdDataSource := datasourceReconciler.ReconcileValid()
+
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex-with-referenced-field",
+				K8sClient:      k8sClient,
+				DataSources: []DataSource{{
+					Name:                     dataSourceName,
+					Version:                  createdDataSource.Status.ActiveVersion,
+					ApiCurioResponseFilename: "datasource-latest-version",
+				}},
+			}
+			createdIndexPipeline := reconciler.ReconcileNew()
+			Expect(createdIndexPipeline.Status.AvroSchemaHash).ToNot(BeEmpty())
+
+			indexPipeline, properties := reconciler.ReconcileAvroSchema("xjoinindex-with-added-field")
+			Expect(indexPipeline.Status.AvroSchemaHash).ToNot(Equal(createdIndexPipeline.Status.AvroSchemaHash))
+			Expect(properties).To(MatchJSON(`{"testdatasource":{"type":"object","properties":{` +
+				`"id":{"type":"keyword"},"facts":{"type":"object"},"ids":{"type":"keyword"}}}}`))
+
+			info := httpmock.GetCallCountInfo()
+			Expect(info["PUT http://localhost:9200/xjoinindexpipeline.test-index-pipeline.1234"]).To(Equal(1))
+
+			deploymentName := "xjoin-core-xjoinindexpipeline-test-index-pipeline-1234"
+			deployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), types.NamespacedName{Name: deploymentName, Namespace: namespace}, deployment)
+			checkError(err)
+			for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+				if env.Name == "SINK_SCHEMA" {
+					Expect(env.Value).To(ContainSubstring(`"name":"ids"`))
+				}
+			}
+
+			validator := &v1alpha1.XJoinIndexValidator{}
+			validatorLookupKey := types.NamespacedName{Name: "xjoinindexpipeline.test-index-pipeline.1234", Namespace: namespace}
+			err = k8sClient.Get(context.Background(), validatorLookupKey, validator)
+			checkError(err)
+			Expect(validator.Spec.AvroSchema).To(Equal(indexPipeline.Spec.AvroSchema))
+
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("deployment " + deploymentName))
+			Expect(condition.Message).ToNot(ContainSubstring("elasticsearch index"))
+		})
+
+		It("Should set the SchemaEvolved condition to false when the components cannot be evolved", func() {
+			dataSourceName := "testdatasource"
+			datasourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      dataSourceName,
+				K8sClient: k8sClient,
+			}
+			datasourceReconciler.ReconcileNew()
+			createdDataSource := datasourceReconciler.ReconcileValid()
+
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:           namespace,
+				Name:                "test-index-pipeline",
+				ConfigFileName:      "xjoinindex-with-referenced-field",
+				K8sClient:           k8sClient,
+				RejectMappingUpdate: true,
+				DataSources: []DataSource{{
+					Name:                     dataSourceName,
+					Version:                  createdDataSource.Status.ActiveVersion,
+					ApiCurioResponseFilename: "datasource-latest-version",
+				}},
+			}
+			createdIndexPipeline := reconciler.ReconcileNew()
+
+			indexPipeline, _ := reconciler.ReconcileAvroSchema("xjoinindex-with-added-field")
+			Expect(indexPipeline.Status.AvroSchemaHash).To(Equal(createdIndexPipeline.Status.AvroSchemaHash))
+
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.SchemaEvolvedConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("EvolutionFailed"))
+		})
+	})
+
+	Context("Reconcile Component Deviations", func() {
+		It("Should set the ComponentsHealthy condition to false when the xjoin-core deployment is modified", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			deploymentName := "xjoin-core-xjoinindexpipeline-test-index-pipeline-1234"
+			deploymentLookupKey := types.NamespacedName{Name: deploymentName, Namespace: namespace}
+			deployment := &v1.Deployment{}
+			err := k8sClient.Get(context.Background(), deploymentLookupKey, deployment)
+			checkError(err)
+			deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/cloudservices/xjoin-core:modified"
+			err = k8sClient.Update(context.Background(), deployment)
+			checkError(err)
+
+			indexPipeline := reconciler.ReconcileExisting()
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring(
+				"deployment " + deploymentName + " container " + deploymentName +
+					" image changed from quay.io/cloudservices/xjoin-core:latest to quay.io/cloudservices/xjoin-core:modified"))
+		})
+
+		It("Should not include the connector's credentials when the Elasticsearch connector is modified", func() {
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+			}
+			reconciler.ReconcileNew()
+
+			connectorName := "xjoinindexpipeline.test-index-pipeline.1234"
+			connectorLookupKey := types.NamespacedName{Name: connectorName, Namespace: namespace}
+			connector := &v1beta2.KafkaConnector{}
+			err := k8sClient.Get(context.Background(), connectorLookupKey, connector)
+			checkError(err)
+			var config map[string]interface{}
+			err = json.Unmarshal(connector.Spec.Config.Raw, &config)
+			checkError(err)
+			config["connection.password"] = "modified-password"
+			connector.Spec.Config.Raw, err = json.Marshal(config)
+			checkError(err)
+			err = k8sClient.Update(context.Background(), connector)
+			checkError(err)
+
+			indexPipeline := reconciler.ReconcileExisting()
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring(
+				"connector " + connectorName + " configuration has changed: connection.password"))
+			Expect(condition.Message).ToNot(ContainSubstring("modified-password"))
+			Expect(condition.Message).ToNot(ContainSubstring("xjoin1337"))
+		})
+
+		It("Should set the ComponentsHealthy condition to false when the Elasticsearch pipeline is modified", func() {
+			dataSourceName := "testdatasource"
+			datasourceReconciler := DatasourceTestReconciler{
+				Namespace: namespace,
+				Name:      dataSourceName,
+				K8sClient: k8sClient,
+			}
+			datasourceReconciler.ReconcileNew()
+			createdDataSource := datasourceReconciler.ReconcileValid()
+
+			reconciler := XJoinIndexPipelineTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-pipeline",
+				ConfigFileName: "xjoinindex-with-json-field",
+				K8sClient:      k8sClient,
+				DataSources: []DataSource{{
+					Name:                     dataSourceName,
+					Version:                  createdDataSource.Status.ActiveVersion,
+					ApiCurioResponseFilename: "datasource-latest-version",
+				}},
+			}
+			indexPipeline := reconciler.ReconcileNew()
+			condition := meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Message).ToNot(ContainSubstring("elasticsearch pipeline"))
+
+			reconciler.SetElasticsearchPipeline(`{"description":"test","processors":[]}`)
+
+			indexPipeline = reconciler.ReconcileExisting()
+			condition = meta.FindStatusCondition(indexPipeline.Status.Conditions, v1alpha1.ComponentsHealthyConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Message).To(ContainSubstring(
+				"elasticsearch pipeline xjoinindexpipeline.test-index-pipeline.1234 processors have changed for fields: " +
+					".facts"))
+			Expect(condition.Message).ToNot(ContainSubstring("ctx."))
+		})
+	})
+
 	Context("Reconcile Deletion", func() {
 		It("Should delete the Elasticsearch index", func() {
 			name := "test-index-pipeline"
@@ -2,15 +2,18 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/gomega"
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"io"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	"net/http"
 	"os"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,9 +25,13 @@ type XJoinIndexPipelineTestReconciler struct {
 	Name                 string
 	ConfigFileName       string
 	CustomSubgraphImages []v1alpha1.CustomSubgraphImage
+	Workloads            *v1alpha1.XJoinIndexWorkloads
 	K8sClient            client.Client
 	DataSources          []DataSource
+	RejectMappingUpdate  bool //Elasticsearch responds to a put mapping request with an error
 	createdIndexPipeline v1alpha1.XJoinIndexPipeline
+	esPipeline           string
+	esIndex              string
 }
 
 type DataSource struct {
@@ -46,6 +53,98 @@ func (x *XJoinIndexPipelineTestReconciler) ReconcileNew() v1alpha1.XJoinIndexPip
 	return x.createdIndexPipeline
 }
 
+// ReconcileNewWithError reconciles a new XJoinIndexPipeline whose reconciliation is expected to fail
+func (x *XJoinIndexPipelineTestReconciler) ReconcileNewWithError() error {
+	x.registerNewMocks()
+	x.createValidIndexPipeline()
+	indexLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
+	_, err := x.newXJoinIndexPipelineReconciler().Reconcile(
+		context.Background(), ctrl.Request{NamespacedName: indexLookupKey})
+	return err
+}
+
+// ElasticsearchIndex is the body of the request which created the Elasticsearch index
+func (x *XJoinIndexPipelineTestReconciler) ElasticsearchIndex() string {
+	return x.esIndex
+}
+
+// ReconcileExisting reconciles an XJoinIndexPipeline that was created by ReconcileNew without resetting the mocks
+func (x *XJoinIndexPipelineTestReconciler) ReconcileExisting() v1alpha1.XJoinIndexPipeline {
+	result := x.reconcile()
+	Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 30000000000}))
+	indexLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
+	err := x.K8sClient.Get(context.Background(), indexLookupKey, &x.createdIndexPipeline)
+	checkError(err)
+	return x.createdIndexPipeline
+}
+
+// ReconcileWorkloads updates the workloads of the XJoinIndexPipeline created by ReconcileNew the same way the
+// XJoinIndex does and reconciles it
+func (x *XJoinIndexPipelineTestReconciler) ReconcileWorkloads(workloads *v1alpha1.XJoinIndexWorkloads) v1alpha1.XJoinIndexPipeline {
+	indexPipeline := &v1alpha1.XJoinIndexPipeline{}
+	indexLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
+	err := x.K8sClient.Get(context.Background(), indexLookupKey, indexPipeline)
+	checkError(err)
+
+	indexPipeline.Spec.Workloads = workloads
+	err = x.K8sClient.Update(context.Background(), indexPipeline)
+	checkError(err)
+
+	return x.ReconcileExisting()
+}
+
+// SetElasticsearchPipeline mimics the Elasticsearch pipeline being modified outside the operator
+func (x *XJoinIndexPipelineTestReconciler) SetElasticsearchPipeline(esPipeline string) {
+	x.esPipeline = esPipeline
+}
+
+// ReconcileAvroSchema updates the avroSchema of the XJoinIndexPipeline created by ReconcileNew the same way the
+// XJoinIndex does for an additive change. It returns the mapping properties put on the existing Elasticsearch index.
+func (x *XJoinIndexPipelineTestReconciler) ReconcileAvroSchema(configFileName string) (v1alpha1.XJoinIndexPipeline, string) {
+	indexAvroSchema, err := os.ReadFile("./test/data/avro/" + configFileName + ".json")
+	checkError(err)
+
+	indexPipeline := &v1alpha1.XJoinIndexPipeline{}
+	indexLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
+	err = x.K8sClient.Get(context.Background(), indexLookupKey, indexPipeline)
+	checkError(err)
+	indexPipeline.Spec.AvroSchema = string(indexAvroSchema)
+	err = x.K8sClient.Update(context.Background(), indexPipeline)
+	checkError(err)
+
+	//the Elasticsearch index exists now, it stores the mapping properties put on it
+	esIndex := "http://localhost:9200/xjoinindexpipeline." + x.Name + ".1234"
+	httpmock.RegisterResponder("HEAD", esIndex, httpmock.NewStringResponder(200, `{}`))
+
+	var properties string
+	httpmock.RegisterResponder(
+		"PUT",
+		esIndex+"/_mapping",
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			checkError(err)
+			if x.RejectMappingUpdate {
+				return httpmock.NewStringResponse(400, `{"error":{"type":"illegal_argument_exception"}}`), nil
+			}
+			var mapping map[string]json.RawMessage
+			checkError(json.Unmarshal(body, &mapping))
+			properties = string(mapping["properties"])
+			return httpmock.NewStringResponse(200, `{"acknowledged":true}`), nil
+		})
+	httpmock.RegisterResponder(
+		"GET",
+		esIndex+"/_mapping",
+		func(req *http.Request) (*http.Response, error) {
+			if properties == "" {
+				return httpmock.NewStringResponse(200, `{"xjoinindexpipeline.`+x.Name+`.1234":{"mappings":{}}}`), nil
+			}
+			return httpmock.NewStringResponse(200,
+				`{"xjoinindexpipeline.`+x.Name+`.1234":{"mappings":{"properties":`+properties+`}}}`), nil
+		})
+
+	return x.ReconcileExisting(), properties
+}
+
 func (x *XJoinIndexPipelineTestReconciler) ReconcileDelete() {
 	x.registerDeleteMocks()
 	result := x.reconcile()
@@ -163,10 +262,16 @@ func (x *XJoinIndexPipelineTestReconciler) registerNewMocks() {
 		"http://localhost:9200/xjoinindexpipeline."+x.Name+".1234",
 		httpmock.NewStringResponder(404, `{}`))
 
+	//stores the body of the create index request so the mapping can be inspected
 	httpmock.RegisterResponder(
 		"PUT",
 		"http://localhost:9200/xjoinindexpipeline."+x.Name+".1234",
-		httpmock.NewStringResponder(201, `{}`))
+		func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			checkError(err)
+			x.esIndex = string(body)
+			return httpmock.NewStringResponse(201, `{}`), nil
+		})
 
 	//avro schema mocks
 	httpmock.RegisterResponder(
@@ -226,15 +331,27 @@ func (x *XJoinIndexPipelineTestReconciler) registerNewMocks() {
 			"http://apicurio:1080/apis/ccompat/v6/subjects/xjoindatasourcepipeline."+dataSource.Name+"."+dataSource.Version+"-value/versions/latest",
 			httpmock.NewStringResponder(200, string(response)))
 
+		//stores the es pipeline on PUT so it can be returned by subsequent GETs
 		httpmock.RegisterResponder(
 			"GET",
 			"http://localhost:9200/_ingest/pipeline/xjoinindexpipeline.test-index-pipeline.1234",
-			httpmock.NewStringResponder(404, "{}").Once())
+			func(req *http.Request) (*http.Response, error) {
+				if x.esPipeline == "" {
+					return httpmock.NewStringResponse(404, "{}"), nil
+				}
+				return httpmock.NewStringResponse(
+					200, `{"xjoinindexpipeline.test-index-pipeline.1234":`+x.esPipeline+`}`), nil
+			})
 
 		httpmock.RegisterResponder(
 			"PUT",
 			"http://localhost:9200/_ingest/pipeline/xjoinindexpipeline.test-index-pipeline.1234",
-			httpmock.NewStringResponder(200, "{}"))
+			func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				checkError(err)
+				x.esPipeline = string(body)
+				return httpmock.NewStringResponse(200, "{}"), nil
+			})
 	}
 
 	httpmock.RegisterResponder(
@@ -287,6 +404,7 @@ func (x *XJoinIndexPipelineTestReconciler) createValidIndexPipeline() {
 		AvroSchema:           string(indexAvroSchema),
 		Pause:                false,
 		CustomSubgraphImages: x.CustomSubgraphImages,
+		Workloads:            x.Workloads,
 	}
 
 	blockOwnerDeletion := true
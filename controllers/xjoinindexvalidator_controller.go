@@ -12,12 +12,14 @@ import (
 	xjoinlogger "github.com/redhatinsights/xjoin-operator/controllers/log"
 	"github.com/redhatinsights/xjoin-operator/controllers/parameters"
 	k8sUtils "github.com/redhatinsights/xjoin-operator/controllers/utils"
+	batchv1 "k8s.io/api/batch/v1"
 	k8errors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -67,8 +69,8 @@ func (r *XJoinIndexValidatorReconciler) SetupWithManager(mgr ctrl.Manager) error
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("xjoin-indexvalidator-controller").
-		For(&xjoin.XJoinIndexValidator{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		For(&xjoin.XJoinIndexValidator{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&batchv1.Job{}, builder.WithPredicates(k8s.ValidationJobFinishedPredicate())).
 		WithLogConstructor(logConstructor).
 		WithOptions(controller.Options{
 			LogConstructor: logConstructor,
@@ -79,6 +81,8 @@ func (r *XJoinIndexValidatorReconciler) SetupWithManager(mgr ctrl.Manager) error
 
 // +kubebuilder:rbac:groups=xjoin.cloud.redhat.com,resources=xjoinindexvalidators;xjoinindexvalidators/status;xjoinindexvalidators/finalizers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps;pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=create;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 
 func (r *XJoinIndexValidatorReconciler) Reconcile(ctx context.Context, request ctrl.Request) (result ctrl.Result, err error) {
 	reqLogger := xjoinlogger.NewLogger("controller_xjoinindexvalidator", "IndexValidator", request.Name, "Namespace", request.Namespace)
@@ -142,16 +146,18 @@ func (r *XJoinIndexValidatorReconciler) Reconcile(ctx context.Context, request c
 		if err != nil {
 			return result, errors.Wrap(err, 0)
 		}
+		return result, nil
 	}
 
-	phase, err := i.ReconcileValidationPod()
+	phase, err := i.ReconcileValidationJob()
 	if err != nil {
 		return result, errors.Wrap(err, 0)
 	}
 	instance.Status.ValidationPodPhase = phase
-	if phase == ValidatorPodSuccess {
-		return i.UpdateStatusAndRequeue(time.Second * time.Duration(p.ValidationInterval.Int()))
+	if phase == ValidatorPodRunning {
+		//the finished job triggers the next reconcile
+		return i.UpdateStatusAndRequeue(0)
 	} else {
-		return i.UpdateStatusAndRequeue(time.Second * time.Duration(p.ValidationPodStatusInterval.Int()))
+		return i.UpdateStatusAndRequeue(time.Second * time.Duration(p.ValidationInterval.Int()))
 	}
 }
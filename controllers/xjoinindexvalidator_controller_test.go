@@ -1,15 +1,27 @@
 package controllers_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	validation "github.com/redhatinsights/xjoin-go-lib/pkg/validation"
+	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/database"
 	"github.com/redhatinsights/xjoin-operator/controllers/index"
+	"github.com/redhatinsights/xjoin-operator/controllers/k8s"
 	"github.com/redhatinsights/xjoin-operator/controllers/k8s/mocks"
+	"github.com/riferrei/srclient"
+	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"strings"
 	"time"
 	//+kubebuilder:scaffold:imports
 )
@@ -44,7 +56,7 @@ var _ = Describe("XJoinIndexValidator", func() {
 			Expect(createdIndexValidator.Finalizers).To(ContainElement(index.XJoinIndexValidatorFinalizer))
 		})
 
-		It("Should create an xjoin-validation pod", func() {
+		It("Should create an xjoin-validation job", func() {
 			configFileName := "xjoinindex-with-referenced-field"
 			name := "test-index-validator"
 
@@ -57,18 +69,28 @@ var _ = Describe("XJoinIndexValidator", func() {
 			}
 			reconciler.ReconcileCreate()
 
-			pods := reconciler.ListValidatorPods()
-			Expect(len(pods.Items)).To(Equal(1))
-			pod := pods.Items[0]
+			jobs := reconciler.ListValidatorJobs()
+			Expect(len(jobs.Items)).To(Equal(1))
+			job := jobs.Items[0]
 
-			Expect(pod.Name).To(Equal("xjoin-validation-" + name))
-			Expect(pod.Status.Phase).To(Equal(corev1.PodPending))
-			Expect(pod.ObjectMeta.Labels).To(Equal(map[string]string{
+			Expect(job.Name).To(HavePrefix("xjoin-validation-" + name + "-"))
+			Expect(job.ObjectMeta.Labels).To(Equal(map[string]string{
 				common.COMPONENT_NAME_LABEL: "XJoinIndexValidator",
 				"xjoin.index":               name,
 			}))
+			Expect(job.OwnerReferences).To(HaveLen(1))
+			Expect(job.OwnerReferences[0].Kind).To(Equal("XJoinIndexValidator"))
+			Expect(job.OwnerReferences[0].Name).To(Equal(name))
+			Expect(*job.OwnerReferences[0].Controller).To(BeTrue())
 
+			Expect(*job.Spec.ActiveDeadlineSeconds).To(Equal(int64(1800)))
+			Expect(*job.Spec.BackoffLimit).To(Equal(int32(0)))
+			Expect(*job.Spec.TTLSecondsAfterFinished).To(Equal(int32(3600)))
+
+			pod := job.Spec.Template
 			Expect(pod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(pod.Spec.Containers[0].TerminationMessagePolicy).To(
+				Equal(corev1.TerminationMessageFallbackToLogsOnError))
 
 			Expect(pod.Spec.Containers).To(HaveLen(1))
 			Expect(pod.Spec.Containers[0].Name).To(Equal("xjoin-validation-" + name))
@@ -166,7 +188,7 @@ var _ = Describe("XJoinIndexValidator", func() {
 			}))
 		})
 
-		It("Should requeue after ValidationPodStatusInterval", func() {
+		It("Should not requeue while the job is running", func() {
 			reconciler := XJoinIndexValidatorTestReconciler{
 				Namespace:      namespace,
 				Name:           "test-index-validator",
@@ -175,12 +197,12 @@ var _ = Describe("XJoinIndexValidator", func() {
 				PodLogReader:   &mocks.LogReader{},
 			}
 			_, result := reconciler.ReconcileCreate()
-			Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 5 * time.Second}))
+			Expect(result).To(Equal(reconcile.Result{}))
 		})
 	})
 
-	Context("Reconcile Pod Running", func() {
-		It("Should requeue after ValidationPodStatusInterval", func() {
+	Context("Reconcile Job Running", func() {
+		It("Should not requeue while the job is running", func() {
 			reconciler := XJoinIndexValidatorTestReconciler{
 				Namespace:      namespace,
 				Name:           "test-index-validator",
@@ -189,11 +211,11 @@ var _ = Describe("XJoinIndexValidator", func() {
 				PodLogReader:   &mocks.LogReader{},
 			}
 			validator, result := reconciler.ReconcileRunning()
-			Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 5 * time.Second}))
+			Expect(result).To(Equal(reconcile.Result{}))
 			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodRunning))
 		})
 
-		It("Should NOT create more pods while a pod is already running", func() {
+		It("Should NOT create more jobs while a job is already running", func() {
 			reconciler := XJoinIndexValidatorTestReconciler{
 				Namespace:      namespace,
 				Name:           "test-index-validator",
@@ -204,12 +226,12 @@ var _ = Describe("XJoinIndexValidator", func() {
 			validator, _ := reconciler.ReconcileRunning()
 			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodRunning))
 
-			pods := reconciler.ListValidatorPods()
-			Expect(len(pods.Items)).To(Equal(1))
+			jobs := reconciler.ListValidatorJobs()
+			Expect(len(jobs.Items)).To(Equal(1))
 		})
 	})
 
-	Context("Reconcile Pod Success", func() {
+	Context("Reconcile Job Success", func() {
 		It("Should requeue after ValidationInterval", func() {
 			name := "test-index-validator"
 
@@ -218,7 +240,7 @@ var _ = Describe("XJoinIndexValidator", func() {
 
 			podLogReader := mocks.LogReader{}
 			podLogReader.
-				On("GetLogs", "xjoin-validation-"+name, namespace).Return(string(logBytes), err)
+				On("GetLogs", mock.Anything, namespace).Return(string(logBytes), err)
 
 			reconciler := XJoinIndexValidatorTestReconciler{
 				Namespace:      namespace,
@@ -227,19 +249,415 @@ var _ = Describe("XJoinIndexValidator", func() {
 				K8sClient:      k8sClient,
 				PodLogReader:   &podLogReader,
 			}
-			validator, result := reconciler.ReconcileSuccess()
+			validator, result := reconciler.ReconcileSuccess("")
 			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodSuccess))
 			Expect(result).To(Equal(reconcile.Result{Requeue: false, RequeueAfter: 60 * time.Second}))
 		})
 
-		It("Should delete the validator pod", func() {
+		It("Should read the result from the termination message", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			validator, _ := reconciler.ReconcileSuccess(
+				`{"result":"invalid","reason":"count mismatch","message":"1 record is missing","details":{}}`)
+			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodSuccess))
+
+			condition := meta.FindStatusCondition(validator.Status.Conditions, v1alpha1.ValidationResultConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ResultParsed"))
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.ValidationResponse.Result).To(Equal("invalid"))
+			Expect(pipeline.Status.ValidationResponse.Reason).To(Equal("count mismatch"))
+			Expect(pipeline.Status.ValidationFailedCount).To(Equal(1))
+		})
+
+		It("Should not count a validation whose mismatches are within the percentage threshold as failed", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+				DocumentCount:  100,
+			}
+			reconciler.ReconcileSuccess(
+				`{"result":"invalid","reason":"","message":"","details":{"totalMismatch":4}}`)
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.ValidationResponse.Result).To(Equal("invalid"))
+			Expect(pipeline.Status.ValidationFailedCount).To(Equal(0))
+		})
+
+		It("Should count a validation whose mismatches exceed the percentage threshold as failed", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+				DocumentCount:  100,
+			}
+			reconciler.ReconcileSuccess(
+				`{"result":"invalid","reason":"","message":"","details":{"totalMismatch":6}}`)
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.ValidationFailedCount).To(Equal(1))
+		})
+
+		It("Should not count a failed validation while a datasource re-emits its records", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:          namespace,
+				Name:               "test-index-validator",
+				ConfigFileName:     "xjoinindex-with-referenced-field",
+				K8sClient:          k8sClient,
+				PodLogReader:       &mocks.LogReader{},
+				DocumentCount:      100,
+				DataSourceSnapshot: v1alpha1.IncrementalSnapshotRunning,
+			}
+			reconciler.ReconcileSuccess(
+				`{"result":"invalid","reason":"","message":"","details":{"totalMismatch":50}}`)
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.ValidationResponse.Result).To(Equal("invalid"))
+			Expect(pipeline.Status.ValidationFailedCount).To(Equal(0))
+		})
+
+		It("Should ignore log lines after the result", func() {
+			name := "test-index-validator"
+
+			logBytes, err := os.ReadFile("./test/data/validator/success-trailing.log.txt")
+			checkError(err)
+
+			podLogReader := mocks.LogReader{}
+			podLogReader.
+				On("GetLogs", mock.Anything, namespace).Return(string(logBytes), err)
+
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           name,
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &podLogReader,
+			}
+			validator, _ := reconciler.ReconcileSuccess("")
+			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodSuccess))
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.ValidationResponse.Result).To(Equal("success"))
+		})
+
+		It("Should keep the recorded validator job until its TTL passes", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			reconciler.ReconcileSuccess(`{"result":"success","reason":"","message":"","details":{}}`)
+			jobs := reconciler.ListValidatorJobs()
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Annotations).To(HaveKeyWithValue(k8s.ValidationRecordedAnnotation, "true"))
+		})
+
+		It("Should start a new job for the next validation", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			reconciler.ReconcileSuccess(`{"result":"invalid","reason":"","message":"first","details":{}}`)
+			validator, _ := reconciler.ReconcileNextSuccess(`{"result":"valid","reason":"","message":"second","details":{}}`)
+			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodSuccess))
+			Expect(reconciler.ListValidatorJobs().Items).To(HaveLen(2))
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.ValidationResponse.Message).To(Equal("second"))
+			Expect(pipeline.Status.ValidationFailedCount).To(Equal(0))
+		})
 
+		It("Should set the ValidationResult condition to false when the result is malformed", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			validator, _ := reconciler.ReconcileSuccess("panic: unable to connect to elasticsearch")
+			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodFailed))
+
+			condition := meta.FindStatusCondition(validator.Status.Conditions, v1alpha1.ValidationResultConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("MalformedResult"))
+			Expect(condition.Message).To(ContainSubstring("panic: unable to connect to elasticsearch"))
+			jobs := reconciler.ListValidatorJobs()
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Annotations).To(HaveKey(k8s.ValidationRecordedAnnotation))
 		})
 	})
 
-	Context("Reconcile Pod Failure", func() {
-		It("Should requeue immediately", func() {
+	Context("Reconcile Validation Report", func() {
+		It("Should store the mismatched record ids in the validation report", func() {
+			SetGenericConfigValue(namespace, "validation.report.ids.max", "2")
+
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			reconciler.ReconcileSuccess(`{"result":"invalid","reason":"","message":"3 records are missing","details":{` +
+				`"idsMissingFromElasticsearch":["1","2","3"],"idsMissingFromElasticsearchCount":3,` +
+				`"idsOnlyInElasticsearch":["4"],"idsOnlyInElasticsearchCount":1}}`)
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.ValidationReport).To(Equal("xjoin-validation-report-test-index-pipeline"))
+
+			history := reconciler.GetValidationReport(pipeline.Status.ValidationReport)
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Result).To(Equal("invalid"))
+			Expect(history[0].Message).To(Equal("3 records are missing"))
+			Expect(history[0].Failed).To(BeTrue())
+			Expect(history[0].Version).To(Equal("1234"))
+			Expect(history[0].Details.IdsMissingFromElasticsearch).To(Equal([]string{"1", "2"}))
+			Expect(history[0].Details.IdsMissingFromElasticsearchCount).To(Equal(3))
+			Expect(history[0].Details.IdsOnlyInElasticsearch).To(Equal([]string{"4"}))
+
+			Expect(pipeline.Status.ValidationResponse.Details.IdsMissingFromElasticsearch).To(Equal([]string{"1", "2"}))
+			Expect(pipeline.Status.ValidationResponse.Details.IdsMissingFromElasticsearchCount).To(Equal(3))
+		})
+
+		It("Should only keep the configured number of validation runs", func() {
+			SetGenericConfigValue(namespace, "validation.report.history.size", "2")
+
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			reconciler.CreateValidationReport([]index.ValidationReportEntry{
+				{Version: "1234", ValidationResponse: validation.ValidationResponse{Result: "invalid", Message: "second"}},
+				{Version: "1234", ValidationResponse: validation.ValidationResponse{Result: "invalid", Message: "first"}},
+			})
+			reconciler.ReconcileSuccess(`{"result":"valid","reason":"","message":"third","details":{}}`)
+
+			history := reconciler.GetValidationReport("xjoin-validation-report-test-index-pipeline")
+			Expect(history).To(HaveLen(2))
+			Expect(history[0].Message).To(Equal("third"))
+			Expect(history[0].Failed).To(BeFalse())
+			Expect(history[1].Message).To(Equal("second"))
+		})
+
+		It("Should keep the latest validation run when the history size is not positive", func() {
+			SetGenericConfigValue(namespace, "validation.report.history.size", "0")
+
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			reconciler.CreateValidationReport([]index.ValidationReportEntry{
+				{Version: "1234", ValidationResponse: validation.ValidationResponse{Result: "invalid", Message: "first"}},
+			})
+			reconciler.ReconcileSuccess(`{"result":"valid","reason":"","message":"second","details":{}}`)
+
+			history := reconciler.GetValidationReport("xjoin-validation-report-test-index-pipeline")
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Message).To(Equal("second"))
+		})
+
+		It("Should drop the oldest validation runs that don't fit in the ConfigMap", func() {
+			var ids []string
+			for id := 0; id < 24000; id++ {
+				ids = append(ids, fmt.Sprintf("00000000-0000-0000-0000-%012d", id))
+			}
+
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			reconciler.CreateValidationReport([]index.ValidationReportEntry{{
+				Version: "1234",
+				ValidationResponse: validation.ValidationResponse{
+					Result:  "invalid",
+					Message: "first",
+					Details: validation.ResponseDetails{IdsMissingFromElasticsearch: ids},
+				},
+			}})
+			reconciler.ReconcileSuccess(`{"result":"valid","reason":"","message":"second","details":{}}`)
+
+			configMap := &corev1.ConfigMap{}
+			k8sGet(types.NamespacedName{Name: "xjoin-validation-report-test-index-pipeline", Namespace: namespace}, configMap)
+			Expect(len(configMap.Data[index.ValidationReportHistoryKey])).To(BeNumerically("<=", index.ValidationReportMaxBytes))
+
+			history := reconciler.GetValidationReport("xjoin-validation-report-test-index-pipeline")
+			Expect(history).To(HaveLen(1))
+			Expect(history[0].Message).To(Equal("second"))
+		})
+	})
+
+	Context("Reconcile Validation Repair", func() {
+		It("Should not repair mismatched records by default", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+				DocumentCount:  100,
+			}
+			reconciler.ReconcileSuccess(
+				`{"result":"invalid","details":{"totalMismatch":1,"idsMissingFromElasticsearch":["1"]}}`)
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.LastValidationRepair).To(BeNil())
+			history := reconciler.GetValidationReport(pipeline.Status.ValidationReport)
+			Expect(history[0].Repair).To(BeNil())
+		})
+
+		It("Should skip datasources without a signal table", func() {
+			SetGenericConfigValue(namespace, "validation.repair.percentage.threshold", "5")
+
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex-with-referenced-field",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+				DocumentCount:  100,
+			}
+			reconciler.ReconcileSuccess(
+				`{"result":"invalid","details":{"totalMismatch":1,"idsMissingFromElasticsearch":["1"]}}`)
+
+			pipeline := reconciler.GetIndexPipeline()
+			Expect(pipeline.Status.LastValidationRepair).To(BeNil())
+			history := reconciler.GetValidationReport(pipeline.Status.ValidationReport)
+			Expect(history[0].Repair).To(BeNil())
+			Expect(history[0].Failed).To(BeFalse())
+		})
+
+		It("Should split the repaired ids into signals that fit the signal table", func() {
+			var ids []string
+			for i := 0; i < 100; i++ {
+				ids = append(ids, fmt.Sprintf("00000000-0000-0000-0000-%012d", i))
+			}
+
+			conditions, err := database.IdsSignalConditions("public.hosts", "deleted_at IS NULL", "id", ids)
+			checkError(err)
+			Expect(len(conditions)).To(BeNumerically(">", 1))
+
+			var signaledIds int
+			for _, condition := range conditions {
+				Expect(condition).To(HavePrefix(`(deleted_at IS NULL) AND "id" IN (`))
+				signaledIds += strings.Count(condition, "'") / 2
+
+				signal, err := json.Marshal(map[string]interface{}{
+					"type":             "incremental",
+					"data-collections": []string{"public.hosts"},
+					"additional-conditions": []map[string]string{{
+						"data-collection": "public.hosts",
+						"filter":          condition,
+					}},
+				})
+				checkError(err)
+				Expect(len(signal)).To(BeNumerically("<=", database.SignalDataMaxLength))
+			}
+			Expect(signaledIds).To(Equal(len(ids)))
+		})
+
+		It("Should fail when a single id does not fit the signal table", func() {
+			_, err := database.IdsSignalConditions(
+				"public.hosts", "", "id", []string{strings.Repeat("a", database.SignalDataMaxLength)})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should repair the ids of the datasource's primary key field", func() {
+			column, err := index.PrimaryKeyColumn(`{
+				"type": "record",
+				"name": "Value",
+				"fields": [
+					{"name": "name", "type": {"type": "string"}},
+					{"name": "host_id", "type": {"type": "string", "xjoin.primary.key": true}}
+				]
+			}`, "id")
+			checkError(err)
+			Expect(column).To(Equal("host_id"))
+
+			column, err = index.PrimaryKeyColumn(
+				`{"type": "record", "name": "Value", "fields": [{"name": "name", "type": {"type": "string"}}]}`, "id")
+			checkError(err)
+			Expect(column).To(Equal("id"))
+		})
+	})
+
+	Context("Reconcile Job Failure", func() {
+		It("Should set the ValidationResult condition to false", func() {
+			reconciler := XJoinIndexValidatorTestReconciler{
+				Namespace:      namespace,
+				Name:           "test-index-validator",
+				ConfigFileName: "xjoinindex",
+				K8sClient:      k8sClient,
+				PodLogReader:   &mocks.LogReader{},
+			}
+			validator, _ := reconciler.ReconcileFailure()
+			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodFailed))
+
+			condition := meta.FindStatusCondition(validator.Status.Conditions, v1alpha1.ValidationResultConditionType)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("JobFailed"))
+			Expect(condition.Message).To(ContainSubstring("DeadlineExceeded"))
+
+			//the failed job is kept for inspection until its TTL passes
+			jobs := reconciler.ListValidatorJobs()
+			Expect(jobs.Items).To(HaveLen(1))
+			Expect(jobs.Items[0].Annotations).To(HaveKey(k8s.ValidationRecordedAnnotation))
+			Expect(condition.Message).To(ContainSubstring(jobs.Items[0].Name))
+			Expect(validator.Status.ValidationPodPhase).To(Equal(index.ValidatorPodFailed))
+		})
+	})
+
+	Context("Datasource References", func() {
+		It("Should read the datasource of a reference from its subject", func() {
+			reference := srclient.Reference{
+				Name:    "xjoindatasourcepipeline.test.hosts.Value",
+				Subject: "xjoindatasourcepipeline.test.hosts.1234-value",
+			}
+			dataSourcePipelineName, err := index.DataSourcePipelineName(reference)
+			checkError(err)
+			Expect(dataSourcePipelineName).To(Equal("test.hosts.1234"))
+			dataSourceName, err := index.DataSourceName(reference)
+			checkError(err)
+			Expect(dataSourceName).To(Equal("test.hosts"))
+		})
+
+		It("Should return an error for a reference that is not a datasource pipeline", func() {
+			reference := srclient.Reference{Name: "hosts.Value", Subject: "hosts-value"}
+			_, err := index.DataSourcePipelineName(reference)
+			Expect(err).To(HaveOccurred())
+			_, err = index.DataSourceName(reference)
+			Expect(err).To(HaveOccurred())
 
+			_, err = index.DataSourceName(srclient.Reference{Subject: "xjoindatasourcepipeline.hosts-value"})
+			Expect(err).To(HaveOccurred())
 		})
 	})
 })
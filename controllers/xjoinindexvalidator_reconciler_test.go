@@ -2,13 +2,16 @@ package controllers_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/jarcoal/httpmock"
 	. "github.com/onsi/gomega"
 	"github.com/redhatinsights/xjoin-operator/api/v1alpha1"
 	"github.com/redhatinsights/xjoin-operator/controllers"
 	"github.com/redhatinsights/xjoin-operator/controllers/common"
+	"github.com/redhatinsights/xjoin-operator/controllers/index"
 	"github.com/redhatinsights/xjoin-operator/controllers/k8s"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,6 +22,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
 )
 
 type XJoinIndexValidatorTestReconciler struct {
@@ -28,6 +32,8 @@ type XJoinIndexValidatorTestReconciler struct {
 	ConfigFileName        string
 	createdIndexValidator v1alpha1.XJoinIndexValidator
 	PodLogReader          k8s.LogReader
+	DocumentCount         int    //number of documents in the validated Elasticsearch index
+	DataSourceSnapshot    string //phase of an incremental snapshot of the datasource's active version
 }
 
 func (x *XJoinIndexValidatorTestReconciler) ReconcileCreate() (v1alpha1.XJoinIndexValidator, reconcile.Result) {
@@ -56,7 +62,10 @@ func (x *XJoinIndexValidatorTestReconciler) ReconcileRunning() (v1alpha1.XJoinIn
 	return x.createdIndexValidator, result
 }
 
-func (x *XJoinIndexValidatorTestReconciler) ReconcileSuccess() (validator v1alpha1.XJoinIndexValidator, result reconcile.Result) {
+// ReconcileSuccess completes the validation job with a pod whose container terminated with terminationMessage
+func (x *XJoinIndexValidatorTestReconciler) ReconcileSuccess(
+	terminationMessage string) (validator v1alpha1.XJoinIndexValidator, result reconcile.Result) {
+
 	x.createIndexValidator()
 	x.createDatasource()
 	x.reconcile()
@@ -66,14 +75,29 @@ func (x *XJoinIndexValidatorTestReconciler) ReconcileSuccess() (validator v1alph
 		return err == nil
 	}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
 
-	validatorPods := x.ListValidatorPods()
-	validatorPods.Items[0].Status.Phase = corev1.PodSucceeded
-	err := x.K8sClient.Status().Update(context.Background(), &validatorPods.Items[0])
-	newPods := x.ListValidatorPods()
-	newPods.Items[0].GetNamespace()
+	return x.ReconcileNextSuccess(terminationMessage)
+}
+
+// ReconcileNextSuccess starts the next validation job of the existing validator and completes it with a pod whose
+// container terminated with terminationMessage
+func (x *XJoinIndexValidatorTestReconciler) ReconcileNextSuccess(
+	terminationMessage string) (validator v1alpha1.XJoinIndexValidator, result reconcile.Result) {
+
+	httpmock.RegisterResponder(
+		"POST",
+		"http://localhost:9200/xjoinindexpipeline.test-index.1234/_count",
+		httpmock.NewStringResponder(200, fmt.Sprintf(`{"count":%d}`, x.DocumentCount)))
+
+	x.reconcile()
+	job := x.pendingValidatorJob()
+	x.createValidatorJobPod(job, terminationMessage)
+
+	job.Status.Succeeded = 1
+	err := x.K8sClient.Status().Update(context.Background(), &job)
 	checkError(err)
 
 	result = x.reconcile()
+	validatorLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
 	Eventually(func() bool {
 		err := x.K8sClient.Get(context.Background(), validatorLookupKey, &validator)
 		return err == nil
@@ -82,15 +106,114 @@ func (x *XJoinIndexValidatorTestReconciler) ReconcileSuccess() (validator v1alph
 	return
 }
 
-func (x *XJoinIndexValidatorTestReconciler) ListValidatorPods() *corev1.PodList {
+// ReconcileFailure marks the validation job as failed
+func (x *XJoinIndexValidatorTestReconciler) ReconcileFailure() (validator v1alpha1.XJoinIndexValidator, result reconcile.Result) {
+	x.createIndexValidator()
+	x.createDatasource()
+	x.reconcile()
+
+	job := x.pendingValidatorJob()
+	job.Status.Failed = 1
+	job.Status.Conditions = []batchv1.JobCondition{{
+		Type:    batchv1.JobFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  "DeadlineExceeded",
+		Message: "Job was active longer than specified deadline",
+	}}
+	err := x.K8sClient.Status().Update(context.Background(), &job)
+	checkError(err)
+
+	result = x.reconcile()
+	validatorLookupKey := types.NamespacedName{Name: x.Name, Namespace: x.Namespace}
+	Eventually(func() bool {
+		err := x.K8sClient.Get(context.Background(), validatorLookupKey, &validator)
+		return err == nil
+	}, K8sGetTimeout, K8sGetInterval).Should(BeTrue())
+
+	return
+}
+
+// createValidatorJobPod creates the succeeded pod the job controller would have created for the validation job
+func (x *XJoinIndexValidatorTestReconciler) createValidatorJobPod(job batchv1.Job, terminationMessage string) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name,
+			Namespace: x.Namespace,
+			Labels:    map[string]string{"job-name": job.Name},
+		},
+		Spec: job.Spec.Template.Spec,
+	}
+	Expect(x.K8sClient.Create(context.Background(), pod)).Should(Succeed())
+
+	pod.Status.Phase = corev1.PodSucceeded
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  job.Spec.Template.Spec.Containers[0].Name,
+		Image: job.Spec.Template.Spec.Containers[0].Image,
+		State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 0,
+				Reason:   "Completed",
+				Message:  terminationMessage,
+			},
+		},
+	}}
+	Expect(x.K8sClient.Status().Update(context.Background(), pod)).Should(Succeed())
+}
+
+func (x *XJoinIndexValidatorTestReconciler) ListValidatorJobs() *batchv1.JobList {
 	labels := client.MatchingLabels{}
 	labels["xjoin.index"] = x.Name
 	labels[common.COMPONENT_NAME_LABEL] = "XJoinIndexValidator"
 
-	pods := &corev1.PodList{}
-	err := k8sClient.List(context.Background(), pods, client.InNamespace(x.Namespace), labels)
+	jobs := &batchv1.JobList{}
+	err := k8sClient.List(context.Background(), jobs, client.InNamespace(x.Namespace), labels)
 	checkError(err)
-	return pods
+	return jobs
+}
+
+// pendingValidatorJob is the validation job whose result has not been recorded yet
+func (x *XJoinIndexValidatorTestReconciler) pendingValidatorJob() batchv1.Job {
+	var pending []batchv1.Job
+	for _, job := range x.ListValidatorJobs().Items {
+		if _, recorded := job.Annotations[k8s.ValidationRecordedAnnotation]; !recorded {
+			pending = append(pending, job)
+		}
+	}
+	Expect(pending).To(HaveLen(1))
+	return pending[0]
+}
+
+func (x *XJoinIndexValidatorTestReconciler) GetIndexPipeline() v1alpha1.XJoinIndexPipeline {
+	pipeline := v1alpha1.XJoinIndexPipeline{}
+	err := x.K8sClient.Get(
+		context.Background(), types.NamespacedName{Name: "test-index-pipeline", Namespace: x.Namespace}, &pipeline)
+	checkError(err)
+	return pipeline
+}
+
+// CreateValidationReport creates the validation report ConfigMap of the test-index-pipeline with an existing history
+func (x *XJoinIndexValidatorTestReconciler) CreateValidationReport(history []index.ValidationReportEntry) {
+	historyJson, err := json.Marshal(history)
+	checkError(err)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "xjoin-validation-report-test-index-pipeline",
+			Namespace: x.Namespace,
+		},
+		Data: map[string]string{index.ValidationReportHistoryKey: string(historyJson)},
+	}
+	Expect(x.K8sClient.Create(context.Background(), configMap)).Should(Succeed())
+}
+
+func (x *XJoinIndexValidatorTestReconciler) GetValidationReport(name string) []index.ValidationReportEntry {
+	configMap := &corev1.ConfigMap{}
+	err := x.K8sClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: x.Namespace}, configMap)
+	checkError(err)
+
+	history, err := index.ParseValidationReport(configMap)
+	checkError(err)
+	return history
 }
 
 func (x *XJoinIndexValidatorTestReconciler) createDatasource() {
@@ -100,14 +223,18 @@ func (x *XJoinIndexValidatorTestReconciler) createDatasource() {
 		K8sClient:          k8sClient,
 		AvroSchemaFileName: "xjoindatasource-single-field",
 	}
-	createdDataSource := reconciler.ReconcileNew()
+	reconciler.ReconcileNew()
+	createdDataSource := reconciler.ReconcileValid()
 	Expect(createdDataSource.Name).To(Equal("testdatasource"))
-	refreshingVersion := createdDataSource.Status.RefreshingVersion
+	if x.DataSourceSnapshot != "" {
+		reconciler.SetIncrementalSnapshot(x.DataSourceSnapshot, time.Now())
+	}
+	activeVersion := createdDataSource.Status.ActiveVersion
 	httpmock.RegisterResponder(
 		"GET",
-		"http://apicurio:1080/apis/ccompat/v6/subjects/xjoindatasourcepipeline.testdatasource."+refreshingVersion+"-value/versions/latest",
+		"http://apicurio:1080/apis/ccompat/v6/subjects/xjoindatasourcepipeline.testdatasource."+activeVersion+"-value/versions/latest",
 		httpmock.NewStringResponder(200, fmt.Sprintf(
-			`{"id": 1, "subject": "xjoindatasourcepipeline.testdatasource.%s-value", "version": 1, "schema": "%s", "references": []}`, refreshingVersion, "{}")))
+			`{"id": 1, "subject": "xjoindatasourcepipeline.testdatasource.%s-value", "version": 1, "schema": "%s", "references": []}`, activeVersion, "{}")))
 }
 
 func (x *XJoinIndexValidatorTestReconciler) createIndexValidator() {
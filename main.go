@@ -189,6 +189,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = controllers.NewXJoinDataSourceValidatorReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		clientset,
+		ctrl.Log.WithName("controllers").WithName("XJoinDataSourceValidator"),
+		mgr.GetEventRecorderFor("xjoindatasourcevalidator"),
+		namespace,
+		false,
+		k8s.PodLogReader{ClientSet: clientset},
+	).SetupWithManager(mgr); err != nil {
+		k8slog.Log.Error(err, "unable to create controller", "controller", "XJoinDataSourceValidator")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.XJoinPipelineReconciler{
 		Client:   mgr.GetClient(),
 		Log:      ctrl.Log.WithName("controllers").WithName("XJoinPipeline"),